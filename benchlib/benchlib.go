@@ -0,0 +1,63 @@
+// Package benchlib provides the timing and output helpers shared by the Go
+// benchmark programs under benchmarks/.
+//
+// Every benchmark reports the same lines on stdout:
+//
+//	STARTUP_TIME_US: <microseconds>
+//	STARTUP_TIME_NS: <nanoseconds>
+//	COMPUTE_TIME_US: <microseconds>
+//	COMPUTE_TIME_NS: <nanoseconds>
+//	RESULT: <integer>
+//
+// The Rust runner and the shell tooling parse the _US and RESULT lines, so
+// the format must not drift between benchmarks. Benchmarks that time parts
+// of their compute runs separately, such as parsing the input, register
+// them with Options.Phases; each phase adds a pair of lines such as
+// PARSE_TIME_US and PARSE_TIME_NS after the startup lines, and parsers
+// that only know the three standard keys skip them. ReportPhases prints
+// the same lines for a single parse phase outside Run.
+package benchlib
+
+import (
+	"fmt"
+	"time"
+)
+
+// now is the clock used for all measurements. Tests replace it with a
+// deterministic fake.
+var now = time.Now
+
+// Report prints the standardized benchmark output.
+//
+// Timings are kept at nanosecond precision. The _US lines are derived by
+// integer division of the nanosecond value by 1000, which truncates exactly
+// like the historical Duration.Microseconds output of the hand-rolled
+// benchmarks.
+func Report(startup, compute time.Duration, result int64) {
+	printPhase("STARTUP_TIME", startup)
+	printPhase("COMPUTE_TIME", compute)
+	fmt.Printf("RESULT: %d\n", result)
+}
+
+// ReportPhases is Report for benchmarks with three phases: startup, parsing
+// the input, and compute. The parse phase is printed as PARSE_TIME_US and
+// PARSE_TIME_NS between the startup and compute lines.
+func ReportPhases(startup, parse, compute time.Duration, result int64) {
+	printPhase("STARTUP_TIME", startup)
+	printPhase(Phase{Name: "parse"}.key(), parse)
+	printPhase("COMPUTE_TIME", compute)
+	fmt.Printf("RESULT: %d\n", result)
+}
+
+// printPhase prints the <prefix>_US and <prefix>_NS lines for d.
+func printPhase(prefix string, d time.Duration) {
+	fmt.Printf("%s_US: %d\n", prefix, d.Nanoseconds()/1000)
+	fmt.Printf("%s_NS: %d\n", prefix, d.Nanoseconds())
+}
+
+// Measure runs fn once and returns its wall-clock duration and result.
+func Measure(fn func() int64) (time.Duration, int64) {
+	t0 := now()
+	result := fn()
+	return now().Sub(t0), result
+}
@@ -0,0 +1,138 @@
+package benchlib
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// captureStdout runs fn with os.Stdout redirected to a pipe and returns
+// everything written to it.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	orig := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = orig }()
+
+	done := make(chan string)
+	go func() {
+		b, _ := io.ReadAll(r)
+		done <- string(b)
+	}()
+
+	fn()
+	w.Close()
+	return <-done
+}
+
+func TestReport(t *testing.T) {
+	got := captureStdout(t, func() {
+		Report(8234*time.Microsecond, 23891*time.Microsecond, 9227465)
+	})
+	want := "STARTUP_TIME_US: 8234\n" +
+		"STARTUP_TIME_NS: 8234000\n" +
+		"COMPUTE_TIME_US: 23891\n" +
+		"COMPUTE_TIME_NS: 23891000\n" +
+		"RESULT: 9227465\n"
+	if got != want {
+		t.Errorf("Report output = %q, want %q", got, want)
+	}
+}
+
+func TestReportTruncatesToMicroseconds(t *testing.T) {
+	// 1999ns and 2999999ns both truncate, never round up.
+	got := captureStdout(t, func() {
+		Report(1999*time.Nanosecond, 2999999*time.Nanosecond, -1)
+	})
+	want := "STARTUP_TIME_US: 1\n" +
+		"STARTUP_TIME_NS: 1999\n" +
+		"COMPUTE_TIME_US: 2999\n" +
+		"COMPUTE_TIME_NS: 2999999\n" +
+		"RESULT: -1\n"
+	if got != want {
+		t.Errorf("Report output = %q, want %q", got, want)
+	}
+}
+
+func TestReportPhases(t *testing.T) {
+	got := captureStdout(t, func() {
+		ReportPhases(8234*time.Microsecond, 1500999*time.Nanosecond, 23891*time.Microsecond, 9592)
+	})
+	want := "STARTUP_TIME_US: 8234\n" +
+		"STARTUP_TIME_NS: 8234000\n" +
+		"PARSE_TIME_US: 1500\n" +
+		"PARSE_TIME_NS: 1500999\n" +
+		"COMPUTE_TIME_US: 23891\n" +
+		"COMPUTE_TIME_NS: 23891000\n" +
+		"RESULT: 9592\n"
+	if got != want {
+		t.Errorf("ReportPhases output = %q, want %q", got, want)
+	}
+}
+
+func TestReportHasNoParsePhase(t *testing.T) {
+	got := captureStdout(t, func() {
+		Report(time.Millisecond, time.Millisecond, 1)
+	})
+	if strings.Contains(got, "PARSE_TIME") {
+		t.Errorf("Report printed a parse phase:\n%s", got)
+	}
+}
+
+// parseLines splits "KEY: value" output into a map of integer values.
+func parseLines(t *testing.T, out string) map[string]int64 {
+	t.Helper()
+	values := make(map[string]int64)
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			t.Fatalf("malformed line %q", line)
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			t.Fatalf("line %q: %v", line, err)
+		}
+		values[key] = n
+	}
+	return values
+}
+
+func TestReportMicrosecondsMatchNanoseconds(t *testing.T) {
+	for _, d := range []time.Duration{0, 999, 1000, 1001, 123456789, 3 * time.Hour} {
+		values := parseLines(t, captureStdout(t, func() { Report(d, d+500, 0) }))
+		for _, phase := range []string{"STARTUP", "COMPUTE"} {
+			us, okUS := values[phase+"_TIME_US"]
+			ns, okNS := values[phase+"_TIME_NS"]
+			if !okUS || !okNS {
+				t.Fatalf("d=%v: missing %s lines in %v", d, phase, values)
+			}
+			if us != ns/1000 {
+				t.Errorf("d=%v: %s_TIME_US = %d, want %d/1000 = %d", d, phase, us, ns, ns/1000)
+			}
+		}
+		if values["COMPUTE_TIME_NS"] != int64(d+500) {
+			t.Errorf("d=%v: COMPUTE_TIME_NS = %d, want %d", d, values["COMPUTE_TIME_NS"], int64(d+500))
+		}
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	d, result := Measure(func() int64 {
+		time.Sleep(time.Millisecond)
+		return 42
+	})
+	if result != 42 {
+		t.Errorf("result = %d, want 42", result)
+	}
+	if d < time.Millisecond {
+		t.Errorf("duration = %v, want >= 1ms", d)
+	}
+}
@@ -0,0 +1,134 @@
+package benchlib
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// cgroupRoot is where the cgroup filesystem is mounted. Tests point it at
+// a fake tree.
+var cgroupRoot = "/sys/fs/cgroup"
+
+// unlimited is reported for a limit that is absent or unreadable.
+const unlimited = "unlimited"
+
+// Limits describes the cgroup resource limits a benchmark ran under. It is
+// reported by the --cgroup-info flag, since results measured under
+// different CPU quotas or memory limits are not comparable.
+type Limits struct {
+	// Cgroup is the cgroup version the limits were read from: "v2", "v1",
+	// or "none" when no cgroup filesystem was found.
+	Cgroup string `json:"cgroup"`
+	// CPUs is the CPU quota as a number of CPUs, such as "1.5", or
+	// "unlimited".
+	CPUs string `json:"cpus"`
+	// MemoryBytes is the memory limit in bytes, or "unlimited".
+	MemoryBytes string `json:"memory_bytes"`
+}
+
+// CgroupInfo reads the CPU quota and memory limit of the cgroup at
+// /sys/fs/cgroup, which inside a container is the container's own. The v2
+// unified layout is tried first, then the v1 per-controller layout. Every
+// limit that cannot be read is reported as "unlimited", so CgroupInfo never
+// fails.
+func CgroupInfo() Limits {
+	return cgroupInfo(cgroupRoot)
+}
+
+func cgroupInfo(root string) Limits {
+	if _, err := os.Stat(filepath.Join(root, "cgroup.controllers")); err == nil {
+		return Limits{Cgroup: "v2", CPUs: cpusV2(root), MemoryBytes: memoryV2(root)}
+	}
+	for _, controller := range []string{"memory", "cpu", "cpu,cpuacct"} {
+		if _, err := os.Stat(filepath.Join(root, controller)); err == nil {
+			return Limits{Cgroup: "v1", CPUs: cpusV1(root), MemoryBytes: memoryV1(root)}
+		}
+	}
+	return Limits{Cgroup: "none", CPUs: unlimited, MemoryBytes: unlimited}
+}
+
+// readCgroupFile returns the trimmed contents of root/name, or "" if it
+// cannot be read.
+func readCgroupFile(root, name string) string {
+	data, err := os.ReadFile(filepath.Join(root, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// formatCPUs renders quota/period as a CPU count, or unlimited if either is
+// not a positive integer.
+func formatCPUs(quota, period string) string {
+	q, err1 := strconv.ParseInt(quota, 10, 64)
+	p, err2 := strconv.ParseInt(period, 10, 64)
+	if err1 != nil || err2 != nil || q <= 0 || p <= 0 {
+		return unlimited
+	}
+	return strconv.FormatFloat(float64(q)/float64(p), 'f', -1, 64)
+}
+
+// cpusV2 parses cpu.max, "<quota> <period>" with quota "max" when
+// unlimited.
+func cpusV2(root string) string {
+	quota, period, ok := strings.Cut(readCgroupFile(root, "cpu.max"), " ")
+	if !ok {
+		return unlimited
+	}
+	return formatCPUs(quota, period)
+}
+
+// memoryV2 parses memory.max, a byte count or "max".
+func memoryV2(root string) string {
+	return formatMemory(readCgroupFile(root, "memory.max"))
+}
+
+// cpusV1 parses cpu.cfs_quota_us (-1 when unlimited) and cpu.cfs_period_us
+// under the cpu controller, which some systems mount as cpu,cpuacct.
+func cpusV1(root string) string {
+	for _, dir := range []string{"cpu", "cpu,cpuacct"} {
+		quota := readCgroupFile(root, filepath.Join(dir, "cpu.cfs_quota_us"))
+		if quota != "" {
+			return formatCPUs(quota, readCgroupFile(root, filepath.Join(dir, "cpu.cfs_period_us")))
+		}
+	}
+	return unlimited
+}
+
+// v1NoLimit is the smallest memory.limit_in_bytes treated as unlimited. v1
+// has no "max" keyword; an unlimited cgroup reports a page-aligned value
+// near MaxInt64.
+const v1NoLimit = 1 << 62
+
+// memoryV1 parses memory/memory.limit_in_bytes.
+func memoryV1(root string) string {
+	s := readCgroupFile(root, filepath.Join("memory", "memory.limit_in_bytes"))
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil && n >= v1NoLimit {
+		return unlimited
+	}
+	return formatMemory(s)
+}
+
+// formatMemory returns s if it is a positive byte count and unlimited
+// otherwise.
+func formatMemory(s string) string {
+	if n, err := strconv.ParseInt(s, 10, 64); err != nil || n <= 0 {
+		return unlimited
+	}
+	return s
+}
+
+// printLimits writes the text-format LIMITS line: the Limits as a JSON
+// object.
+func printLimits(l Limits) {
+	data, err := json.Marshal(l)
+	if err != nil {
+		// Limits holds only strings, so this cannot happen.
+		panic(err)
+	}
+	fmt.Printf("LIMITS: %s\n", data)
+}
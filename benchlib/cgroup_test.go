@@ -0,0 +1,178 @@
+package benchlib
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeCgroup creates a cgroup tree under a temporary root with the given
+// files (path relative to the root → contents) and returns the root.
+func fakeCgroup(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, data := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestCgroupInfo(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  Limits
+	}{
+		{
+			name: "v2 limited",
+			files: map[string]string{
+				"cgroup.controllers": "cpuset cpu io memory pids\n",
+				"cpu.max":            "150000 100000\n",
+				"memory.max":         "536870912\n",
+			},
+			want: Limits{Cgroup: "v2", CPUs: "1.5", MemoryBytes: "536870912"},
+		},
+		{
+			name: "v2 unlimited",
+			files: map[string]string{
+				"cgroup.controllers": "cpu memory\n",
+				"cpu.max":            "max 100000\n",
+				"memory.max":         "max\n",
+			},
+			want: Limits{Cgroup: "v2", CPUs: unlimited, MemoryBytes: unlimited},
+		},
+		{
+			// The root cgroup of a v2 host has no cpu.max or memory.max.
+			name:  "v2 files absent",
+			files: map[string]string{"cgroup.controllers": "cpu memory\n"},
+			want:  Limits{Cgroup: "v2", CPUs: unlimited, MemoryBytes: unlimited},
+		},
+		{
+			name: "v1 limited",
+			files: map[string]string{
+				"cpu/cpu.cfs_quota_us":          "200000\n",
+				"cpu/cpu.cfs_period_us":         "100000\n",
+				"memory/memory.limit_in_bytes":  "268435456\n",
+				"cpu,cpuacct/cpu.cfs_period_us": "100000\n",
+				"cpu,cpuacct/cpu.cfs_quota_us":  "-1\n",
+			},
+			want: Limits{Cgroup: "v1", CPUs: "2", MemoryBytes: "268435456"},
+		},
+		{
+			name: "v1 unlimited",
+			files: map[string]string{
+				"cpu/cpu.cfs_quota_us":         "-1\n",
+				"cpu/cpu.cfs_period_us":        "100000\n",
+				"memory/memory.limit_in_bytes": "9223372036854771712\n",
+			},
+			want: Limits{Cgroup: "v1", CPUs: unlimited, MemoryBytes: unlimited},
+		},
+		{
+			name: "v1 combined cpu,cpuacct mount",
+			files: map[string]string{
+				"cpu,cpuacct/cpu.cfs_quota_us":  "50000\n",
+				"cpu,cpuacct/cpu.cfs_period_us": "100000\n",
+			},
+			want: Limits{Cgroup: "v1", CPUs: "0.5", MemoryBytes: unlimited},
+		},
+		{
+			name:  "no cgroup filesystem",
+			files: nil,
+			want:  Limits{Cgroup: "none", CPUs: unlimited, MemoryBytes: unlimited},
+		},
+		{
+			name: "garbled files",
+			files: map[string]string{
+				"cgroup.controllers": "",
+				"cpu.max":            "lots\n",
+				"memory.max":         "-5\n",
+			},
+			want: Limits{Cgroup: "v2", CPUs: unlimited, MemoryBytes: unlimited},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cgroupInfo(fakeCgroup(t, tt.files)); got != tt.want {
+				t.Errorf("cgroupInfo = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCgroupInfoMissingRoot(t *testing.T) {
+	got := cgroupInfo(filepath.Join(t.TempDir(), "absent"))
+	if want := (Limits{Cgroup: "none", CPUs: unlimited, MemoryBytes: unlimited}); got != want {
+		t.Errorf("cgroupInfo = %+v, want %+v", got, want)
+	}
+}
+
+func TestRunLimitsLineOnlyWithFlag(t *testing.T) {
+	orig := cgroupRoot
+	cgroupRoot = fakeCgroup(t, map[string]string{
+		"cgroup.controllers": "cpu memory\n",
+		"cpu.max":            "100000 100000\n",
+		"memory.max":         "max\n",
+	})
+	t.Cleanup(func() { cgroupRoot = orig })
+
+	for _, enabled := range []bool{false, true} {
+		var opts Options
+		var args []string
+		if enabled {
+			args = []string{"--cgroup-info"}
+		}
+		if err := newFlagSet(&opts).Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		out := captureStdout(t, func() {
+			Run("primes", opts, 0, func() int64 { return 1 })
+		})
+
+		line, found := "", false
+		for _, l := range strings.Split(out, "\n") {
+			if rest, ok := strings.CutPrefix(l, "LIMITS: "); ok {
+				line, found = rest, true
+			}
+		}
+		if found != enabled {
+			t.Fatalf("--cgroup-info=%v: LIMITS line present = %v\n%s", enabled, found, out)
+		}
+		if enabled {
+			var l Limits
+			if err := json.Unmarshal([]byte(line), &l); err != nil {
+				t.Fatalf("LIMITS value is not JSON: %v\n%s", err, line)
+			}
+			if want := (Limits{Cgroup: "v2", CPUs: "1", MemoryBytes: unlimited}); l != want {
+				t.Errorf("LIMITS = %+v, want %+v", l, want)
+			}
+		}
+	}
+}
+
+func TestReportFormatJSONLimits(t *testing.T) {
+	s := summarize(us(100))
+	s.Limits = &Limits{Cgroup: "v2", CPUs: "2", MemoryBytes: "1073741824"}
+
+	out := captureStdout(t, func() {
+		if err := ReportFormat(FormatJSON, "primes", 0, s); err != nil {
+			t.Fatal(err)
+		}
+	})
+	var got struct {
+		Limits *Limits `json:"limits"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Limits == nil || *got.Limits != *s.Limits {
+		t.Errorf("limits = %+v, want %+v", got.Limits, s.Limits)
+	}
+}
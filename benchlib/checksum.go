@@ -0,0 +1,87 @@
+package benchlib
+
+import "math"
+
+// InvalidFloatChecksum is returned by FloatChecksum when the total is NaN,
+// infinite, or outside the int64 range. Its value, -2^63, is reserved for
+// this purpose: a total that rounds to exactly -2^63 is reported as
+// invalid too, so a caller comparing against InvalidFloatChecksum never
+// mistakes a real checksum for an overflow.
+const InvalidFloatChecksum int64 = math.MinInt64
+
+// FloatChecksum reduces values to an integer that any language can
+// reproduce exactly. The values are added one at a time, left to right, in
+// float64 (each addition rounded to nearest, ties to even, as IEEE 754
+// requires), starting from +0. The total is then rounded half to even to
+// an integer and converted to int64, which must lie strictly between -2^63
+// and 2^63; any other total yields InvalidFloatChecksum.
+//
+// Both steps matter for cross-language validation: reassociating the sum,
+// or truncating instead of rounding, can change the last digit.
+func FloatChecksum(values []float64) int64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	r := math.RoundToEven(sum)
+	// 2^63 is the first value past the int64 range. -2^63 is within it,
+	// but is InvalidFloatChecksum itself.
+	if math.IsNaN(r) || r <= math.MinInt64 || r >= math.MaxInt64 {
+		return InvalidFloatChecksum
+	}
+	return int64(r)
+}
+
+// FNV-1a 64-bit parameters, used by Checksum, Checksum64 and Checksum32.
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// Checksum reduces xs to an integer that any language can reproduce
+// exactly, and that changes when elements are reordered: the 64-bit
+// FNV-1a hash of the elements, each taken as a two's-complement int64 and
+// fed as its 8 bytes in little-endian order, reinterpreted as an int64.
+// In full, starting from h = 14695981039346656037, for each byte b:
+//
+//	h = (h XOR b) * 1099511628211 mod 2^64
+//
+// Unlike a plain sum it cannot overflow, and unlike a position-weighted
+// sum modulo a prime it needs only wrapping 64-bit arithmetic.
+func Checksum(xs []int) int64 {
+	h := uint64(fnvOffset64)
+	for _, x := range xs {
+		h = fnvMix(h, uint64(x))
+	}
+	return int64(h)
+}
+
+// Checksum64 is Checksum for int64 elements. The two agree on equal
+// values.
+func Checksum64(xs []int64) int64 {
+	h := uint64(fnvOffset64)
+	for _, x := range xs {
+		h = fnvMix(h, uint64(x))
+	}
+	return int64(h)
+}
+
+// Checksum32 is Checksum for uint32 elements, each zero-extended to 64
+// bits: it agrees with Checksum64 of the same values widened to int64.
+func Checksum32(xs []uint32) int64 {
+	h := uint64(fnvOffset64)
+	for _, x := range xs {
+		h = fnvMix(h, uint64(x))
+	}
+	return int64(h)
+}
+
+// fnvMix feeds the 8 bytes of v, least significant first, into h.
+func fnvMix(h, v uint64) uint64 {
+	for i := 0; i < 8; i++ {
+		h ^= v & 0xff
+		h *= fnvPrime64
+		v >>= 8
+	}
+	return h
+}
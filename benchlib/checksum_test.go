@@ -0,0 +1,124 @@
+package benchlib
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestFloatChecksum(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   int64
+	}{
+		{"empty", nil, 0},
+		{"integers", []float64{1, 2, 3, 4}, 10},
+		{"round down", []float64{2.4}, 2},
+		{"round up", []float64{2.6}, 3},
+		// Ties go to the even neighbor, not away from zero.
+		{"tie 0.5", []float64{0.5}, 0},
+		{"tie 1.5", []float64{1.5}, 2},
+		{"tie 2.5", []float64{2.5}, 2},
+		{"tie -0.5", []float64{-0.5}, 0},
+		{"tie -1.5", []float64{-1.5}, -2},
+		{"tie -2.5", []float64{-2.5}, -2},
+		{"tie from a sum", []float64{0.25, 0.25, 3}, 4},
+		// Not truncation: int64(2.9) would be 2.
+		{"not truncated", []float64{1.45, 1.45}, 3},
+		// Left-to-right order is part of the definition: 1e16+1 rounds
+		// back to 1e16 (the spacing there is 2), so the 1 is lost.
+		{"order matters", []float64{1e16, 1, -1e16}, 0},
+		{"order matters reversed", []float64{1e16, -1e16, 1}, 1},
+		{"large", []float64{5078978272}, 5078978272},
+		// The float64 nearest -2^63 that is not InvalidFloatChecksum.
+		{"above min int64", []float64{-(1 << 63) + 1024}, math.MinInt64 + 1024},
+	}
+	for _, tt := range tests {
+		if got := FloatChecksum(tt.values); got != tt.want {
+			t.Errorf("%s: FloatChecksum(%v) = %d, want %d", tt.name, tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestFloatChecksumInvalid(t *testing.T) {
+	for _, values := range [][]float64{
+		{math.NaN()},
+		{math.Inf(1)},
+		{math.Inf(-1)},
+		{1 << 63},
+		{math.MaxFloat64},
+		// In the int64 range, but reserved for InvalidFloatChecksum.
+		{-(1 << 63)},
+		{-(1 << 62), -(1 << 62)},
+		{-(1 << 63) - 2048},
+	} {
+		if got := FloatChecksum(values); got != InvalidFloatChecksum {
+			t.Errorf("FloatChecksum(%v) = %d, want InvalidFloatChecksum", values, got)
+		}
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	tests := []struct {
+		xs   []int
+		want int64
+	}{
+		// The FNV-1a offset basis itself.
+		{nil, -3750763034362895579},
+		{[]int{0}, -6284781860667377211},
+		{[]int{1, 2, 3}, -2725809024417325307},
+		{[]int{3, 2, 1}, 2988284088020576005},
+		{[]int{-1}, -8289690350564177859},
+		{[]int{math.MaxInt64, math.MinInt64}, 3258221292563947997},
+	}
+	for _, tt := range tests {
+		if got := Checksum(tt.xs); got != tt.want {
+			t.Errorf("Checksum(%v) = %d, want %d", tt.xs, got, tt.want)
+		}
+		xs64 := make([]int64, len(tt.xs))
+		for i, x := range tt.xs {
+			xs64[i] = int64(x)
+		}
+		if got := Checksum64(xs64); got != tt.want {
+			t.Errorf("Checksum64(%v) = %d, want %d", tt.xs, got, tt.want)
+		}
+	}
+}
+
+func TestChecksum32(t *testing.T) {
+	for _, xs := range [][]uint32{nil, {0}, {1, 2, 3}, {math.MaxUint32, 0, 1 << 31}} {
+		wide := make([]int64, len(xs))
+		for i, x := range xs {
+			wide[i] = int64(x)
+		}
+		if got, want := Checksum32(xs), Checksum64(wide); got != want {
+			t.Errorf("Checksum32(%v) = %d, Checksum64 of the widened values = %d", xs, got, want)
+		}
+	}
+}
+
+func TestChecksumMatchesFNV1a(t *testing.T) {
+	xs := RandomInts(NewRand(DefaultSeed), 1000)
+	h := fnv.New64a()
+	for _, x := range xs {
+		h.Write(binary.LittleEndian.AppendUint64(nil, uint64(x)))
+	}
+	if got, want := Checksum(xs), int64(h.Sum64()); got != want {
+		t.Errorf("Checksum = %d, hash/fnv FNV-1a of the little-endian bytes = %d", got, want)
+	}
+}
+
+func TestChecksumOrderSensitive(t *testing.T) {
+	xs := RandomInts(NewRand(DefaultSeed), 1000)
+	sum := Checksum(xs)
+	for _, swap := range [][2]int{{0, 1}, {0, 999}, {500, 501}} {
+		ys := slices.Clone(xs)
+		ys[swap[0]], ys[swap[1]] = ys[swap[1]], ys[swap[0]]
+		if Checksum(ys) == sum {
+			t.Errorf("swapping elements %d and %d left the checksum unchanged", swap[0], swap[1])
+		}
+	}
+}
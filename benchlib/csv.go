@@ -0,0 +1,55 @@
+package benchlib
+
+import (
+	"encoding/csv"
+	"io"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// CSVHeader is the header row of the csv format. Tools that combine several
+// benchmarks, such as cmd/runall, write it once and append one CSVRecord per
+// benchmark.
+var CSVHeader = []string{"benchmark", "startup_us", "compute_us", "result"}
+
+// CSVRecord returns the csv data row for one benchmark run, matching
+// CSVHeader.
+func CSVRecord(name string, startupUS, computeUS, result int64) []string {
+	return []string{
+		name,
+		strconv.FormatInt(startupUS, 10),
+		strconv.FormatInt(computeUS, 10),
+		strconv.FormatInt(result, 10),
+	}
+}
+
+// reportColumns returns CSVHeader and the CSVRecord for s, each extended by
+// a goroutines column when s.Goroutines is set and a <name>_us column per
+// phase. Files shared between benchmarks, such as AppendCSV's, keep to a
+// fixed header instead.
+func reportColumns(name string, startup time.Duration, s Stats) (header, record []string) {
+	header = slices.Clone(CSVHeader)
+	record = CSVRecord(name, startup.Microseconds(), s.Mean.Microseconds(), s.Result)
+	if s.Goroutines > 0 {
+		header = append(header, "goroutines")
+		record = append(record, strconv.Itoa(s.Goroutines))
+	}
+	for _, p := range s.Phases {
+		header = append(header, p.Name+"_us")
+		record = append(record, strconv.FormatInt(p.Duration.Microseconds(), 10))
+	}
+	return header, record
+}
+
+// printCSV writes the header and a single data row for s, with the extra
+// columns of reportColumns. encoding/csv quotes a field only when it needs to, so numbers
+// are never quoted and a name containing a comma or quote is escaped.
+func printCSV(w io.Writer, name string, startup time.Duration, s Stats) error {
+	header, record := reportColumns(name, startup, s)
+	cw := csv.NewWriter(w)
+	cw.Write(header)
+	cw.Write(record)
+	cw.Flush()
+	return cw.Error()
+}
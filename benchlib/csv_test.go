@@ -0,0 +1,54 @@
+package benchlib
+
+import (
+	"encoding/csv"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReportFormatCSV(t *testing.T) {
+	s := summarize(us(23891))
+	s.Result = 9592
+
+	out := captureStdout(t, func() {
+		if err := ReportFormat(FormatCSV, "primes", 8234*time.Microsecond, s); err != nil {
+			t.Fatalf("ReportFormat: %v", err)
+		}
+	})
+
+	if want := "benchmark,startup_us,compute_us,result\nprimes,8234,23891,9592\n"; out != want {
+		t.Errorf("CSV output = %q, want %q", out, want)
+	}
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatalf("csv.ReadAll: %v", err)
+	}
+	want := [][]string{CSVHeader, {"primes", "8234", "23891", "9592"}}
+	if !slices.EqualFunc(records, want, slices.Equal) {
+		t.Errorf("records = %q, want %q", records, want)
+	}
+}
+
+func TestReportFormatCSVEscapesName(t *testing.T) {
+	s := summarize(us(1))
+	s.Result = -5
+
+	out := captureStdout(t, func() {
+		if err := ReportFormat(FormatCSV, `sort,"big"`, 0, s); err != nil {
+			t.Fatalf("ReportFormat: %v", err)
+		}
+	})
+
+	if !strings.HasSuffix(out, "\n\"sort,\"\"big\"\"\",0,1,-5\n") {
+		t.Errorf("CSV output = %q, want quoted name and unquoted numbers", out)
+	}
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatalf("csv.ReadAll: %v", err)
+	}
+	if len(records) != 2 || len(records[1]) != len(CSVHeader) || records[1][0] != `sort,"big"` {
+		t.Errorf("records = %q", records)
+	}
+}
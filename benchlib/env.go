@@ -0,0 +1,67 @@
+package benchlib
+
+import (
+	"flag"
+	"fmt"
+)
+
+// Environment variables that supply shared flag values, so one CI
+// configuration applies to every benchmark binary alike.
+const (
+	EnvIterations = "BENCH_ITERATIONS"
+	EnvWarmup     = "BENCH_WARMUP"
+	EnvFormat     = "BENCH_FORMAT"
+)
+
+// envFlags maps each environment variable to the flag it supplies.
+var envFlags = []struct{ env, flag string }{
+	{EnvIterations, "iterations"},
+	{EnvWarmup, "warmup"},
+	{EnvFormat, "format"},
+}
+
+// applyEnv sets each flag in envFlags whose variable lookup reports as
+// non-empty, replacing the flag's default. Parsing the command line
+// afterwards overrides these values again, so the precedence is: flag,
+// then environment variable, then built-in default. A value the flag
+// rejects is recorded in o.envErrs, for validate to report, unless the
+// command line then sets that flag.
+func (o *Options) applyEnv(fs *flag.FlagSet, lookup func(string) (string, bool)) {
+	for _, e := range envFlags {
+		v, ok := lookup(e.env)
+		if !ok || v == "" {
+			continue
+		}
+		f := fs.Lookup(e.flag)
+		if err := f.Value.Set(v); err != nil {
+			if o.envErrs == nil {
+				o.envErrs = make(map[string]error)
+			}
+			o.envErrs[e.flag] = fmt.Errorf("%s=%q: %v", e.env, v, err)
+			f.Value = overriddenValue{f.Value, func() { delete(o.envErrs, f.Name) }}
+		}
+	}
+}
+
+// envErr returns the error for the first invalid environment value still
+// in effect, or nil.
+func (o Options) envErr() error {
+	for _, e := range envFlags {
+		if err := o.envErrs[e.flag]; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// overriddenValue wraps a flag whose environment value was invalid and
+// calls clear when the command line sets it.
+type overriddenValue struct {
+	flag.Value
+	clear func()
+}
+
+func (v overriddenValue) Set(s string) error {
+	v.clear()
+	return v.Value.Set(s)
+}
@@ -0,0 +1,97 @@
+package benchlib
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+)
+
+// parseWithEnv registers the shared flags with env in place of the process
+// environment and parses args.
+func parseWithEnv(t *testing.T, env map[string]string, args ...string) Options {
+	t.Helper()
+	var opts Options
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	opts.registerFlags(fs, func(k string) (string, bool) {
+		v, ok := env[k]
+		return v, ok
+	})
+	if err := fs.Parse(args); err != nil {
+		t.Fatalf("Parse(%q): %v", args, err)
+	}
+	return opts
+}
+
+func TestEnvFallbacks(t *testing.T) {
+	env := map[string]string{EnvIterations: "20", EnvWarmup: "auto", EnvFormat: FormatJSON}
+	tests := []struct {
+		name       string
+		env        map[string]string
+		args       []string
+		iterations int
+		warmupAuto bool
+		format     string
+	}{
+		{"default", nil, nil, 1, false, FormatText},
+		{"env only", env, nil, 20, true, FormatJSON},
+		{"flag over env", env, []string{"--iterations=5", "--warmup=2", "--format=csv"}, 5, false, FormatCSV},
+		{"empty env is unset", map[string]string{EnvIterations: "", EnvFormat: ""}, nil, 1, false, FormatText},
+	}
+	for _, tt := range tests {
+		opts := parseWithEnv(t, tt.env, tt.args...)
+		if opts.Iterations != tt.iterations || opts.WarmupAuto != tt.warmupAuto || opts.Format != tt.format {
+			t.Errorf("%s: iterations %d, warmup auto %v, format %q; want %d, %v, %q",
+				tt.name, opts.Iterations, opts.WarmupAuto, opts.Format, tt.iterations, tt.warmupAuto, tt.format)
+		}
+		if err := opts.validate(); err != nil {
+			t.Errorf("%s: validate: %v", tt.name, err)
+		}
+	}
+}
+
+func TestEnvInvalidValue(t *testing.T) {
+	env := map[string]string{EnvWarmup: "lots"}
+	err := parseWithEnv(t, env).validate()
+	if err == nil || !strings.HasPrefix(err.Error(), `BENCH_WARMUP="lots": `) {
+		t.Errorf("validate = %v, want an error naming BENCH_WARMUP", err)
+	}
+	// A flag replaces the bad value, so there is nothing left to report.
+	if err := parseWithEnv(t, env, "--warmup=3").validate(); err != nil {
+		t.Errorf("validate with --warmup=3 = %v, want nil", err)
+	}
+}
+
+func TestRegisterFlagsReadsEnvironment(t *testing.T) {
+	t.Setenv(EnvIterations, "7")
+	var opts Options
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	opts.RegisterFlags(fs)
+	if err := fs.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+	if opts.Iterations != 7 {
+		t.Errorf("Iterations = %d, want 7 from %s", opts.Iterations, EnvIterations)
+	}
+}
+
+func TestNewFlagSetIgnoresEnvironment(t *testing.T) {
+	// A CI job may export these for the benchmarks; the package's own
+	// tests must still see the defaults.
+	t.Setenv(EnvIterations, "2")
+	t.Setenv(EnvWarmup, "auto")
+	t.Setenv(EnvFormat, FormatJSON)
+	var opts Options
+	if err := newFlagSet(&opts).Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+	if opts.Iterations != 1 || opts.Warmup != 0 || opts.WarmupAuto || opts.Format != FormatText {
+		t.Errorf("iterations %d, warmup %d (auto %v), format %q; want the defaults 1, 0, false, %q",
+			opts.Iterations, opts.Warmup, opts.WarmupAuto, opts.Format, FormatText)
+	}
+	if err := opts.validate(); err != nil {
+		t.Errorf("validate: %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+package benchlib
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Output formats accepted by ReportFormat and the --format flag.
+const (
+	FormatText       = "text"
+	FormatJSON       = "json"
+	FormatBenchstat  = "benchstat"
+	FormatCSV        = "csv"
+	FormatPrometheus = "prometheus"
+	FormatJSONL      = "jsonl"
+	FormatMarkdown   = "markdown"
+)
+
+// formats lists every supported output format, in the order shown in help
+// and error messages.
+var formats = []string{FormatText, FormatJSON, FormatBenchstat, FormatCSV, FormatPrometheus, FormatJSONL, FormatMarkdown}
+
+// unknownFormat builds the error for an unsupported format name.
+func unknownFormat(format string) error {
+	return fmt.Errorf("unknown output format %q (want one of %s)", format, strings.Join(formats, ", "))
+}
+
+// jsonReport is the single-line JSON form of a benchmark run. Field order
+// is fixed by the struct definition, so the output is stable.
+type jsonReport struct {
+	Benchmark string `json:"benchmark"`
+	StartupUS int64  `json:"startup_us"`
+	ComputeUS int64  `json:"compute_us"`
+	Result    int64  `json:"result"`
+	// PhasesUS maps each phase name to its mean in microseconds; it is
+	// present when phases were timed.
+	PhasesUS map[string]int64 `json:"phases_us,omitempty"`
+	// GOMAXPROCS is present for concurrent benchmarks and --gomaxprocs.
+	GOMAXPROCS int `json:"gomaxprocs,omitempty"`
+	// Goroutines is present when the benchmark reports its goroutine count.
+	Goroutines int `json:"goroutines,omitempty"`
+	// WarmupRuns is present after --warmup=auto.
+	WarmupRuns int `json:"warmup_runs,omitempty"`
+	// Partial is present when an interrupt cut the runs short.
+	Partial bool `json:"partial,omitempty"`
+
+	// Memory fields are flattened into the object when --mem is set.
+	*MemStats
+
+	// Host is nested rather than flattened because its fields describe the
+	// machine, not the run.
+	Host *Host `json:"host,omitempty"`
+	// Limits is nested for the same reason.
+	Limits *Limits `json:"limits,omitempty"`
+}
+
+// newJSONReport builds the json format object for s.
+func newJSONReport(name string, startup time.Duration, s Stats) jsonReport {
+	return jsonReport{
+		Benchmark:  name,
+		StartupUS:  startup.Microseconds(),
+		ComputeUS:  s.Mean.Microseconds(),
+		Result:     s.Result,
+		PhasesUS:   phasesUS(s.Phases),
+		GOMAXPROCS: s.GOMAXPROCS,
+		Goroutines: s.Goroutines,
+		WarmupRuns: s.WarmupRuns,
+		Partial:    s.Partial,
+		MemStats:   s.Mem,
+		Host:       s.Host,
+		Limits:     s.Limits,
+	}
+}
+
+// phasesUS returns the microsecond mean of each phase by name, or nil for
+// no phases.
+func phasesUS(phases []Phase) map[string]int64 {
+	if len(phases) == 0 {
+		return nil
+	}
+	m := make(map[string]int64, len(phases))
+	for _, p := range phases {
+		m[p.Name] = p.Duration.Microseconds()
+	}
+	return m
+}
+
+// ReportFormat prints the outcome of a benchmark named name in the given
+// format. The text format is the standardized line-oriented output written
+// by ReportStats; the JSON format is one object on a single line; the
+// benchstat format is described at printBenchstat; the csv format is a
+// CSVHeader row followed by one CSVRecord; the prometheus format is the
+// text exposition format written by WritePrometheus; the markdown format
+// is a one-row WriteMarkdownTable.
+func ReportFormat(format, name string, startup time.Duration, s Stats) error {
+	switch format {
+	case FormatText:
+		ReportStats(startup, s)
+		return nil
+	case FormatJSON:
+		return json.NewEncoder(os.Stdout).Encode(newJSONReport(name, startup, s))
+	case FormatBenchstat:
+		printBenchstat(name, s)
+		return nil
+	case FormatCSV:
+		return printCSV(os.Stdout, name, startup, s)
+	case FormatPrometheus:
+		return printPrometheus(os.Stdout, name, startup, s)
+	case FormatJSONL:
+		return printJSONL(os.Stdout, name, startup, s)
+	case FormatMarkdown:
+		return printMarkdown(os.Stdout, name, startup, s)
+	default:
+		return unknownFormat(format)
+	}
+}
+
+// printBenchstat writes s in the Go benchmark format read by
+// golang.org/x/perf/cmd/benchstat:
+//
+//	goos: linux
+//	goarch: amd64
+//	BenchmarkPrimes 	      30	    212345 ns/op
+//
+// The iteration count is the number of timed runs and ns/op is their mean,
+// taken from the nanosecond durations rather than the truncated
+// microsecond output. Each phase follows as an extra metric, such as
+// "parse-ns/op". With --host-info a "cpu:" line is added, as go test does,
+// so benchstat can tell machines apart.
+func printBenchstat(name string, s Stats) {
+	fmt.Printf("goos: %s\n", runtime.GOOS)
+	fmt.Printf("goarch: %s\n", runtime.GOARCH)
+	if s.Host != nil {
+		fmt.Printf("cpu: %s\n", s.Host.CPUModel)
+	}
+	if s.Goroutines > 0 {
+		fmt.Printf("goroutines: %d\n", s.Goroutines)
+	}
+	fmt.Printf("%s \t%8d\t%10d ns/op", benchstatName(name), len(s.Samples), s.Mean.Nanoseconds())
+	for _, p := range s.Phases {
+		fmt.Printf("\t%10d %s-ns/op", p.Duration.Nanoseconds(), p.Name)
+	}
+	fmt.Println()
+}
+
+// benchstatName converts a benchmark name such as "matrix-multiply" into a
+// Go benchmark identifier such as "BenchmarkMatrixMultiply".
+func benchstatName(name string) string {
+	var b strings.Builder
+	b.WriteString("Benchmark")
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,174 @@
+package benchlib
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReportFormatJSONRoundTrip(t *testing.T) {
+	s := summarize(us(23891))
+	s.Result = 9592
+
+	out := captureStdout(t, func() {
+		if err := ReportFormat(FormatJSON, "primes", 8234*time.Microsecond, s); err != nil {
+			t.Fatalf("ReportFormat: %v", err)
+		}
+	})
+
+	want := `{"benchmark":"primes","startup_us":8234,"compute_us":23891,"result":9592}` + "\n"
+	if out != want {
+		t.Errorf("JSON output = %q, want %q", out, want)
+	}
+
+	var got jsonReport
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("json.Unmarshal(%q): %v", out, err)
+	}
+	if want := (jsonReport{Benchmark: "primes", StartupUS: 8234, ComputeUS: 23891, Result: 9592}); !reflect.DeepEqual(got, want) {
+		t.Errorf("round-tripped report = %+v", got)
+	}
+}
+
+func TestReportFormatText(t *testing.T) {
+	s := summarize(us(20))
+	s.Result = 7
+
+	got := captureStdout(t, func() {
+		if err := ReportFormat(FormatText, "primes", 5*time.Microsecond, s); err != nil {
+			t.Fatalf("ReportFormat: %v", err)
+		}
+	})
+	want := "STARTUP_TIME_US: 5\n" +
+		"STARTUP_TIME_NS: 5000\n" +
+		"COMPUTE_TIME_US: 20\n" +
+		"COMPUTE_TIME_NS: 20000\n" +
+		"RESULT: 7\n"
+	if got != want {
+		t.Errorf("text output = %q, want %q", got, want)
+	}
+}
+
+func TestReportFormatUnknown(t *testing.T) {
+	err := ReportFormat("yaml", "primes", 0, summarize(us(1)))
+	if err == nil || !strings.Contains(err.Error(), `"yaml"`) {
+		t.Errorf("ReportFormat(yaml) error = %v, want unknown format error", err)
+	}
+}
+
+func TestOptionsDefaultFormatIsText(t *testing.T) {
+	var opts Options
+	fs := newFlagSet(&opts)
+	if err := fs.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+	if opts.Format != FormatText {
+		t.Errorf("default Format = %q, want %q", opts.Format, FormatText)
+	}
+	if err := opts.validate(); err != nil {
+		t.Errorf("default options invalid: %v", err)
+	}
+}
+
+func TestReportFormatBenchstat(t *testing.T) {
+	// Three runs whose mean (1234567.333ns) is not a whole microsecond.
+	s := summarize([]time.Duration{1234000, 1234567, 1235135})
+	s.Result = 9592
+
+	out := captureStdout(t, func() {
+		if err := ReportFormat(FormatBenchstat, "primes", 0, s); err != nil {
+			t.Fatalf("ReportFormat: %v", err)
+		}
+	})
+
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("benchstat output has %d lines, want 3:\n%s", len(lines), out)
+	}
+	if !strings.HasPrefix(lines[0], "goos: ") || !strings.HasPrefix(lines[1], "goarch: ") {
+		t.Errorf("missing goos/goarch header:\n%s", out)
+	}
+	want := "BenchmarkPrimes \t       3\t   1234567 ns/op"
+	if lines[2] != want {
+		t.Errorf("benchmark line = %q, want %q", lines[2], want)
+	}
+
+	// benchstat splits on whitespace: name, iterations, value, unit.
+	fields := strings.Fields(lines[2])
+	if len(fields) != 4 || fields[1] != "3" || fields[3] != "ns/op" {
+		t.Errorf("benchmark line fields = %q", fields)
+	}
+}
+
+func TestBenchstatName(t *testing.T) {
+	tests := map[string]string{
+		"primes":          "BenchmarkPrimes",
+		"matrix-multiply": "BenchmarkMatrixMultiply",
+		"sha256":          "BenchmarkSha256",
+		"a_b c":           "BenchmarkABC",
+	}
+	for in, want := range tests {
+		if got := benchstatName(in); got != want {
+			t.Errorf("benchstatName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestReportFormatGoroutines(t *testing.T) {
+	s := summarize(us(61000))
+	s.Result = 5000000
+	s.Goroutines = 8
+
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{FormatText, []string{"GOROUTINES: 8\nSTARTUP_TIME_US: "}},
+		{FormatJSON, []string{`"result":5000000,"goroutines":8}`}},
+		{FormatJSONL, []string{`"result":5000000,"goroutines":8}`}},
+		{FormatCSV, []string{"benchmark,startup_us,compute_us,result,goroutines\nmutex,0,61000,5000000,8\n"}},
+		{FormatMarkdown, []string{"| benchmark | startup_us | compute_us | result | goroutines |\n", "| mutex | 0 | 61000 | 5000000 | 8 |\n"}},
+		{FormatPrometheus, []string{"# TYPE benchmark_goroutines gauge\n", `benchmark_goroutines{benchmark="mutex"} 8` + "\n"}},
+		{FormatBenchstat, []string{"goroutines: 8\nBenchmarkMutex "}},
+	}
+	for _, tt := range tests {
+		out := captureStdout(t, func() {
+			if err := ReportFormat(tt.format, "mutex", 0, s); err != nil {
+				t.Fatalf("ReportFormat(%s): %v", tt.format, err)
+			}
+		})
+		for _, want := range tt.want {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s output missing %q:\n%s", tt.format, want, out)
+			}
+		}
+	}
+
+	s.Goroutines = 0
+	for _, format := range formats {
+		out := captureStdout(t, func() {
+			if err := ReportFormat(format, "mutex", 0, s); err != nil {
+				t.Fatalf("ReportFormat(%s): %v", format, err)
+			}
+		})
+		if strings.Contains(strings.ToLower(out), "goroutines") {
+			t.Errorf("%s output reports goroutines without a count:\n%s", format, out)
+		}
+	}
+}
+
+func TestRunReportsGoroutines(t *testing.T) {
+	opts := Options{Iterations: 1, Format: FormatText, Goroutines: 8}
+	var stats Stats
+	out := captureStdout(t, func() {
+		stats = Run("mutex", opts, 0, func() int64 { return 5000000 })
+	})
+	if stats.Goroutines != 8 {
+		t.Errorf("Stats.Goroutines = %d, want 8", stats.Goroutines)
+	}
+	if !strings.Contains(out, "GOROUTINES: 8\n") {
+		t.Errorf("output missing the GOROUTINES line:\n%s", out)
+	}
+}
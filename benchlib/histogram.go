@@ -0,0 +1,85 @@
+package benchlib
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// HistogramBarWidth is the length, in characters, of the bar for the
+// fullest bucket; the others are scaled to it.
+const HistogramBarWidth = 40
+
+// bucket is one histogram bin, [Lo, Hi), holding Count samples. The last
+// bin also includes Hi.
+type bucket struct {
+	Lo, Hi time.Duration
+	Count  int
+}
+
+// histogram splits [min, max] of samples into n equal-width buckets and
+// counts the samples in each. When every sample is equal there is a single
+// bucket.
+func histogram(samples []time.Duration, n int) []bucket {
+	if len(samples) == 0 {
+		return nil
+	}
+	lo, hi := slices.Min(samples), slices.Max(samples)
+	if lo == hi {
+		return []bucket{{Lo: lo, Hi: hi, Count: len(samples)}}
+	}
+	width := float64(hi-lo) / float64(n)
+	buckets := make([]bucket, n)
+	for i := range buckets {
+		buckets[i].Lo = lo + time.Duration(float64(i)*width)
+		buckets[i].Hi = lo + time.Duration(float64(i+1)*width)
+	}
+	buckets[n-1].Hi = hi
+	for _, d := range samples {
+		i := min(int(float64(d-lo)/width), n-1)
+		buckets[i].Count++
+	}
+	return buckets
+}
+
+// WriteHistogram writes an ASCII histogram of samples in n equal-width
+// buckets to w, one line per bucket with its range in microseconds, a bar
+// and the count:
+//
+//	HISTOGRAM: 20 runs, 4 buckets
+//	[   1200.0,    1250.0) ######################################## 12
+//	[   1250.0,    1300.0) ###                                      1
+//	...
+//
+// A bimodal distribution, such as runs with and without a GC cycle, shows
+// up as two separate groups of bars.
+func WriteHistogram(w io.Writer, samples []time.Duration, n int) error {
+	buckets := histogram(samples, n)
+	peak := 0
+	for _, b := range buckets {
+		peak = max(peak, b.Count)
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "HISTOGRAM: %d runs, %d buckets\n", len(samples), len(buckets))
+	for i, b := range buckets {
+		closing := ")"
+		if i == len(buckets)-1 {
+			closing = "]"
+		}
+		bar := b.Count * HistogramBarWidth / peak
+		if b.Count > 0 && bar == 0 {
+			bar = 1
+		}
+		fmt.Fprintf(bw, "[%9.1f, %9.1f%s %-*s %d\n", microseconds(b.Lo), microseconds(b.Hi), closing,
+			HistogramBarWidth, strings.Repeat("#", bar), b.Count)
+	}
+	return bw.Flush()
+}
+
+// microseconds returns d in fractional microseconds.
+func microseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Microsecond)
+}
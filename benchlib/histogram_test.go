@@ -0,0 +1,102 @@
+package benchlib
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistogramCounts(t *testing.T) {
+	// Two modes, around 100µs and around 200µs, over [100, 200]µs in four
+	// 25µs buckets.
+	samples := us(100, 105, 110, 102, 198, 200, 190, 101, 195, 130, 170, 199)
+	buckets := histogram(samples, 4)
+
+	var counts []int
+	total := 0
+	for _, b := range buckets {
+		counts = append(counts, b.Count)
+		total += b.Count
+	}
+	if want := []int{5, 1, 1, 5}; !slices.Equal(counts, want) {
+		t.Errorf("bucket counts = %v, want %v", counts, want)
+	}
+	if total != len(samples) {
+		t.Errorf("bucket counts sum to %d, want %d", total, len(samples))
+	}
+	if buckets[0].Lo != 100*time.Microsecond || buckets[1].Lo != 125*time.Microsecond || buckets[3].Hi != 200*time.Microsecond {
+		t.Errorf("bucket bounds = %+v", buckets)
+	}
+}
+
+func TestHistogramCountsSumToRuns(t *testing.T) {
+	samples := make([]time.Duration, 97)
+	r := NewRand(DefaultSeed)
+	for i := range samples {
+		samples[i] = time.Duration(1000 + r.Uint64()%5000)
+	}
+	for _, n := range []int{1, 3, 10, 200} {
+		total := 0
+		for _, b := range histogram(samples, n) {
+			total += b.Count
+		}
+		if total != len(samples) {
+			t.Errorf("%d buckets: counts sum to %d, want %d", n, total, len(samples))
+		}
+	}
+}
+
+func TestHistogramEqualSamples(t *testing.T) {
+	buckets := histogram(us(50, 50, 50), 10)
+	if len(buckets) != 1 || buckets[0].Count != 3 {
+		t.Errorf("histogram of equal samples = %+v, want one bucket of 3", buckets)
+	}
+}
+
+func TestWriteHistogram(t *testing.T) {
+	var b strings.Builder
+	if err := WriteHistogram(&b, us(100, 100, 100, 100, 150, 200), 2); err != nil {
+		t.Fatal(err)
+	}
+	want := "HISTOGRAM: 6 runs, 2 buckets\n" +
+		"[    100.0,     150.0) " + strings.Repeat("#", 40) + " 4\n" +
+		"[    150.0,     200.0] " + strings.Repeat("#", 20) + strings.Repeat(" ", 20) + " 2\n"
+	if b.String() != want {
+		t.Errorf("WriteHistogram output:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestRunHistogramKeepsStdout(t *testing.T) {
+	run := func(args ...string) (stdout, stderr string) {
+		fakeClock(t, us(300, 100, 200)...)
+		var opts Options
+		if err := newFlagSet(&opts).Parse(append([]string{"--iterations=3"}, args...)); err != nil {
+			t.Fatal(err)
+		}
+		f, err := os.CreateTemp(t.TempDir(), "stderr")
+		if err != nil {
+			t.Fatal(err)
+		}
+		orig := os.Stderr
+		os.Stderr = f
+		defer func() { os.Stderr = orig }()
+		stdout = captureStdout(t, func() {
+			Run("primes", opts, 0, func() int64 { return 9592 })
+		})
+		b, err := os.ReadFile(f.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return stdout, string(b)
+	}
+	plain, _ := run()
+	stdout, stderr := run("--histogram", "--histogram-buckets=2")
+	if stdout != plain {
+		t.Errorf("--histogram changed stdout:\n%s\nwant:\n%s", stdout, plain)
+	}
+	if !strings.HasPrefix(stderr, "HISTOGRAM: 3 runs, 2 buckets\n") {
+		t.Errorf("stderr = %q, want a histogram of 3 runs", stderr)
+	}
+}
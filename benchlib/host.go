@@ -0,0 +1,72 @@
+package benchlib
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// readCPUInfo is the /proc/cpuinfo source. Tests replace it with a stub.
+var readCPUInfo = func() ([]byte, error) { return os.ReadFile("/proc/cpuinfo") }
+
+// unknownCPU is the CPU model reported when it cannot be determined.
+const unknownCPU = "unknown"
+
+// Host describes the machine a benchmark ran on. It is reported by the
+// --host-info flag so results from different machines are not compared
+// blindly.
+type Host struct {
+	CPUModel  string `json:"cpu_model"`
+	NumCPU    int    `json:"num_cpu"`
+	GOARCH    string `json:"goarch"`
+	GOOS      string `json:"goos"`
+	GoVersion string `json:"go_version"`
+}
+
+// HostInfo collects the current machine's description.
+func HostInfo() Host {
+	return Host{
+		CPUModel:  cpuModel(runtime.GOOS),
+		NumCPU:    runtime.NumCPU(),
+		GOARCH:    runtime.GOARCH,
+		GOOS:      runtime.GOOS,
+		GoVersion: runtime.Version(),
+	}
+}
+
+// cpuModel returns the first "model name" entry of /proc/cpuinfo on Linux,
+// and unknownCPU on other systems or when the file is unreadable or has no
+// such entry (as on some ARM kernels).
+func cpuModel(goos string) string {
+	if goos != "linux" {
+		return unknownCPU
+	}
+	data, err := readCPUInfo()
+	if err != nil {
+		return unknownCPU
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if ok && strings.TrimSpace(key) == "model name" {
+			if model := strings.TrimSpace(value); model != "" {
+				return model
+			}
+		}
+	}
+	return unknownCPU
+}
+
+// printHost writes the text-format HOST line: the Host as a JSON object.
+func printHost(h Host) {
+	data, err := json.Marshal(h)
+	if err != nil {
+		// Host holds only strings and ints, so this cannot happen.
+		panic(err)
+	}
+	fmt.Printf("HOST: %s\n", data)
+}
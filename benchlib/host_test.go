@@ -0,0 +1,142 @@
+package benchlib
+
+import (
+	"encoding/json"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// stubCPUInfo makes readCPUInfo return data, or err when it is non-nil.
+func stubCPUInfo(t *testing.T, data string, err error) {
+	t.Helper()
+	orig := readCPUInfo
+	readCPUInfo = func() ([]byte, error) { return []byte(data), err }
+	t.Cleanup(func() { readCPUInfo = orig })
+}
+
+const x86CPUInfo = `processor	: 0
+vendor_id	: GenuineIntel
+model name	: Intel(R) Xeon(R) Platinum 8375C CPU @ 2.90GHz
+flags		: fpu vme de
+
+processor	: 1
+vendor_id	: GenuineIntel
+model name	: Intel(R) Xeon(R) Platinum 8375C CPU @ 2.90GHz
+`
+
+func TestCPUModel(t *testing.T) {
+	tests := []struct {
+		name string
+		goos string
+		data string
+		err  error
+		want string
+	}{
+		{"linux", "linux", x86CPUInfo, nil, "Intel(R) Xeon(R) Platinum 8375C CPU @ 2.90GHz"},
+		{"no model name", "linux", "processor\t: 0\nBogoMIPS\t: 50.00\n", nil, unknownCPU},
+		{"unreadable", "linux", "", errors.New("permission denied"), unknownCPU},
+		{"other os", "darwin", x86CPUInfo, nil, unknownCPU},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubCPUInfo(t, tt.data, tt.err)
+			if got := cpuModel(tt.goos); got != tt.want {
+				t.Errorf("cpuModel(%q) = %q, want %q", tt.goos, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHostInfoJSON(t *testing.T) {
+	stubCPUInfo(t, x86CPUInfo, nil)
+
+	h := HostInfo()
+	data, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"num_cpu":    float64(runtime.NumCPU()),
+		"goarch":     runtime.GOARCH,
+		"goos":       runtime.GOOS,
+		"go_version": runtime.Version(),
+	}
+	if runtime.GOOS == "linux" {
+		want["cpu_model"] = "Intel(R) Xeon(R) Platinum 8375C CPU @ 2.90GHz"
+	} else {
+		want["cpu_model"] = unknownCPU
+	}
+	if len(fields) != len(want) {
+		t.Errorf("HostInfo JSON has fields %v, want %v", fields, want)
+	}
+	for k, v := range want {
+		if fields[k] != v {
+			t.Errorf("%s = %v, want %v", k, fields[k], v)
+		}
+	}
+}
+
+func TestRunHostLineOnlyWithFlag(t *testing.T) {
+	stubCPUInfo(t, x86CPUInfo, nil)
+
+	for _, host := range []bool{false, true} {
+		var opts Options
+		var args []string
+		if host {
+			args = []string{"--host-info"}
+		}
+		if err := newFlagSet(&opts).Parse(args); err != nil {
+			t.Fatal(err)
+		}
+
+		out := captureStdout(t, func() {
+			Run("primes", opts, 0, func() int64 { return 1 })
+		})
+
+		line, found := "", false
+		for _, l := range strings.Split(out, "\n") {
+			if rest, ok := strings.CutPrefix(l, "HOST: "); ok {
+				line, found = rest, true
+			}
+		}
+		if found != host {
+			t.Fatalf("--host-info=%v: HOST line present = %v\n%s", host, found, out)
+		}
+		if host {
+			var h Host
+			if err := json.Unmarshal([]byte(line), &h); err != nil {
+				t.Fatalf("HOST value is not JSON: %v\n%s", err, line)
+			}
+			if h != HostInfo() {
+				t.Errorf("HOST = %+v, want %+v", h, HostInfo())
+			}
+		}
+	}
+}
+
+func TestReportFormatJSONHost(t *testing.T) {
+	h := Host{CPUModel: "test cpu", NumCPU: 4, GOARCH: "amd64", GOOS: "linux", GoVersion: "go1.23.0"}
+	s := summarize(us(100))
+	s.Host = &h
+
+	out := captureStdout(t, func() {
+		if err := ReportFormat(FormatJSON, "primes", 0, s); err != nil {
+			t.Fatal(err)
+		}
+	})
+	var got struct {
+		Host *Host `json:"host"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Host == nil || *got.Host != h {
+		t.Errorf("host = %+v, want %+v", got.Host, h)
+	}
+}
@@ -0,0 +1,64 @@
+//go:build unix
+
+package benchlib
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// interruptHelperEnv makes the re-executed test binary in TestRunInterrupt
+// run a benchmark that interrupts itself during its third timed run.
+const interruptHelperEnv = "BENCHLIB_INTERRUPT_HELPER"
+
+func TestRunInterrupt(t *testing.T) {
+	if format := os.Getenv(interruptHelperEnv); format != "" {
+		var opts Options
+		fs := flag.NewFlagSet("primes", flag.ContinueOnError)
+		opts.registerFlags(fs, noEnv)
+		fs.Parse([]string{"--iterations=50", "--format=" + format})
+		calls := 0
+		Run("primes", opts, 0, func() int64 {
+			if calls++; calls == 3 {
+				syscall.Kill(os.Getpid(), syscall.SIGINT)
+				// Let the signal be delivered before the run ends.
+				time.Sleep(100 * time.Millisecond)
+			}
+			return 9592
+		})
+		os.Exit(0) // not reached
+	}
+
+	tests := []struct {
+		format, wantStdout string
+	}{
+		{FormatJSON, `"partial":true`},
+		{FormatText, "RESULT: 9592\nPARTIAL: true\nITERATIONS: 3\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			cmd := exec.Command(os.Args[0], "-test.run=^TestRunInterrupt$")
+			cmd.Env = append(os.Environ(), interruptHelperEnv+"="+tt.format)
+			var stdout, stderr strings.Builder
+			cmd.Stdout, cmd.Stderr = &stdout, &stderr
+			err := cmd.Run()
+
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) || exitErr.ExitCode() != 130 {
+				t.Fatalf("err = %v, want exit status 130; stderr:\n%s", err, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.wantStdout) {
+				t.Errorf("stdout:\n%s\nwant it to contain %q", stdout.String(), tt.wantStdout)
+			}
+			if want := "primes: interrupted after 3 of 50 timed runs\n"; stderr.String() != want {
+				t.Errorf("stderr = %q, want %q", stderr.String(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package benchlib
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// jsonlRun is one timed run in the jsonl format.
+type jsonlRun struct {
+	Benchmark string `json:"benchmark"`
+	// Run is the 1-based index of the timed run; warmup runs are not
+	// counted.
+	Run       int   `json:"run"`
+	ComputeUS int64 `json:"compute_us"`
+	ComputeNS int64 `json:"compute_ns"`
+	Result    int64 `json:"result"`
+}
+
+// printJSONL writes s as JSON Lines: one jsonlRun object per sample, in
+// run order, then the json format summary object, which is told apart by
+// having no "run" key. The lines are written after the compute phase, so
+// encoding them never lands inside a timed run.
+//
+// Every run carries the same result: RunN has already checked each run
+// against the first, and the caller validates that value as usual.
+func printJSONL(w io.Writer, name string, startup time.Duration, s Stats) error {
+	enc := json.NewEncoder(w)
+	for i, d := range s.Samples {
+		if err := enc.Encode(jsonlRun{
+			Benchmark: name,
+			Run:       i + 1,
+			ComputeUS: d.Microseconds(),
+			ComputeNS: d.Nanoseconds(),
+			Result:    s.Result,
+		}); err != nil {
+			return err
+		}
+	}
+	return enc.Encode(newJSONReport(name, startup, s))
+}
@@ -0,0 +1,77 @@
+package benchlib
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunFormatJSONL(t *testing.T) {
+	fakeClock(t, us(300, 100, 200)...)
+
+	var opts Options
+	if err := newFlagSet(&opts).Parse([]string{"--format=jsonl", "--iterations=3"}); err != nil {
+		t.Fatal(err)
+	}
+	out := captureStdout(t, func() {
+		Run("primes", opts, 8234*time.Microsecond, func() int64 { return 9592 })
+	})
+
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 3 runs + 1 summary:\n%s", len(lines), out)
+	}
+	for i, line := range lines[:3] {
+		var run map[string]any
+		if err := json.Unmarshal([]byte(line), &run); err != nil {
+			t.Fatalf("line %d does not parse: %v\n%s", i+1, err, line)
+		}
+		want := map[string]any{
+			"benchmark":  "primes",
+			"run":        float64(i + 1),
+			"compute_us": float64([]int{300, 100, 200}[i]),
+			"compute_ns": float64([]int{300, 100, 200}[i] * 1000),
+			"result":     float64(9592),
+		}
+		if len(run) != len(want) {
+			t.Errorf("line %d = %v, want %v", i+1, run, want)
+		}
+		for k, v := range want {
+			if run[k] != v {
+				t.Errorf("line %d: %s = %v, want %v", i+1, k, run[k], v)
+			}
+		}
+	}
+
+	var summary map[string]any
+	if err := json.Unmarshal([]byte(lines[3]), &summary); err != nil {
+		t.Fatalf("summary does not parse: %v\n%s", err, lines[3])
+	}
+	if _, ok := summary["run"]; ok {
+		t.Errorf("summary has a run key: %s", lines[3])
+	}
+	jsonOut := captureStdout(t, func() {
+		s := summarize(us(300, 100, 200))
+		s.Result = 9592
+		if err := ReportFormat(FormatJSON, "primes", 8234*time.Microsecond, s); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if lines[3]+"\n" != jsonOut {
+		t.Errorf("summary = %s, want the json format object %s", lines[3], jsonOut)
+	}
+}
+
+func TestRunFormatJSONLSkipsWarmup(t *testing.T) {
+	var opts Options
+	if err := newFlagSet(&opts).Parse([]string{"--format=jsonl", "--warmup=2", "--iterations=2"}); err != nil {
+		t.Fatal(err)
+	}
+	out := captureStdout(t, func() {
+		Run("primes", opts, 0, func() int64 { return 1 })
+	})
+	if n := strings.Count(out, "\n"); n != 3 {
+		t.Errorf("got %d lines, want 2 runs + 1 summary:\n%s", n, out)
+	}
+}
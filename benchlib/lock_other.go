@@ -0,0 +1,8 @@
+//go:build !unix
+
+package benchlib
+
+import "os"
+
+// lockFile is a no-op where flock is unavailable.
+func lockFile(f *os.File) error { return nil }
@@ -0,0 +1,37 @@
+//go:build unix
+
+package benchlib
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAppendCSVWaitsForLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.csv")
+	holder, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := lockFile(holder); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error)
+	go func() { done <- AppendCSV(path, "primes", 0, Stats{}, time.Now()) }()
+	select {
+	case err := <-done:
+		t.Fatalf("AppendCSV returned %v while the file was locked", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	holder.Close()
+	if err := <-done; err != nil {
+		t.Fatalf("AppendCSV: %v", err)
+	}
+	if got := readCSV(t, path); len(got) != 2 {
+		t.Errorf("records = %q, want the header and one row", got)
+	}
+}
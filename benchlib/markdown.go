@@ -0,0 +1,43 @@
+package benchlib
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+)
+
+// WriteMarkdownTable writes header and rows as a GitHub-flavored Markdown
+// table. The first column is left-aligned and the rest, which hold
+// numbers, are right-aligned with the |---:| syntax. A "|" inside a cell is
+// escaped.
+func WriteMarkdownTable(w io.Writer, header []string, rows [][]string) error {
+	bw := bufio.NewWriter(w)
+	writeMarkdownRow(bw, header)
+	bw.WriteString("|---|")
+	for range header[1:] {
+		bw.WriteString("---:|")
+	}
+	bw.WriteString("\n")
+	for _, row := range rows {
+		writeMarkdownRow(bw, row)
+	}
+	return bw.Flush()
+}
+
+func writeMarkdownRow(w *bufio.Writer, cells []string) {
+	w.WriteString("|")
+	for _, c := range cells {
+		w.WriteString(" ")
+		w.WriteString(strings.ReplaceAll(c, "|", `\|`))
+		w.WriteString(" |")
+	}
+	w.WriteString("\n")
+}
+
+// printMarkdown writes s as a Markdown table with the columns of the csv
+// format and a single row.
+func printMarkdown(w io.Writer, name string, startup time.Duration, s Stats) error {
+	header, row := reportColumns(name, startup, s)
+	return WriteMarkdownTable(w, header, [][]string{row})
+}
@@ -0,0 +1,35 @@
+package benchlib
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestReportFormatMarkdown(t *testing.T) {
+	s := summarize(us(23891))
+	s.Result = 9592
+
+	out := captureStdout(t, func() {
+		if err := ReportFormat(FormatMarkdown, "primes", 8234*time.Microsecond, s); err != nil {
+			t.Fatalf("ReportFormat: %v", err)
+		}
+	})
+
+	want := "| benchmark | startup_us | compute_us | result |\n" +
+		"|---|---:|---:|---:|\n" +
+		"| primes | 8234 | 23891 | 9592 |\n"
+	if out != want {
+		t.Errorf("markdown output:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestWriteMarkdownTableEscapesPipes(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteMarkdownTable(&buf, []string{"name", "n"}, [][]string{{"a|b", "1"}}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "| name | n |\n|---|---:|\n| a\\|b | 1 |\n"; buf.String() != want {
+		t.Errorf("table = %q, want %q", buf.String(), want)
+	}
+}
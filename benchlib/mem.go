@@ -0,0 +1,89 @@
+package benchlib
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// readMemStats is the MemStats source. Tests replace it with a stub.
+var readMemStats = runtime.ReadMemStats
+
+// MemStats is the heap summary reported by the --mem flag.
+type MemStats struct {
+	// AllocBytes is the live heap after a forced GC.
+	AllocBytes uint64 `json:"alloc_bytes"`
+	// TotalAllocBytes is the cumulative bytes allocated by the process.
+	TotalAllocBytes uint64 `json:"total_alloc_bytes"`
+	// NumGC is the number of completed GC cycles.
+	NumGC uint32 `json:"num_gc"`
+	// GCPauseTotalNs and GCPauseMaxNs are the summed and longest
+	// stop-the-world pauses of the GC cycles that completed between the
+	// MemBaseline and the read, excluding the GC forced by the read itself.
+	GCPauseTotalNs uint64 `json:"gc_pause_total_ns"`
+	GCPauseMaxNs   uint64 `json:"gc_pause_max_ns"`
+}
+
+// MemBaseline holds the GC counters at the start of the compute phase, so
+// pause figures cover only the work being measured.
+type MemBaseline struct {
+	numGC        uint32
+	pauseTotalNs uint64
+}
+
+// StartMem snapshots the GC counters. Call it before the compute phase and
+// pass the result to ReadMemSince afterwards.
+func StartMem() MemBaseline {
+	var m runtime.MemStats
+	readMemStats(&m)
+	return MemBaseline{numGC: m.NumGC, pauseTotalNs: m.PauseTotalNs}
+}
+
+// ReadMem is ReadMemSince from process start.
+func ReadMem() MemStats {
+	return ReadMemSince(MemBaseline{})
+}
+
+// ReadMemSince forces a garbage collection so the live-heap figure is
+// stable, then takes a single runtime.ReadMemStats snapshot. ReadMemStats
+// stops the world, so after the baseline it is called exactly once.
+//
+// The forced GC is the most recent cycle in the snapshot, so its pause is
+// subtracted from the total and skipped for the maximum. The runtime keeps
+// only the last len(PauseNs) pauses; if more cycles than that ran, the
+// maximum is taken over the ones still recorded.
+func ReadMemSince(base MemBaseline) MemStats {
+	runtime.GC()
+	var m runtime.MemStats
+	readMemStats(&m)
+
+	stats := MemStats{
+		AllocBytes:      m.Alloc,
+		TotalAllocBytes: m.TotalAlloc,
+		NumGC:           m.NumGC,
+	}
+	if m.NumGC <= base.numGC {
+		return stats
+	}
+	// GC cycle k (1-based) has its pause at PauseNs[(k+255)%256].
+	n := uint32(len(m.PauseNs))
+	forced := m.PauseNs[(m.NumGC+n-1)%n]
+	stats.GCPauseTotalNs = m.PauseTotalNs - base.pauseTotalNs - forced
+
+	first := base.numGC + 1
+	if m.NumGC > n && first <= m.NumGC-n {
+		first = m.NumGC - n + 1
+	}
+	for k := first; k < m.NumGC; k++ {
+		stats.GCPauseMaxNs = max(stats.GCPauseMaxNs, m.PauseNs[(k+n-1)%n])
+	}
+	return stats
+}
+
+// printMem writes the text-format memory lines.
+func printMem(m MemStats) {
+	fmt.Printf("ALLOC_BYTES: %d\n", m.AllocBytes)
+	fmt.Printf("TOTAL_ALLOC_BYTES: %d\n", m.TotalAllocBytes)
+	fmt.Printf("NUM_GC: %d\n", m.NumGC)
+	fmt.Printf("GC_PAUSE_TOTAL_NS: %d\n", m.GCPauseTotalNs)
+	fmt.Printf("GC_PAUSE_MAX_NS: %d\n", m.GCPauseMaxNs)
+}
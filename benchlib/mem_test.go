@@ -0,0 +1,113 @@
+package benchlib
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestRunMemLinesOnlyWithFlag(t *testing.T) {
+	for _, mem := range []bool{false, true} {
+		args := []string{"--mem=false"}
+		if mem {
+			args = []string{"--mem"}
+		}
+		var opts Options
+		if err := newFlagSet(&opts).Parse(args); err != nil {
+			t.Fatal(err)
+		}
+
+		out := captureStdout(t, func() {
+			Run("primes", opts, 0, func() int64 {
+				return int64(len(make([]byte, 1<<20)))
+			})
+		})
+
+		for _, key := range []string{"ALLOC_BYTES: ", "TOTAL_ALLOC_BYTES: ", "NUM_GC: ", "GC_PAUSE_TOTAL_NS: ", "GC_PAUSE_MAX_NS: "} {
+			if got := strings.Contains(out, "\n"+key); got != mem {
+				t.Errorf("--mem=%v: %s line present = %v\n%s", mem, key, got, out)
+			}
+		}
+	}
+}
+
+func TestReadMemSingleSnapshot(t *testing.T) {
+	calls := 0
+	readMemStats = func(m *runtime.MemStats) {
+		calls++
+		m.Alloc, m.TotalAlloc, m.NumGC = 1, 2, 3
+	}
+	t.Cleanup(func() { readMemStats = runtime.ReadMemStats })
+
+	got := ReadMem()
+	if calls != 1 {
+		t.Errorf("ReadMemStats called %d times, want 1", calls)
+	}
+	if got != (MemStats{AllocBytes: 1, TotalAllocBytes: 2, NumGC: 3}) {
+		t.Errorf("ReadMem() = %+v", got)
+	}
+}
+
+// stubMemStats makes successive readMemStats calls return snaps in order.
+func stubMemStats(t *testing.T, snaps ...runtime.MemStats) {
+	t.Helper()
+	calls := 0
+	readMemStats = func(m *runtime.MemStats) {
+		*m = snaps[calls]
+		calls++
+	}
+	t.Cleanup(func() { readMemStats = runtime.ReadMemStats })
+}
+
+// withPauses returns a MemStats after the given numbered GC cycles, with
+// pauses[k] recorded as the pause of cycle k.
+func withPauses(numGC uint32, totalNs uint64, pauses map[uint32]uint64) runtime.MemStats {
+	m := runtime.MemStats{NumGC: numGC, PauseTotalNs: totalNs}
+	for k, ns := range pauses {
+		m.PauseNs[(k+255)%256] = ns
+	}
+	return m
+}
+
+func TestReadMemSincePauseDeltas(t *testing.T) {
+	// Ten cycles before compute totalling 1000ns; cycles 11 and 12 run
+	// during compute; cycle 13 is the GC forced by the read.
+	stubMemStats(t,
+		withPauses(10, 1000, map[uint32]uint64{10: 999}),
+		withPauses(13, 1000+300+500+50, map[uint32]uint64{10: 999, 11: 300, 12: 500, 13: 50}),
+	)
+
+	got := ReadMemSince(StartMem())
+	if got.GCPauseTotalNs != 800 {
+		t.Errorf("GCPauseTotalNs = %d, want 800 (300+500, excluding pre-compute and forced GCs)", got.GCPauseTotalNs)
+	}
+	if got.GCPauseMaxNs != 500 {
+		t.Errorf("GCPauseMaxNs = %d, want 500", got.GCPauseMaxNs)
+	}
+}
+
+func TestReadMemSinceRingWraparound(t *testing.T) {
+	// Cycles 255..258 straddle the end of the 256-entry PauseNs ring.
+	stubMemStats(t,
+		withPauses(254, 0, nil),
+		withPauses(259, 70+20+90+10+5, map[uint32]uint64{255: 70, 256: 20, 257: 90, 258: 10, 259: 5}),
+	)
+
+	got := ReadMemSince(StartMem())
+	if got.GCPauseTotalNs != 190 || got.GCPauseMaxNs != 90 {
+		t.Errorf("pauses = total %d max %d, want 190 and 90", got.GCPauseTotalNs, got.GCPauseMaxNs)
+	}
+}
+
+func TestReadMemSinceNoComputeGC(t *testing.T) {
+	// Only the forced GC ran after the baseline.
+	stubMemStats(t,
+		withPauses(4, 400, nil),
+		withPauses(5, 460, map[uint32]uint64{5: 60}),
+	)
+
+	got := ReadMemSince(StartMem())
+	if got.GCPauseTotalNs != 0 || got.GCPauseMaxNs != 0 {
+		t.Errorf("pauses = total %d max %d, want 0 and 0", got.GCPauseTotalNs, got.GCPauseMaxNs)
+	}
+}
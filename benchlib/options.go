@@ -0,0 +1,267 @@
+package benchlib
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Options holds the command-line flags shared by every benchmark.
+type Options struct {
+	// Iterations is the number of timed compute runs.
+	Iterations int
+	// Warmup is the number of untimed runs before the timed ones.
+	Warmup int
+	// WarmupAuto replaces the fixed Warmup count with warmup runs that
+	// continue until compute time stabilizes; see RunAutoWarm. The
+	// --warmup=auto flag sets it.
+	WarmupAuto bool
+	// Format selects the output format; see ReportFormat.
+	Format string
+	// ResultBase is ResultBaseDec or ResultBaseHex; see Stats.HexResult.
+	// Empty means ResultBaseDec.
+	ResultBase string
+	// Verbose prints each timed run's compute time as it finishes. It
+	// requires the text format.
+	Verbose bool
+	// Mem reports heap statistics read after the compute phase.
+	Mem bool
+	// HostInfo reports the machine description; see HostInfo.
+	HostInfo bool
+	// GOMAXPROCS, if positive, is applied with runtime.GOMAXPROCS before
+	// the compute phase and reported; zero leaves the default.
+	GOMAXPROCS int
+	// Concurrent marks a benchmark whose compute phase runs in parallel.
+	// It is not a flag: such benchmarks set it before calling Run, which
+	// then always reports the GOMAXPROCS in effect.
+	Concurrent bool
+	// Goroutines, if positive, is the number of goroutines the compute
+	// phase runs, reported with the results in every format. Like
+	// Concurrent it is not a flag: benchmarks with their own goroutine
+	// count, such as mutex's --goroutines, set it before calling Run.
+	Goroutines int
+	// Phases, if set, times named parts of the compute runs; see Phases.
+	// Like Concurrent it is not a flag: benchmarks that time phases set it
+	// before calling Run.
+	Phases *Phases
+	// CgroupInfo reports the cgroup CPU and memory limits; see CgroupInfo.
+	CgroupInfo bool
+	// Timeout bounds the whole compute phase; zero means no limit.
+	Timeout time.Duration
+	// Trim is the percentage of fastest and of slowest runs dropped from
+	// the mean and standard deviation; see Stats.Trim. The default 0
+	// keeps every run.
+	Trim float64
+	// Percentiles lists extra percentiles of the timed runs, each in
+	// [0, 100], to report; see Stats.Percentiles.
+	Percentiles []float64
+	// Histogram prints an ASCII histogram of the timed runs' compute
+	// times, in HistogramBuckets buckets, on stderr; see WriteHistogram.
+	// A single run has nothing to plot and prints none.
+	Histogram        bool
+	HistogramBuckets int
+	// MaxRSD, if positive, is the largest relative standard deviation, in
+	// percent, that Run accepts; noisier measurements fail. See Stats.RSD.
+	MaxRSD float64
+	// Output, if set, is a CSV file that a row for the run is appended to,
+	// in addition to the normal output; see AppendCSV.
+	Output string
+	// CPUProfile, if set, is the path the compute-phase CPU profile is
+	// written to.
+	CPUProfile string
+	// StartupProfile, if set, is the path the startup-phase CPU profile is
+	// written to. Profiling starts as soon as the --profile-startup flag
+	// is parsed and stops when Run begins, so it covers the benchmark's
+	// setup between flag.Parse and Run; see startupProfileValue.
+	StartupProfile string
+	// MemProfile, if set, is the path a heap profile is written to after
+	// the compute phase.
+	MemProfile string
+	// Seed seeds the input generators of benchmarks built on NewRand.
+	// Expected results hold only for DefaultSeed; see ValidateSeed.
+	Seed int64
+
+	// stopStartupProfile ends the profile started by --profile-startup,
+	// or is nil.
+	stopStartupProfile func() error
+
+	// envErrs holds, by flag name, the invalid environment values not
+	// overridden on the command line; see applyEnv.
+	envErrs map[string]error
+}
+
+// Result bases accepted by --result-base.
+const (
+	ResultBaseDec = "dec"
+	ResultBaseHex = "hex"
+)
+
+// RegisterFlags binds the shared benchmark flags to fs. Benchmarks call it
+// on flag.CommandLine before registering their own flags and calling
+// flag.Parse.
+//
+// The environment variables EnvIterations, EnvWarmup and EnvFormat, when
+// set and non-empty, replace the defaults of --iterations, --warmup and
+// --format. A flag on the command line still wins, so each value comes
+// from the flag if given, else the environment, else the default.
+func (o *Options) RegisterFlags(fs *flag.FlagSet) {
+	o.registerFlags(fs, os.LookupEnv)
+}
+
+// registerFlags is RegisterFlags with the environment read through lookup.
+func (o *Options) registerFlags(fs *flag.FlagSet, lookup func(string) (string, bool)) {
+	fs.IntVar(&o.Iterations, "iterations", 1, "number of timed compute runs")
+	fs.Var(warmupValue{o}, "warmup", "number of untimed runs before the timed ones, or auto to warm up until compute time stabilizes")
+	fs.StringVar(&o.Format, "format", FormatText, "output format: "+strings.Join(formats, ", "))
+	fs.StringVar(&o.ResultBase, "result-base", ResultBaseDec, "print the text format's result as dec (RESULT) or hex (RESULT_HEX)")
+	fs.BoolVar(&o.Verbose, "verbose", false, "print each timed run's compute time as it finishes (text format only)")
+	fs.BoolVar(&o.Mem, "mem", false, "report heap statistics after the compute phase")
+	fs.BoolVar(&o.HostInfo, "host-info", false, "report CPU model, CPU count, OS, architecture and Go version")
+	fs.IntVar(&o.GOMAXPROCS, "gomaxprocs", 0, "set GOMAXPROCS for the compute phase (0 = leave the default)")
+	fs.BoolVar(&o.CgroupInfo, "cgroup-info", false, "report the cgroup CPU quota and memory limit")
+	fs.Float64Var(&o.Trim, "trim", 0, "percent of fastest and of slowest runs to drop from mean and stddev, in [0, 50)")
+	fs.Var(percentilesValue{&o.Percentiles}, "percentiles", "comma-separated extra `percentiles` of the timed runs to report, e.g. 50,90,99")
+	fs.BoolVar(&o.Histogram, "histogram", false, "print a histogram of the timed runs' compute times on stderr")
+	fs.IntVar(&o.HistogramBuckets, "histogram-buckets", 10, "number of buckets for --histogram")
+	fs.Float64Var(&o.MaxRSD, "max-rsd", 0, "fail if stddev/mean of the timed runs exceeds this `percent` (0 = disabled)")
+	fs.StringVar(&o.Output, "output", "", "also append a timestamped CSV row for the run to `path`, creating it with a header if absent")
+	fs.StringVar(&o.CPUProfile, "cpuprofile", "", "write a CPU profile of the compute phase to `path`")
+	fs.StringVar(&o.MemProfile, "memprofile", "", "write a heap profile taken after the compute phase to `path`")
+	fs.Var(startupProfileValue{o}, "profile-startup", "write a CPU profile of the startup phase, from flag parsing until the compute phase, to `path`")
+	fs.Int64Var(&o.Seed, "seed", DefaultSeed, "seed for benchmarks with generated inputs; other than the default, RESULT is not checked against the expected value")
+	fs.DurationVar(&o.Timeout, "timeout", 0, "abort with FAILURE: timeout if the compute phase runs longer than this (0 = no limit)")
+	registerDescribeFlag(fs)
+	o.applyEnv(fs, lookup)
+}
+
+// validate reports the first invalid option value.
+func (o Options) validate() error {
+	if err := o.envErr(); err != nil {
+		return err
+	}
+	if o.Iterations < 1 {
+		return fmt.Errorf("--iterations must be >= 1, got %d", o.Iterations)
+	}
+	if o.Warmup < 0 {
+		return fmt.Errorf("--warmup must be >= 0, got %d", o.Warmup)
+	}
+	if o.Trim < 0 || o.Trim >= 50 {
+		return fmt.Errorf("--trim must be in [0, 50), got %g", o.Trim)
+	}
+	if o.GOMAXPROCS < 0 {
+		return fmt.Errorf("--gomaxprocs must be >= 0, got %d", o.GOMAXPROCS)
+	}
+	for _, p := range o.Percentiles {
+		if p < 0 || p > 100 {
+			return fmt.Errorf("--percentiles values must be in [0, 100], got %g", p)
+		}
+	}
+	if o.Histogram && o.HistogramBuckets < 1 {
+		return fmt.Errorf("--histogram-buckets must be >= 1, got %d", o.HistogramBuckets)
+	}
+	if o.MaxRSD < 0 {
+		return fmt.Errorf("--max-rsd must be >= 0, got %g", o.MaxRSD)
+	}
+	if o.MaxRSD > 0 && o.Iterations < 2 {
+		return fmt.Errorf("--max-rsd needs --iterations >= 2, got %d", o.Iterations)
+	}
+	if o.Timeout < 0 {
+		return fmt.Errorf("--timeout must be >= 0, got %v", o.Timeout)
+	}
+	if !slices.Contains(formats, o.Format) {
+		return unknownFormat(o.Format)
+	}
+	if o.ResultBase != "" && o.ResultBase != ResultBaseDec && o.ResultBase != ResultBaseHex {
+		return fmt.Errorf("--result-base must be dec or hex, got %q", o.ResultBase)
+	}
+	if o.Verbose && o.Format != FormatText {
+		return fmt.Errorf("--verbose needs --format=text, got %s", o.Format)
+	}
+	return nil
+}
+
+// warmupValue is the --warmup flag: a run count, stored in Warmup, or
+// "auto", which sets WarmupAuto.
+type warmupValue struct{ o *Options }
+
+func (v warmupValue) String() string {
+	switch {
+	case v.o == nil:
+		return "0"
+	case v.o.WarmupAuto:
+		return "auto"
+	}
+	return strconv.Itoa(v.o.Warmup)
+}
+
+func (v warmupValue) Set(s string) error {
+	if s == "auto" {
+		v.o.Warmup, v.o.WarmupAuto = 0, true
+		return nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return errors.New("want a run count or auto")
+	}
+	v.o.Warmup, v.o.WarmupAuto = n, false
+	return nil
+}
+
+// startupProfileValue is the --profile-startup flag. Setting it starts
+// the CPU profile at once, since the startup phase begins right after
+// flag.Parse returns and no harness code runs before it.
+type startupProfileValue struct{ o *Options }
+
+func (v startupProfileValue) String() string {
+	if v.o == nil {
+		return ""
+	}
+	return v.o.StartupProfile
+}
+
+func (v startupProfileValue) Set(path string) error {
+	if v.o.stopStartupProfile != nil {
+		// Given twice: only the last path gets the profile.
+		v.o.stopStartupProfile()
+		os.Remove(v.o.StartupProfile)
+	}
+	stop, err := StartCPUProfile(path)
+	if err != nil {
+		return err
+	}
+	v.o.StartupProfile, v.o.stopStartupProfile = path, stop
+	return nil
+}
+
+// percentilesValue is the --percentiles flag, a comma-separated list of
+// numbers.
+type percentilesValue struct{ ps *[]float64 }
+
+func (v percentilesValue) String() string {
+	if v.ps == nil {
+		return ""
+	}
+	parts := make([]string, len(*v.ps))
+	for i, p := range *v.ps {
+		parts[i] = strconv.FormatFloat(p, 'g', -1, 64)
+	}
+	return strings.Join(parts, ",")
+}
+
+func (v percentilesValue) Set(s string) error {
+	var ps []float64
+	for _, part := range strings.Split(s, ",") {
+		p, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return fmt.Errorf("invalid percentile %q", part)
+		}
+		ps = append(ps, p)
+	}
+	*v.ps = ps
+	return nil
+}
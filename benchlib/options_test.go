@@ -0,0 +1,126 @@
+package benchlib
+
+import (
+	"flag"
+	"io"
+	"slices"
+	"testing"
+)
+
+// noEnv is an environment lookup that finds nothing, so tests see the
+// built-in defaults whatever BENCH_* variables the caller has set.
+func noEnv(string) (string, bool) { return "", false }
+
+// newFlagSet returns a flag set with the shared benchmark flags bound to
+// opts, ignoring the environment.
+func newFlagSet(opts *Options) *flag.FlagSet {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	opts.registerFlags(fs, noEnv)
+	return fs
+}
+
+func TestOptionsValidate(t *testing.T) {
+	tests := []struct {
+		args    []string
+		wantErr bool
+	}{
+		{nil, false},
+		{[]string{"--iterations=30", "--format=json"}, false},
+		{[]string{"--iterations=0"}, true},
+		{[]string{"--format=xml"}, true},
+		{[]string{"--timeout=30s"}, false},
+		{[]string{"--trim=10"}, false},
+		{[]string{"--warmup=3"}, false},
+		{[]string{"--warmup=-1"}, true},
+		{[]string{"--warmup=auto"}, false},
+		{[]string{"--percentiles=50,90,99.9"}, false},
+		{[]string{"--percentiles=50,101"}, true},
+		{[]string{"--verbose", "--iterations=5"}, false},
+		{[]string{"--verbose", "--format=json"}, true},
+		{[]string{"--result-base=hex"}, false},
+		{[]string{"--result-base=oct"}, true},
+		{[]string{"--trim=50"}, true},
+		{[]string{"--trim=-1"}, true},
+		{[]string{"--timeout=-1s"}, true},
+		{[]string{"--gomaxprocs=2"}, false},
+		{[]string{"--gomaxprocs=-1"}, true},
+		{[]string{"--histogram", "--histogram-buckets=20"}, false},
+		{[]string{"--histogram", "--histogram-buckets=0"}, true},
+		{[]string{"--max-rsd=5", "--iterations=10"}, false},
+		{[]string{"--max-rsd=-1", "--iterations=10"}, true},
+		// One run has no spread to gate on.
+		{[]string{"--max-rsd=5"}, true},
+	}
+	for _, tt := range tests {
+		var opts Options
+		if err := newFlagSet(&opts).Parse(tt.args); err != nil {
+			t.Fatalf("Parse(%v): %v", tt.args, err)
+		}
+		if err := opts.validate(); (err != nil) != tt.wantErr {
+			t.Errorf("validate(%v) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+		}
+	}
+}
+
+func TestWarmupFlag(t *testing.T) {
+	var opts Options
+	fs := newFlagSet(&opts)
+	if err := fs.Parse([]string{"--warmup=auto"}); err != nil {
+		t.Fatal(err)
+	}
+	if !opts.WarmupAuto || opts.Warmup != 0 {
+		t.Errorf("--warmup=auto: Warmup = %d, WarmupAuto = %v", opts.Warmup, opts.WarmupAuto)
+	}
+	if got := fs.Lookup("warmup").Value.String(); got != "auto" {
+		t.Errorf("String() = %q, want auto", got)
+	}
+
+	// A later count overrides auto.
+	if err := fs.Parse([]string{"--warmup=3"}); err != nil {
+		t.Fatal(err)
+	}
+	if opts.WarmupAuto || opts.Warmup != 3 {
+		t.Errorf("--warmup=3: Warmup = %d, WarmupAuto = %v", opts.Warmup, opts.WarmupAuto)
+	}
+
+	if err := newFlagSet(&opts).Parse([]string{"--warmup=often"}); err == nil {
+		t.Error("--warmup=often parsed without error")
+	}
+}
+
+func TestPercentilesFlag(t *testing.T) {
+	var opts Options
+	fs := newFlagSet(&opts)
+	if err := fs.Parse([]string{"--percentiles=50, 90,99.9"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []float64{50, 90, 99.9}; !slices.Equal(opts.Percentiles, want) {
+		t.Errorf("Percentiles = %v, want %v", opts.Percentiles, want)
+	}
+	if got := fs.Lookup("percentiles").Value.String(); got != "50,90,99.9" {
+		t.Errorf("String() = %q", got)
+	}
+	if err := newFlagSet(&opts).Parse([]string{"--percentiles=50,p90"}); err == nil {
+		t.Error("--percentiles=50,p90 parsed without error")
+	}
+}
+
+func TestSeedFlag(t *testing.T) {
+	var opts Options
+	if err := newFlagSet(&opts).Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+	if opts.Seed != DefaultSeed {
+		t.Errorf("default Seed = %d, want DefaultSeed (%d)", opts.Seed, DefaultSeed)
+	}
+	if err := newFlagSet(&opts).Parse([]string{"--seed=-7"}); err != nil {
+		t.Fatal(err)
+	}
+	if opts.Seed != -7 {
+		t.Errorf("--seed=-7 gave Seed = %d", opts.Seed)
+	}
+	if err := newFlagSet(&opts).Parse([]string{"--seed=x"}); err == nil {
+		t.Error("--seed=x parsed")
+	}
+}
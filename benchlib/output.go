@@ -0,0 +1,62 @@
+package benchlib
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// AppendHeader is the header row of a file written by AppendCSV: CSVHeader
+// preceded by the time of the run and followed by the goroutine count,
+// which is empty for benchmarks that do not report one.
+var AppendHeader = append(append([]string{"timestamp"}, CSVHeader...), "goroutines")
+
+// AppendCSV appends one row for the run of benchmark name to the CSV file
+// at path, writing AppendHeader first if the file is new or empty. The
+// timestamp column is at, formatted as RFC 3339 in UTC.
+//
+// The file is held under an exclusive advisory lock (flock on Unix) while
+// it is checked and written, so concurrent benchmarks, for example under
+// cmd/runall, can share one file without interleaving rows or writing the
+// header twice. On other systems the lock is a no-op.
+func AppendCSV(path, name string, startup time.Duration, s Stats, at time.Time) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening results file: %w", err)
+	}
+	// Closing f releases the lock.
+	defer f.Close()
+	if err := lockFile(f); err != nil {
+		return fmt.Errorf("locking results file: %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("opening results file: %w", err)
+	}
+	var buf bytes.Buffer
+	cw := csv.NewWriter(&buf)
+	if info.Size() == 0 {
+		cw.Write(AppendHeader)
+	}
+	goroutines := ""
+	if s.Goroutines > 0 {
+		goroutines = strconv.Itoa(s.Goroutines)
+	}
+	row := append([]string{at.UTC().Format(time.RFC3339)}, CSVRecord(name, startup.Microseconds(), s.Mean.Microseconds(), s.Result)...)
+	cw.Write(append(row, goroutines))
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("writing results file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing results file: %w", err)
+	}
+	return nil
+}
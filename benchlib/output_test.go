@@ -0,0 +1,129 @@
+package benchlib
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func readCSV(t *testing.T, path string) [][]string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("csv.ReadAll: %v", err)
+	}
+	return records
+}
+
+func TestAppendCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.csv")
+	s := summarize(us(23891))
+	s.Result = 9592
+	at := time.Date(2026, 3, 1, 9, 30, 0, 0, time.FixedZone("CET", 3600))
+
+	for i := 0; i < 2; i++ {
+		if err := AppendCSV(path, "primes", 8234*time.Microsecond, s, at); err != nil {
+			t.Fatalf("AppendCSV: %v", err)
+		}
+	}
+
+	want := [][]string{
+		AppendHeader,
+		{"2026-03-01T08:30:00Z", "primes", "8234", "23891", "9592", ""},
+		{"2026-03-01T08:30:00Z", "primes", "8234", "23891", "9592", ""},
+	}
+	if got := readCSV(t, path); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("records = %q, want %q", got, want)
+	}
+}
+
+func TestAppendCSVGoroutines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.csv")
+	s := summarize(us(61000))
+	s.Result = 5000000
+	s.Goroutines = 8
+	if err := AppendCSV(path, "mutex", 0, s, time.Unix(0, 0)); err != nil {
+		t.Fatalf("AppendCSV: %v", err)
+	}
+	records := readCSV(t, path)
+	if want := []string{"1970-01-01T00:00:00Z", "mutex", "0", "61000", "5000000", "8"}; len(records) != 2 || !slices.Equal(records[1], want) {
+		t.Errorf("records = %q, want the header and %q", records, want)
+	}
+}
+
+func TestAppendCSVConcurrent(t *testing.T) {
+	// The race that matters is on a new file: every writer sees it empty
+	// unless the lock serializes them. Release all writers at once, onto a
+	// fresh file each round.
+	const rounds, writers, rows = 20, 8, 5
+	for round := 0; round < rounds; round++ {
+		path := filepath.Join(t.TempDir(), "results.csv")
+		start := make(chan struct{})
+		var wg sync.WaitGroup
+		errs := make(chan error, writers*rows)
+		for w := 0; w < writers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				for i := 0; i < rows; i++ {
+					s := Stats{Mean: time.Duration(i) * time.Microsecond, Result: int64(w)}
+					errs <- AppendCSV(path, "bench-"+strconv.Itoa(w), 0, s, time.Now())
+				}
+			}()
+		}
+		close(start)
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			if err != nil {
+				t.Fatalf("AppendCSV: %v", err)
+			}
+		}
+
+		records := readCSV(t, path)
+		if len(records) != 1+writers*rows {
+			t.Fatalf("round %d: got %d records, want a header and %d rows", round, len(records), writers*rows)
+		}
+		if !slices.Equal(records[0], AppendHeader) {
+			t.Errorf("round %d: first record = %q, want the header", round, records[0])
+		}
+		for i, rec := range records[1:] {
+			if !strings.HasPrefix(rec[1], "bench-") {
+				t.Fatalf("round %d: record %d = %q, not a data row", round, i+2, rec)
+			}
+		}
+	}
+}
+
+func TestRunOutputKeepsStdout(t *testing.T) {
+	fakeClock(t, us(300)...)
+	path := filepath.Join(t.TempDir(), "results.csv")
+	var opts Options
+	if err := newFlagSet(&opts).Parse([]string{"--output=" + path}); err != nil {
+		t.Fatal(err)
+	}
+
+	out := captureStdout(t, func() {
+		Run("primes", opts, 0, func() int64 { return 9592 })
+	})
+
+	if !strings.Contains(out, "COMPUTE_TIME_US: 300\n") || !strings.Contains(out, "RESULT: 9592\n") {
+		t.Errorf("stdout missing the standard lines:\n%s", out)
+	}
+	records := readCSV(t, path)
+	if len(records) != 2 || !slices.Equal(records[1][1:], []string{"primes", "0", "300", "9592", ""}) {
+		t.Errorf("records = %q, want the header and one primes row", records)
+	}
+}
@@ -0,0 +1,77 @@
+package benchlib
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// Phase is the mean duration of one named part of the timed compute runs.
+type Phase struct {
+	Name     string
+	Duration time.Duration
+}
+
+// key returns the text format prefix for p, such as PARSE_TIME.
+func (p Phase) key() string {
+	return strings.ToUpper(p.Name) + "_TIME"
+}
+
+// Phases times named parts of a benchmark's compute runs, such as decoding
+// its input, so that a single compute time does not hide where the time
+// goes. A benchmark sets Options.Phases to a new Phases before calling Run
+// and calls Measure or Add from its compute function; Run then reports the
+// mean of each phase over the timed runs in Stats.Phases. The phases are
+// parts of the compute time, not added to it.
+//
+// Names should be short lower-case words: the text format prints phase
+// "parse" as PARSE_TIME_US and PARSE_TIME_NS.
+type Phases struct {
+	// names lists the phases in the order they were first added.
+	names []string
+	// runs holds the time added to each phase, one map per compute run.
+	runs []map[string]time.Duration
+}
+
+// startRun begins the record of a new compute run.
+func (p *Phases) startRun() {
+	p.runs = append(p.runs, make(map[string]time.Duration))
+}
+
+// Add adds d to phase name in the current compute run.
+func (p *Phases) Add(name string, d time.Duration) {
+	if len(p.runs) == 0 {
+		p.startRun()
+	}
+	if !slices.Contains(p.names, name) {
+		p.names = append(p.names, name)
+	}
+	p.runs[len(p.runs)-1][name] += d
+}
+
+// Measure runs fn and adds its wall-clock duration to phase name.
+func (p *Phases) Measure(name string, fn func()) {
+	t0 := now()
+	fn()
+	p.Add(name, now().Sub(t0))
+}
+
+// Mean returns the mean of each phase over the last n compute runs, in the
+// order the phases were first added; a run that did not add to a phase
+// counts as zero for it. Run uses it to drop the warmup runs. It returns
+// nil if no phase was added or n is not positive.
+func (p *Phases) Mean(n int) []Phase {
+	n = min(n, len(p.runs))
+	if len(p.names) == 0 || n <= 0 {
+		return nil
+	}
+	phases := make([]Phase, len(p.names))
+	for i, name := range p.names {
+		var sum time.Duration
+		for _, run := range p.runs[len(p.runs)-n:] {
+			sum += run[name]
+		}
+		phases[i] = Phase{Name: name, Duration: sum / time.Duration(n)}
+	}
+	return phases
+}
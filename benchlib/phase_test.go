@@ -0,0 +1,124 @@
+package benchlib
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPhasesMean(t *testing.T) {
+	var p Phases
+	p.startRun()
+	p.Add("parse", 10*time.Microsecond)
+	p.Add("sum", 6*time.Microsecond)
+	p.startRun()
+	p.Add("parse", 30*time.Microsecond)
+	p.Add("parse", 10*time.Microsecond)
+	p.startRun()
+	p.Add("parse", 10*time.Microsecond)
+
+	tests := []struct {
+		n    int
+		want []Phase
+	}{
+		{3, []Phase{{"parse", 20 * time.Microsecond}, {"sum", 2 * time.Microsecond}}},
+		// The last two runs never added to "sum".
+		{2, []Phase{{"parse", 25 * time.Microsecond}, {"sum", 0}}},
+		{10, []Phase{{"parse", 20 * time.Microsecond}, {"sum", 2 * time.Microsecond}}},
+		{0, nil},
+	}
+	for _, tt := range tests {
+		if got := p.Mean(tt.n); !slices.Equal(got, tt.want) {
+			t.Errorf("Mean(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+	if got := new(Phases).Mean(3); got != nil {
+		t.Errorf("Mean of no phases = %v, want nil", got)
+	}
+}
+
+func TestPhasesMeasure(t *testing.T) {
+	fakeClock(t, us(40)...)
+	var p Phases
+	ran := false
+	p.Measure("parse", func() { ran = true })
+	if !ran {
+		t.Fatal("Measure did not call fn")
+	}
+	if got, want := p.Mean(1), []Phase{{"parse", 40 * time.Microsecond}}; !slices.Equal(got, want) {
+		t.Errorf("Mean = %v, want %v", got, want)
+	}
+}
+
+func TestRunReportsPhases(t *testing.T) {
+	// One warmup run, then two timed ones; only the timed ones count.
+	parse := us(1000, 10, 30)
+	calls := 0
+	opts := Options{Iterations: 2, Warmup: 1, Format: FormatText, Phases: new(Phases)}
+	var stats Stats
+	out := captureStdout(t, func() {
+		stats = Run("jsonparse", opts, 0, func() int64 {
+			opts.Phases.Add("parse", parse[calls])
+			calls++
+			return 1
+		})
+	})
+	if want := []Phase{{"parse", 20 * time.Microsecond}}; !slices.Equal(stats.Phases, want) {
+		t.Errorf("Stats.Phases = %v, want %v", stats.Phases, want)
+	}
+	if want := "STARTUP_TIME_NS: 0\nPARSE_TIME_US: 20\nPARSE_TIME_NS: 20000\nCOMPUTE_TIME_US: "; !strings.Contains(out, want) {
+		t.Errorf("output missing the phase lines between startup and compute:\n%s", out)
+	}
+}
+
+func TestReportFormatPhases(t *testing.T) {
+	s := summarize(us(23891))
+	s.Result = 9592
+	s.Phases = []Phase{{"parse", 20000 * time.Microsecond}, {"sum", 3891 * time.Microsecond}}
+
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{FormatText, []string{"PARSE_TIME_US: 20000\nPARSE_TIME_NS: 20000000\nSUM_TIME_US: 3891\nSUM_TIME_NS: 3891000\nCOMPUTE_TIME_US: 23891\n"}},
+		{FormatJSON, []string{`"result":9592,"phases_us":{"parse":20000,"sum":3891}}`}},
+		{FormatJSONL, []string{`"phases_us":{"parse":20000,"sum":3891}`}},
+		{FormatCSV, []string{"benchmark,startup_us,compute_us,result,parse_us,sum_us\nprimes,8234,23891,9592,20000,3891\n"}},
+		{FormatMarkdown, []string{"| benchmark | startup_us | compute_us | result | parse_us | sum_us |\n", "| primes | 8234 | 23891 | 9592 | 20000 | 3891 |\n"}},
+		{FormatPrometheus, []string{
+			"# TYPE benchmark_phase_microseconds gauge\n",
+			`benchmark_phase_microseconds{benchmark="primes",phase="parse"} 20000` + "\n",
+			`benchmark_phase_microseconds{benchmark="primes",phase="sum"} 3891` + "\n",
+		}},
+		{FormatBenchstat, []string{"ns/op\t  20000000 parse-ns/op\t   3891000 sum-ns/op\n"}},
+	}
+	for _, tt := range tests {
+		out := captureStdout(t, func() {
+			if err := ReportFormat(tt.format, "primes", 8234*time.Microsecond, s); err != nil {
+				t.Fatalf("ReportFormat(%s): %v", tt.format, err)
+			}
+		})
+		for _, want := range tt.want {
+			if !strings.Contains(out, want) {
+				t.Errorf("%s output missing %q:\n%s", tt.format, want, out)
+			}
+		}
+	}
+}
+
+func TestReportFormatWithoutPhases(t *testing.T) {
+	s := summarize(us(23891))
+	for _, format := range formats {
+		out := captureStdout(t, func() {
+			if err := ReportFormat(format, "primes", 0, s); err != nil {
+				t.Fatalf("ReportFormat(%s): %v", format, err)
+			}
+		})
+		for _, key := range []string{"phases_us", "result,", "result | ", "benchmark_phase_", "-ns/op"} {
+			if strings.Contains(out, key) {
+				t.Errorf("%s output has %q without phases:\n%s", format, key, out)
+			}
+		}
+	}
+}
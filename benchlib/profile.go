@@ -0,0 +1,50 @@
+package benchlib
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+// StartCPUProfile starts writing a CPU profile to path and returns the
+// function that stops profiling and flushes and closes the file. Run calls
+// it around the compute phase only, so startup work is not profiled; the
+// --profile-startup flag uses it for the startup phase instead.
+func StartCPUProfile(path string) (stop func() error, err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("creating CPU profile: %w", err)
+	}
+	if err := pprof.StartCPUProfile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("starting CPU profile: %w", err)
+	}
+	return func() error {
+		pprof.StopCPUProfile()
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("writing CPU profile: %w", err)
+		}
+		return nil
+	}, nil
+}
+
+// WriteHeapProfile forces a garbage collection, so the profile reflects the
+// live heap rather than garbage awaiting collection, and writes a heap
+// profile to path. Run calls it after the compute phase, outside the timed
+// region.
+func WriteHeapProfile(path string) error {
+	runtime.GC()
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating heap profile: %w", err)
+	}
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		f.Close()
+		return fmt.Errorf("writing heap profile: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing heap profile: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package benchlib
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// spin burns CPU for long enough that the profiler takes samples.
+func spin() int64 {
+	var x int64
+	for i := int64(0); i < 200_000_000; i++ {
+		x += i ^ (x >> 3)
+	}
+	return x & 1
+}
+
+// checkProfile fails t unless path holds a non-empty, parseable pprof
+// profile. Profiles are gzip-compressed protobufs; when the go command is
+// available, `go tool pprof` must also accept the file.
+func checkProfile(t *testing.T, path string) {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("profile is not gzip data: %v", err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("decompressing profile: %v", err)
+	}
+	if len(data) == 0 {
+		t.Fatal("profile is empty")
+	}
+
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Log("go command not found; skipping go tool pprof check")
+		return
+	}
+	if out, err := exec.Command(gobin, "tool", "pprof", "-raw", path).CombinedOutput(); err != nil {
+		t.Fatalf("go tool pprof rejected the profile: %v\n%s", err, out)
+	}
+}
+
+func TestRunCPUProfile(t *testing.T) {
+	if testing.Short() {
+		t.Skip("profiles a CPU-bound loop")
+	}
+	path := filepath.Join(t.TempDir(), "cpu.pprof")
+	var opts Options
+	if err := newFlagSet(&opts).Parse([]string{"--cpuprofile=" + path}); err != nil {
+		t.Fatal(err)
+	}
+
+	captureStdout(t, func() { Run("spin", opts, 0, spin) })
+
+	checkProfile(t, path)
+}
+
+func TestRunMemProfileWithMem(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mem.pprof")
+	var opts Options
+	if err := newFlagSet(&opts).Parse([]string{"--mem", "--memprofile=" + path}); err != nil {
+		t.Fatal(err)
+	}
+
+	var keep [][]byte
+	out := captureStdout(t, func() {
+		Run("alloc", opts, 0, func() int64 {
+			for i := 0; i < 64; i++ {
+				keep = append(keep, make([]byte, 64<<10))
+			}
+			return int64(len(keep))
+		})
+	})
+
+	checkProfile(t, path)
+	if !strings.Contains(out, "\nALLOC_BYTES: ") {
+		t.Errorf("--mem output missing alongside --memprofile:\n%s", out)
+	}
+}
+
+func TestStartCPUProfileBadPath(t *testing.T) {
+	if _, err := StartCPUProfile(filepath.Join(t.TempDir(), "missing", "cpu.pprof")); err == nil {
+		t.Error("StartCPUProfile into a missing directory succeeded")
+	}
+}
+
+func TestRunStartupAndComputeProfiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("profiles a CPU-bound loop")
+	}
+	dir := t.TempDir()
+	startupPath, computePath := filepath.Join(dir, "startup.pprof"), filepath.Join(dir, "cpu.pprof")
+	var opts Options
+	if err := newFlagSet(&opts).Parse([]string{"--profile-startup=" + startupPath, "--cpuprofile=" + computePath}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Heavy setup, as a benchmark generating its input would do.
+	setup := spin()
+	captureStdout(t, func() { Run("spin", opts, 0, func() int64 { return spin() + setup }) })
+
+	checkProfile(t, startupPath)
+	checkProfile(t, computePath)
+}
+
+func TestProfileStartupBadPath(t *testing.T) {
+	var opts Options
+	path := filepath.Join(t.TempDir(), "missing", "startup.pprof")
+	if err := newFlagSet(&opts).Parse([]string{"--profile-startup=" + path}); err == nil {
+		opts.stopStartupProfile()
+		t.Error("--profile-startup into a missing directory parsed")
+	}
+}
+
+// failProfileHelperEnv makes the re-executed test binary in
+// TestRunCPUProfileOnComputeFailure profile a run that calls Failf, writing
+// the profile to the path it holds.
+const failProfileHelperEnv = "BENCHLIB_FAIL_PROFILE_HELPER"
+
+func TestRunCPUProfileOnComputeFailure(t *testing.T) {
+	if path := os.Getenv(failProfileHelperEnv); path != "" {
+		opts := Options{Iterations: 1, Format: FormatText, CPUProfile: path}
+		Run("spin", opts, 0, func() int64 {
+			spin()
+			Failf("checksum overflow")
+			return 0
+		})
+		os.Exit(0) // not reached
+	}
+	if testing.Short() {
+		t.Skip("profiles a CPU-bound loop")
+	}
+
+	path := filepath.Join(t.TempDir(), "cpu.pprof")
+	cmd := exec.Command(os.Args[0], "-test.run=^TestRunCPUProfileOnComputeFailure$")
+	cmd.Env = append(os.Environ(), failProfileHelperEnv+"="+path)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Fatalf("failing run: err = %v, want exit status 1; stderr: %s", err, stderr.String())
+	}
+	if want := "FAILURE: checksum overflow\n"; stderr.String() != want {
+		t.Errorf("stderr = %q, want %q", stderr.String(), want)
+	}
+	checkProfile(t, path)
+}
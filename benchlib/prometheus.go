@@ -0,0 +1,94 @@
+package benchlib
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// PrometheusSample is one benchmark's values for the prometheus format.
+type PrometheusSample struct {
+	Benchmark string
+	StartupUS int64
+	ComputeUS int64
+	Result    int64
+	// Phases are the sample's timed phases, if any.
+	Phases []Phase
+	// Goroutines is the sample's goroutine count, or 0 if it has none.
+	Goroutines int
+}
+
+// promFamily is one metric family of the prometheus format.
+type promFamily struct {
+	name, help string
+	value      func(PrometheusSample) int64
+}
+
+var promFamilies = []promFamily{
+	{"benchmark_startup_microseconds", "Duration of the benchmark startup phase in microseconds.",
+		func(s PrometheusSample) int64 { return s.StartupUS }},
+	{"benchmark_compute_microseconds", "Mean duration of the timed compute runs in microseconds.",
+		func(s PrometheusSample) int64 { return s.ComputeUS }},
+	{"benchmark_result", "RESULT value of the benchmark, for spotting wrong answers.",
+		func(s PrometheusSample) int64 { return s.Result }},
+}
+
+// promLabelEscaper escapes a label value as the exposition format requires:
+// backslash, double quote and line feed.
+var promLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WritePrometheus writes samples in the Prometheus text exposition format,
+// as accepted by a pushgateway. Each metric family gets its HELP and TYPE
+// lines once, followed by one gauge per sample labeled with the benchmark
+// name, so tools that run several benchmarks can expose them together.
+// When any sample has phases, a benchmark_phase_microseconds family
+// follows with one gauge per phase, also labeled with the phase name; when
+// any has a goroutine count, a benchmark_goroutines family follows with a
+// gauge for each such sample.
+func WritePrometheus(w io.Writer, samples []PrometheusSample) error {
+	bw := bufio.NewWriter(w)
+	for _, f := range promFamilies {
+		fmt.Fprintf(bw, "# HELP %s %s\n", f.name, f.help)
+		fmt.Fprintf(bw, "# TYPE %s gauge\n", f.name)
+		for _, s := range samples {
+			fmt.Fprintf(bw, "%s{benchmark=\"%s\"} %d\n", f.name, promLabelEscaper.Replace(s.Benchmark), f.value(s))
+		}
+	}
+	if slices.ContainsFunc(samples, func(s PrometheusSample) bool { return len(s.Phases) > 0 }) {
+		const name = "benchmark_phase_microseconds"
+		fmt.Fprintf(bw, "# HELP %s Mean duration of a timed phase of the compute runs in microseconds.\n", name)
+		fmt.Fprintf(bw, "# TYPE %s gauge\n", name)
+		for _, s := range samples {
+			for _, p := range s.Phases {
+				fmt.Fprintf(bw, "%s{benchmark=\"%s\",phase=\"%s\"} %d\n", name,
+					promLabelEscaper.Replace(s.Benchmark), promLabelEscaper.Replace(p.Name), p.Duration.Microseconds())
+			}
+		}
+	}
+	if slices.ContainsFunc(samples, func(s PrometheusSample) bool { return s.Goroutines > 0 }) {
+		const name = "benchmark_goroutines"
+		fmt.Fprintf(bw, "# HELP %s Number of goroutines the compute runs used.\n", name)
+		fmt.Fprintf(bw, "# TYPE %s gauge\n", name)
+		for _, s := range samples {
+			if s.Goroutines > 0 {
+				fmt.Fprintf(bw, "%s{benchmark=\"%s\"} %d\n", name, promLabelEscaper.Replace(s.Benchmark), s.Goroutines)
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// printPrometheus writes the prometheus format for a single run.
+func printPrometheus(w io.Writer, name string, startup time.Duration, s Stats) error {
+	return WritePrometheus(w, []PrometheusSample{{
+		Benchmark:  name,
+		StartupUS:  startup.Microseconds(),
+		ComputeUS:  s.Mean.Microseconds(),
+		Result:     s.Result,
+		Phases:     s.Phases,
+		Goroutines: s.Goroutines,
+	}})
+}
@@ -0,0 +1,156 @@
+package benchlib
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Grammar of the Prometheus text exposition format, version 0.0.4.
+var (
+	promComment = regexp.MustCompile(`^# (HELP|TYPE) ([a-zA-Z_:][a-zA-Z0-9_:]*) (.*)$`)
+	promSample  = regexp.MustCompile(`^([a-zA-Z_:][a-zA-Z0-9_:]*)` +
+		`(?:\{((?:[a-zA-Z_][a-zA-Z0-9_]*="(?:[^"\\\n]|\\[\\"n])*")(?:,[a-zA-Z_][a-zA-Z0-9_]*="(?:[^"\\\n]|\\[\\"n])*")*,?)?\})?` +
+		` (\S+)(?: -?[0-9]+)?$`)
+	promLabel    = regexp.MustCompile(`([a-zA-Z_][a-zA-Z0-9_]*)="((?:[^"\\\n]|\\[\\"n])*)"`)
+	promHelpText = regexp.MustCompile(`^(?:[^\\]|\\[\\n])*$`)
+)
+
+// parseExposition validates out against the text format grammar and its
+// ordering rules (at most one HELP and TYPE per family, both before the
+// family's samples, samples of a family contiguous), and returns the sample
+// values keyed by metric name and unescaped benchmark label.
+func parseExposition(t *testing.T, out string) map[string]map[string]float64 {
+	t.Helper()
+	samples := make(map[string]map[string]float64)
+	seenComment := make(map[string]bool)
+	finished := make(map[string]bool)
+	current := ""
+	if !strings.HasSuffix(out, "\n") {
+		t.Fatal("exposition does not end with a newline")
+	}
+	for i, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		if m := promComment.FindStringSubmatch(line); m != nil {
+			kind, name, rest := m[1], m[2], m[3]
+			key := kind + " " + name
+			if seenComment[key] {
+				t.Errorf("line %d: duplicate %s", i+1, key)
+			}
+			if _, ok := samples[name]; ok {
+				t.Errorf("line %d: %s after samples of %s", i+1, kind, name)
+			}
+			seenComment[key] = true
+			switch kind {
+			case "TYPE":
+				if !strings.Contains(" counter gauge histogram summary untyped ", " "+rest+" ") {
+					t.Errorf("line %d: invalid type %q", i+1, rest)
+				}
+			case "HELP":
+				if !promHelpText.MatchString(rest) {
+					t.Errorf("line %d: badly escaped help text %q", i+1, rest)
+				}
+			}
+			continue
+		}
+		m := promSample.FindStringSubmatch(line)
+		if m == nil {
+			t.Errorf("line %d does not match the sample grammar: %q", i+1, line)
+			continue
+		}
+		name, labels, value := m[1], m[2], m[3]
+		if name != current {
+			if finished[name] {
+				t.Errorf("line %d: samples of %s are not contiguous", i+1, name)
+			}
+			finished[current] = true
+			current = name
+		}
+		if !seenComment["TYPE "+name] {
+			t.Errorf("line %d: sample of %s before its TYPE line", i+1, name)
+		}
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			t.Errorf("line %d: invalid value %q", i+1, value)
+		}
+		var bench string
+		for _, lm := range promLabel.FindAllStringSubmatch(labels, -1) {
+			if lm[1] == "benchmark" {
+				bench = strings.NewReplacer(`\\`, `\`, `\"`, `"`, `\n`, "\n").Replace(lm[2])
+			}
+		}
+		if samples[name] == nil {
+			samples[name] = make(map[string]float64)
+		}
+		samples[name][bench] = v
+	}
+	return samples
+}
+
+func TestWritePrometheusGrammar(t *testing.T) {
+	var buf bytes.Buffer
+	err := WritePrometheus(&buf, []PrometheusSample{
+		{Benchmark: "primes", StartupUS: 8, ComputeUS: 23891, Result: 9592},
+		{Benchmark: "nbody", StartupUS: 0, ComputeUS: 410000, Result: -169083134},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := parseExposition(t, buf.String())
+	want := map[string]map[string]float64{
+		"benchmark_startup_microseconds": {"primes": 8, "nbody": 0},
+		"benchmark_compute_microseconds": {"primes": 23891, "nbody": 410000},
+		"benchmark_result":               {"primes": 9592, "nbody": -169083134},
+	}
+	for name, byBench := range want {
+		for bench, v := range byBench {
+			if got[name][bench] != v {
+				t.Errorf("%s{benchmark=%q} = %v, want %v", name, bench, got[name][bench], v)
+			}
+		}
+	}
+	if !strings.Contains(buf.String(), "benchmark_compute_microseconds{benchmark=\"primes\"} 23891\n") {
+		t.Errorf("output missing the primes compute sample:\n%s", buf.String())
+	}
+}
+
+func TestWritePrometheusEscapesLabels(t *testing.T) {
+	name := "sort \"big\"\\n\nnext"
+	var buf bytes.Buffer
+	if err := WritePrometheus(&buf, []PrometheusSample{{Benchmark: name, ComputeUS: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if want := `benchmark_compute_microseconds{benchmark="sort \"big\"\\n\nnext"} 1`; !strings.Contains(buf.String(), want) {
+		t.Errorf("output missing %s:\n%s", want, buf.String())
+	}
+	got := parseExposition(t, buf.String())
+	if v, ok := got["benchmark_compute_microseconds"][name]; !ok || v != 1 {
+		t.Errorf("escaped label did not round-trip: %v", got)
+	}
+}
+
+func TestReportFormatPrometheus(t *testing.T) {
+	s := summarize(us(23891))
+	s.Result = 9592
+	out := captureStdout(t, func() {
+		if err := ReportFormat(FormatPrometheus, "primes", 8234*time.Microsecond, s); err != nil {
+			t.Fatal(err)
+		}
+	})
+	want := `# HELP benchmark_startup_microseconds Duration of the benchmark startup phase in microseconds.
+# TYPE benchmark_startup_microseconds gauge
+benchmark_startup_microseconds{benchmark="primes"} 8234
+# HELP benchmark_compute_microseconds Mean duration of the timed compute runs in microseconds.
+# TYPE benchmark_compute_microseconds gauge
+benchmark_compute_microseconds{benchmark="primes"} 23891
+# HELP benchmark_result RESULT value of the benchmark, for spotting wrong answers.
+# TYPE benchmark_result gauge
+benchmark_result{benchmark="primes"} 9592
+`
+	if out != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+	parseExposition(t, out)
+}
@@ -0,0 +1,71 @@
+package benchlib
+
+import "math/rand"
+
+// DefaultSeed seeds every benchmark input generator unless --seed
+// overrides it, so runs in different languages see the same inputs.
+const DefaultSeed int64 = 42
+
+// splitMix64 is Vigna's SplitMix64 generator. It is used instead of the
+// math/rand default source because it is a few lines in any language, which
+// lets ports of a benchmark reproduce its inputs bit for bit.
+type splitMix64 struct {
+	state uint64
+}
+
+func (s *splitMix64) Seed(seed int64) { s.state = uint64(seed) }
+
+func (s *splitMix64) Uint64() uint64 {
+	s.state += 0x9e3779b97f4a7c15
+	z := s.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+func (s *splitMix64) Int63() int64 { return int64(s.Uint64() >> 1) }
+
+// NewRand returns a generator backed by SplitMix64 seeded with seed.
+func NewRand(seed int64) *rand.Rand {
+	return rand.New(&splitMix64{state: uint64(seed)})
+}
+
+// RandomInts returns n integers uniformly distributed in [0, 2^31), taken
+// as the top 31 bits of successive Uint64 outputs.
+func RandomInts(r *rand.Rand, n int) []int {
+	xs := make([]int, n)
+	for i := range xs {
+		xs[i] = int(r.Uint64() >> 33)
+	}
+	return xs
+}
+
+// RandomFloat returns a value uniformly distributed in [0, 1), built from
+// the top 53 bits of one Uint64 output. Unlike r.Float64, the construction
+// is exact and trivially portable.
+func RandomFloat(r *rand.Rand) float64 {
+	return float64(r.Uint64()>>11) / (1 << 53)
+}
+
+// RandomFloats returns n successive RandomFloat values.
+func RandomFloats(r *rand.Rand, n int) []float64 {
+	xs := make([]float64, n)
+	for i := range xs {
+		xs[i] = RandomFloat(r)
+	}
+	return xs
+}
+
+// RandomBytes returns n bytes taken little-endian, eight at a time, from
+// successive Uint64 outputs.
+func RandomBytes(r *rand.Rand, n int) []byte {
+	buf := make([]byte, n)
+	for i := 0; i < n; i += 8 {
+		v := r.Uint64()
+		for j := i; j < i+8 && j < n; j++ {
+			buf[j] = byte(v)
+			v >>= 8
+		}
+	}
+	return buf
+}
@@ -0,0 +1,71 @@
+package benchlib
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitMix64ReferenceVector(t *testing.T) {
+	// First outputs of the reference C implementation seeded with 1234567.
+	want := []uint64{
+		6457827717110365317,
+		3203168211198807973,
+		9817491932198370423,
+		4593380528125082431,
+		16408922859458223821,
+	}
+	r := NewRand(1234567)
+	for i, w := range want {
+		if got := r.Uint64(); got != w {
+			t.Errorf("output %d = %d, want %d", i, got, w)
+		}
+	}
+}
+
+func TestSameSeedReproducesSequences(t *testing.T) {
+	ints1, ints2 := RandomInts(NewRand(DefaultSeed), 1000), RandomInts(NewRand(DefaultSeed), 1000)
+	if !slices.Equal(ints1, ints2) {
+		t.Error("RandomInts differs between generators with the same seed")
+	}
+	floats1, floats2 := RandomFloats(NewRand(DefaultSeed), 1000), RandomFloats(NewRand(DefaultSeed), 1000)
+	if !slices.Equal(floats1, floats2) {
+		t.Error("RandomFloats differs between generators with the same seed")
+	}
+
+	if !slices.Equal(RandomBytes(NewRand(DefaultSeed), 1001), RandomBytes(NewRand(DefaultSeed), 1001)) {
+		t.Error("RandomBytes differs between generators with the same seed")
+	}
+
+	if slices.Equal(ints1, RandomInts(NewRand(DefaultSeed+1), 1000)) {
+		t.Error("RandomInts identical for different seeds")
+	}
+}
+
+func TestRandomRanges(t *testing.T) {
+	r := NewRand(DefaultSeed)
+	for _, x := range RandomInts(r, 10000) {
+		if x < 0 || x >= 1<<31 {
+			t.Fatalf("RandomInts value %d outside [0, 2^31)", x)
+		}
+	}
+	for _, x := range RandomFloats(r, 10000) {
+		if x < 0 || x >= 1 {
+			t.Fatalf("RandomFloats value %v outside [0, 1)", x)
+		}
+	}
+}
+
+func TestRandomBytesLittleEndian(t *testing.T) {
+	v := NewRand(DefaultSeed).Uint64()
+	b := RandomBytes(NewRand(DefaultSeed), 3)
+	if len(b) != 3 || b[0] != byte(v) || b[1] != byte(v>>8) || b[2] != byte(v>>16) {
+		t.Errorf("RandomBytes = %x, want low bytes of %016x", b, v)
+	}
+}
+
+func TestRandomFloatTopBits(t *testing.T) {
+	v := NewRand(DefaultSeed).Uint64()
+	if got, want := RandomFloat(NewRand(DefaultSeed)), float64(v>>11)/(1<<53); got != want {
+		t.Errorf("RandomFloat = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,162 @@
+package benchlib
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Benchmark categories, used by cmd/runall --category to select a group.
+const (
+	// CategoryNumeric is integer and floating-point arithmetic.
+	CategoryNumeric = "numeric"
+	// CategoryMemory is allocation, pointer chasing and cache behavior.
+	CategoryMemory = "memory"
+	// CategoryConcurrency is goroutines, channels and locks.
+	CategoryConcurrency = "concurrency"
+	// CategoryRecursion is deep or branching recursive search.
+	CategoryRecursion = "recursion"
+	// CategoryAlgorithm is sorting, graphs and combinatorics.
+	CategoryAlgorithm = "algorithm"
+	// CategoryText is string processing, parsing and encoding.
+	CategoryText = "text"
+	// CategoryIO is file system reads and writes.
+	CategoryIO = "io"
+)
+
+// Categories lists every benchmark category, in the order cmd/runall
+// --list prints them.
+var Categories = []string{
+	CategoryNumeric,
+	CategoryMemory,
+	CategoryConcurrency,
+	CategoryRecursion,
+	CategoryAlgorithm,
+	CategoryText,
+	CategoryIO,
+}
+
+// Info is the metadata a benchmark registers about itself.
+type Info struct {
+	// Name is the benchmark's name, which is also its directory under
+	// benchmarks/.
+	Name string `json:"name"`
+	// Category is one of Categories.
+	Category string `json:"category"`
+	// Expected is the RESULT of a run with default flags.
+	Expected int64 `json:"expected"`
+}
+
+// Registry holds benchmark metadata keyed by name. The zero value is an
+// empty registry ready to use.
+type Registry struct {
+	infos map[string]Info
+}
+
+// Add registers info. It fails if the name is empty or already registered,
+// or the category is not one of Categories.
+func (r *Registry) Add(info Info) error {
+	if info.Name == "" {
+		return fmt.Errorf("benchmark has no name")
+	}
+	if !slices.Contains(Categories, info.Category) {
+		return fmt.Errorf("benchmark %q: unknown category %q (want one of %s)", info.Name, info.Category, strings.Join(Categories, ", "))
+	}
+	if _, ok := r.infos[info.Name]; ok {
+		return fmt.Errorf("benchmark %q registered twice", info.Name)
+	}
+	if r.infos == nil {
+		r.infos = make(map[string]Info)
+	}
+	r.infos[info.Name] = info
+	return nil
+}
+
+// Lookup returns the metadata registered under name.
+func (r *Registry) Lookup(name string) (Info, bool) {
+	info, ok := r.infos[name]
+	return info, ok
+}
+
+// All returns every registered benchmark, sorted by name.
+func (r *Registry) All() []Info {
+	infos := make([]Info, 0, len(r.infos))
+	for _, info := range r.infos {
+		infos = append(infos, info)
+	}
+	slices.SortFunc(infos, func(a, b Info) int { return strings.Compare(a.Name, b.Name) })
+	return infos
+}
+
+// InCategory returns the registered benchmarks in category, sorted by
+// name.
+func (r *Registry) InCategory(category string) []Info {
+	return FilterCategory(r.All(), category)
+}
+
+// FilterCategory returns the elements of infos in category, keeping their
+// order.
+func FilterCategory(infos []Info, category string) []Info {
+	var out []Info
+	for _, info := range infos {
+		if info.Category == category {
+			out = append(out, info)
+		}
+	}
+	return out
+}
+
+// registry holds the benchmarks registered by this process with Register.
+var registry Registry
+
+// Register records info in the process-wide registry, for --describe, and
+// returns it. Benchmarks call it from an init function. It panics on an
+// invalid or duplicate registration, which is a programming error.
+func Register(info Info) Info {
+	if err := registry.Add(info); err != nil {
+		panic("benchlib: " + err.Error())
+	}
+	return info
+}
+
+// writeDescription writes each benchmark in r as one JSON object per line,
+// sorted by name.
+func writeDescription(w io.Writer, r *Registry) error {
+	enc := json.NewEncoder(w)
+	for _, info := range r.All() {
+		if err := enc.Encode(info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// describeFlag is the --describe flag. Setting it prints the registered
+// metadata and exits, like -help, so no startup or compute work runs.
+type describeFlag struct{}
+
+func (describeFlag) IsBoolFlag() bool { return true }
+
+func (describeFlag) String() string { return "false" }
+
+func (describeFlag) Set(s string) error {
+	on, err := strconv.ParseBool(s)
+	if err != nil || !on {
+		return err
+	}
+	if err := writeDescription(os.Stdout, &registry); err != nil {
+		return err
+	}
+	os.Exit(0)
+	return nil
+}
+
+// registerDescribeFlag adds --describe to fs.
+func registerDescribeFlag(fs *flag.FlagSet) {
+	fs.Var(describeFlag{}, "describe", "print the benchmark's registered name, category and expected result as JSON and exit")
+}
@@ -0,0 +1,154 @@
+package benchlib
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRegistryAdd(t *testing.T) {
+	var r Registry
+	for _, info := range []Info{
+		{Name: "primes", Category: CategoryNumeric, Expected: 9592},
+		{Name: "fibonacci", Category: CategoryRecursion, Expected: 9227465},
+		{Name: "nbody", Category: CategoryNumeric, Expected: -169083134},
+	} {
+		if err := r.Add(info); err != nil {
+			t.Fatalf("Add(%+v): %v", info, err)
+		}
+	}
+
+	info, ok := r.Lookup("fibonacci")
+	if !ok || info.Expected != 9227465 || info.Category != CategoryRecursion {
+		t.Errorf("Lookup(fibonacci) = %+v, %v", info, ok)
+	}
+	if _, ok := r.Lookup("mutex"); ok {
+		t.Error("Lookup found an unregistered benchmark")
+	}
+	var names []string
+	for _, info := range r.All() {
+		names = append(names, info.Name)
+	}
+	if want := []string{"fibonacci", "nbody", "primes"}; !slices.Equal(names, want) {
+		t.Errorf("All names = %v, want %v", names, want)
+	}
+}
+
+func TestRegistryRejectsDuplicates(t *testing.T) {
+	var r Registry
+	if err := r.Add(Info{Name: "primes", Category: CategoryNumeric, Expected: 9592}); err != nil {
+		t.Fatal(err)
+	}
+	err := r.Add(Info{Name: "primes", Category: CategoryAlgorithm, Expected: 1})
+	if err == nil || !strings.Contains(err.Error(), "registered twice") {
+		t.Fatalf("duplicate Add error = %v", err)
+	}
+	if info, _ := r.Lookup("primes"); info.Expected != 9592 {
+		t.Errorf("duplicate replaced the original: %+v", info)
+	}
+}
+
+func TestRegistryRejectsInvalidInfo(t *testing.T) {
+	var r Registry
+	for _, info := range []Info{
+		{Category: CategoryNumeric},
+		{Name: "primes", Category: "fast"},
+		{Name: "primes"},
+	} {
+		if err := r.Add(info); err == nil {
+			t.Errorf("Add(%+v) succeeded", info)
+		}
+	}
+	if len(r.All()) != 0 {
+		t.Errorf("invalid registrations were kept: %v", r.All())
+	}
+}
+
+func TestRegistryInCategory(t *testing.T) {
+	var r Registry
+	for _, info := range []Info{
+		{Name: "primes", Category: CategoryNumeric},
+		{Name: "mutex", Category: CategoryConcurrency},
+		{Name: "mandelbrot", Category: CategoryNumeric},
+		{Name: "channels", Category: CategoryConcurrency},
+	} {
+		if err := r.Add(info); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := map[string][]string{
+		CategoryNumeric:     {"mandelbrot", "primes"},
+		CategoryConcurrency: {"channels", "mutex"},
+		CategoryText:        nil,
+	}
+	for category, want := range tests {
+		var got []string
+		for _, info := range r.InCategory(category) {
+			got = append(got, info.Name)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("InCategory(%s) = %v, want %v", category, got, want)
+		}
+	}
+}
+
+func TestRegisterPanicsOnDuplicate(t *testing.T) {
+	orig := registry
+	t.Cleanup(func() { registry = orig })
+	registry = Registry{}
+
+	Register(Info{Name: "primes", Category: CategoryNumeric})
+	defer func() {
+		if recover() == nil {
+			t.Error("second Register did not panic")
+		}
+	}()
+	Register(Info{Name: "primes", Category: CategoryNumeric})
+}
+
+func TestWriteDescription(t *testing.T) {
+	var r Registry
+	r.Add(Info{Name: "primes", Category: CategoryNumeric, Expected: 9592})
+	var buf bytes.Buffer
+	if err := writeDescription(&buf, &r); err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"name":"primes","category":"numeric","expected":9592}` + "\n"; buf.String() != want {
+		t.Errorf("description = %q, want %q", buf.String(), want)
+	}
+}
+
+// describeHelperEnv makes the re-executed test binary parse --describe in
+// TestDescribeFlagExits.
+const describeHelperEnv = "BENCHLIB_DESCRIBE_HELPER"
+
+func TestDescribeFlagExits(t *testing.T) {
+	if os.Getenv(describeHelperEnv) != "" {
+		registry = Registry{}
+		Register(Info{Name: "primes", Category: CategoryNumeric, Expected: 9592})
+		var opts Options
+		fs := flag.NewFlagSet("primes", flag.ContinueOnError)
+		opts.registerFlags(fs, noEnv)
+		fs.Parse([]string{"--describe", "--iterations=0"})
+		os.Exit(3) // not reached: --describe exits during Parse
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestDescribeFlagExits$")
+	cmd.Env = append(os.Environ(), describeHelperEnv+"=1")
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("helper: %v", err)
+	}
+	var info Info
+	if err := json.Unmarshal(out, &info); err != nil {
+		t.Fatalf("--describe output %q: %v", out, err)
+	}
+	if want := (Info{Name: "primes", Category: CategoryNumeric, Expected: 9592}); info != want {
+		t.Errorf("--describe = %+v, want %+v", info, want)
+	}
+}
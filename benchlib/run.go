@@ -0,0 +1,191 @@
+package benchlib
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Run is the shared benchmark harness. It times fn according to opts and
+// prints the outcome under the benchmark's name in the selected format.
+// The collected stats are returned so the caller can validate the result.
+//
+// opts.Warmup untimed runs precede the opts.Iterations timed ones; see
+// RunWarm. With opts.WarmupAuto, warmup instead continues until compute
+// time stabilizes, as described at RunAutoWarm; if it never does, Run
+// notes on stderr that the cap was reached and measures anyway. With
+// opts.CPUProfile set, only these runs are profiled, and the profile is
+// completed even if fn fails the benchmark with Failf; opts.MemProfile is
+// written after them. A startup profile begun by --profile-startup is
+// stopped and written first, so the two CPU profiles never overlap. A
+// positive opts.GOMAXPROCS is applied before the first run; the value in
+// effect is reported when it was set or opts.Concurrent is true.
+//
+// With opts.Phases set, each run of fn starts a new record in it, and the
+// phases' means over the timed runs are reported in Stats.Phases.
+//
+// With opts.Verbose, a "RUN i: COMPUTE_TIME_US: N" line is printed as each
+// timed run finishes, ahead of the usual report.
+//
+// With opts.Histogram and more than one timed run, a histogram of every
+// run's compute time, trimmed or not, is written to stderr by
+// WriteHistogram after the report, so stdout keeps its standard lines.
+//
+// With opts.Output set, a row is also appended to that CSV file by
+// AppendCSV.
+//
+// An interrupt (SIGINT) during the runs lets the current run finish and
+// then reports the timed runs completed so far, marked PARTIAL, before
+// exiting with status 130 without appending to opts.Output or returning.
+// If no timed run had completed, only a note is printed on stderr. A
+// second interrupt terminates the process immediately.
+//
+// Invalid options are reported on stderr and terminate the process with
+// exit status 2, before any compute work is done. If the compute phase
+// exceeds opts.Timeout, Run prints "FAILURE: timeout" on stderr and exits
+// with status 1. With opts.MaxRSD set, a measurement whose relative
+// standard deviation exceeds it is reported as usual and then fails with
+// "FAILURE: measurement too noisy (rsd=...)" and exit status 1.
+func Run(name string, opts Options, startup time.Duration, fn func() int64) Stats {
+	if opts.stopStartupProfile != nil {
+		if err := opts.stopStartupProfile(); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			os.Exit(1)
+		}
+	}
+	if err := opts.validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		os.Exit(2)
+	}
+
+	if opts.GOMAXPROCS > 0 {
+		runtime.GOMAXPROCS(opts.GOMAXPROCS)
+	}
+	procs := runtime.GOMAXPROCS(0)
+
+	var memBase MemBaseline
+	if opts.Mem {
+		memBase = StartMem()
+	}
+
+	stopProfile := func() error { return nil }
+	if opts.CPUProfile != "" {
+		stop, err := StartCPUProfile(opts.CPUProfile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			os.Exit(1)
+		}
+		stopProfile = sync.OnceValue(stop)
+		// A Failf inside fn exits without returning here.
+		atFail = func() { stopProfile() }
+	}
+
+	// The first interrupt stops the runs after the current one; restoring
+	// the default handler then lets a second one kill the process at once.
+	ctx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt)
+	go func() {
+		<-ctx.Done()
+		stopSignals()
+	}()
+
+	if opts.Phases != nil {
+		compute := fn
+		fn = func() int64 {
+			opts.Phases.startRun()
+			return compute()
+		}
+	}
+
+	var stats Stats
+	_, err := RunWithTimeout(opts.Timeout, func() int64 {
+		var progress progressFunc
+		if opts.Verbose {
+			progress = printRun
+		}
+		if opts.WarmupAuto {
+			stats = runAutoWarm(opts.Iterations, fn, progress, ctx.Done())
+		} else {
+			stats = runWarm(opts.Warmup, opts.Iterations, fn, progress, ctx.Done())
+		}
+		if len(stats.Samples) > 0 {
+			stats = stats.Trim(opts.Trim)
+		}
+		return stats.Result
+	})
+	stopSignals()
+	// Stop profiling before anything can exit the process, so the profile
+	// is complete even when the run times out or the caller's validation
+	// fails.
+	atFail = nil
+	if perr := stopProfile(); perr != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, perr)
+		os.Exit(1)
+	}
+	if err != nil {
+		Failf("timeout")
+	}
+	if stats.Partial && len(stats.Samples) == 0 {
+		fmt.Fprintf(os.Stderr, "%s: interrupted during warmup, no timed runs to report\n", name)
+		os.Exit(130)
+	}
+	if opts.WarmupAuto && !stats.WarmupStable {
+		fmt.Fprintf(os.Stderr, "%s: warmup did not stabilize within %d runs (rsd >= %g%%)\n", name, AutoWarmupMaxRuns, AutoWarmupRSD)
+	}
+	if opts.Phases != nil {
+		stats.Phases = opts.Phases.Mean(len(stats.Samples))
+	}
+	stats.Percentiles = opts.Percentiles
+	stats.HexResult = opts.ResultBase == ResultBaseHex
+	if opts.Concurrent || opts.GOMAXPROCS > 0 {
+		stats.GOMAXPROCS = procs
+	}
+	stats.Goroutines = max(opts.Goroutines, 0)
+	if opts.Mem {
+		m := ReadMemSince(memBase)
+		stats.Mem = &m
+	}
+	if opts.MemProfile != "" {
+		if err := WriteHeapProfile(opts.MemProfile); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			os.Exit(1)
+		}
+	}
+	if opts.HostInfo {
+		h := HostInfo()
+		stats.Host = &h
+	}
+	if opts.CgroupInfo {
+		l := CgroupInfo()
+		stats.Limits = &l
+	}
+	if err := ReportFormat(opts.Format, name, startup, stats); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		os.Exit(1)
+	}
+	if opts.Histogram && len(stats.Samples) > 1 {
+		WriteHistogram(os.Stderr, stats.Samples, opts.HistogramBuckets)
+	}
+	if stats.Partial {
+		fmt.Fprintf(os.Stderr, "%s: interrupted after %d of %d timed runs\n", name, len(stats.Samples), opts.Iterations)
+		os.Exit(130)
+	}
+	if opts.Output != "" {
+		if err := AppendCSV(opts.Output, name, startup, stats, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+			os.Exit(1)
+		}
+	}
+	if err := stats.checkRSD(opts.MaxRSD); err != nil {
+		Failf("%v", err)
+	}
+	return stats
+}
+
+// printRun writes the --verbose line for one timed run.
+func printRun(run int, d time.Duration) {
+	fmt.Printf("RUN %d: COMPUTE_TIME_US: %d\n", run, d.Microseconds())
+}
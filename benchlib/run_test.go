@@ -0,0 +1,102 @@
+package benchlib
+
+import (
+	"encoding/json"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// keepGOMAXPROCS restores the GOMAXPROCS setting when the test ends.
+func keepGOMAXPROCS(t *testing.T) {
+	t.Helper()
+	orig := runtime.GOMAXPROCS(0)
+	t.Cleanup(func() { runtime.GOMAXPROCS(orig) })
+}
+
+func TestRunAppliesGOMAXPROCS(t *testing.T) {
+	keepGOMAXPROCS(t)
+	want := 1
+	if runtime.GOMAXPROCS(0) == 1 {
+		want = 2
+	}
+
+	var opts Options
+	if err := newFlagSet(&opts).Parse([]string{"--gomaxprocs=" + strconv.Itoa(want)}); err != nil {
+		t.Fatal(err)
+	}
+	var during int
+	out := captureStdout(t, func() {
+		Run("mutex", opts, 0, func() int64 {
+			during = runtime.GOMAXPROCS(0)
+			return 1
+		})
+	})
+	if during != want {
+		t.Errorf("GOMAXPROCS during compute = %d, want %d", during, want)
+	}
+	if line := "GOMAXPROCS: " + strconv.Itoa(want) + "\n"; !strings.Contains(out, line) {
+		t.Errorf("output missing %q:\n%s", line, out)
+	}
+}
+
+func TestRunReportsGOMAXPROCSForConcurrent(t *testing.T) {
+	keepGOMAXPROCS(t)
+	procs := runtime.GOMAXPROCS(0)
+
+	for _, concurrent := range []bool{false, true} {
+		opts := Options{Iterations: 1, Format: FormatText, Concurrent: concurrent}
+		out := captureStdout(t, func() {
+			Run("channels", opts, 0, func() int64 { return 1 })
+		})
+		if got := strings.Contains(out, "GOMAXPROCS: "+strconv.Itoa(procs)+"\n"); got != concurrent {
+			t.Errorf("Concurrent=%v: GOMAXPROCS line present = %v\n%s", concurrent, got, out)
+		}
+		if runtime.GOMAXPROCS(0) != procs {
+			t.Errorf("Concurrent=%v changed GOMAXPROCS to %d", concurrent, runtime.GOMAXPROCS(0))
+		}
+	}
+}
+
+func TestRunGOMAXPROCSJSON(t *testing.T) {
+	keepGOMAXPROCS(t)
+	opts := Options{Iterations: 1, Format: FormatJSON, GOMAXPROCS: 1}
+	out := captureStdout(t, func() {
+		Run("mutex", opts, 0, func() int64 { return 1 })
+	})
+	var got struct {
+		GOMAXPROCS int `json:"gomaxprocs"`
+	}
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.GOMAXPROCS != 1 {
+		t.Errorf("gomaxprocs = %d, want 1 in %s", got.GOMAXPROCS, out)
+	}
+}
+
+func TestRunVerbose(t *testing.T) {
+	run := func(args ...string) string {
+		fakeClock(t, us(300, 100, 200)...)
+		var opts Options
+		if err := newFlagSet(&opts).Parse(append([]string{"--iterations=3"}, args...)); err != nil {
+			t.Fatal(err)
+		}
+		return captureStdout(t, func() {
+			Run("primes", opts, 0, func() int64 { return 9592 })
+		})
+	}
+	plain := run()
+	verbose := run("--verbose")
+
+	runs := "RUN 1: COMPUTE_TIME_US: 300\n" +
+		"RUN 2: COMPUTE_TIME_US: 100\n" +
+		"RUN 3: COMPUTE_TIME_US: 200\n"
+	if verbose != runs+plain {
+		t.Errorf("--verbose output:\n%s\nwant the per-run lines followed by the plain output:\n%s%s", verbose, runs, plain)
+	}
+	if !strings.Contains(plain, "COMPUTE_TIME_US: 200\nCOMPUTE_TIME_NS: 200000\nRESULT: 9592\n") {
+		t.Errorf("summary lines missing:\n%s", plain)
+	}
+}
@@ -0,0 +1,443 @@
+package benchlib
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Stats summarizes the compute durations of repeated runs.
+type Stats struct {
+	// Samples holds every compute duration in run order.
+	Samples []time.Duration
+	// Result is the RESULT value shared by every run.
+	Result int64
+	// Phases holds the mean duration of each phase timed with
+	// Options.Phases over the timed runs, or nil.
+	Phases []Phase
+
+	Min    time.Duration
+	Max    time.Duration
+	Mean   time.Duration
+	Median time.Duration
+	// StdDev is the sample standard deviation (n-1 denominator); it is zero
+	// for a single run.
+	StdDev time.Duration
+	P95    time.Duration
+
+	// HexResult prints the result in the text format as RESULT_HEX, the
+	// int64 bit pattern as 16 hex digits, instead of RESULT. Checksums
+	// such as hash digests read more clearly, and without a sign, that way.
+	// Other formats always carry the result as a decimal number.
+	HexResult bool
+
+	// Percentiles lists extra percentiles that ReportStats prints after
+	// P95; see Percentile.
+	Percentiles []float64
+
+	// Trimmed is the number of samples excluded from Mean and StdDev by
+	// Trim; Min, Max, Median and P95 always cover every sample.
+	Trimmed int
+
+	// WarmupRuns is the number of warmup runs RunAutoWarm made, or 0 for a
+	// fixed warmup count. WarmupStable reports whether they stabilized
+	// before the AutoWarmupMaxRuns cap.
+	WarmupRuns   int
+	WarmupStable bool
+
+	// Partial reports that the runs were interrupted before the requested
+	// iteration count; Samples holds only the runs that completed, and is
+	// empty if the interrupt came during warmup.
+	Partial bool
+
+	// GOMAXPROCS is the GOMAXPROCS setting the runs were made with, or 0
+	// when it is not reported.
+	GOMAXPROCS int
+
+	// Goroutines is the number of goroutines the compute phase ran, or 0
+	// when it is not reported.
+	Goroutines int
+
+	// Mem is the heap summary taken after the compute phase, or nil when
+	// memory tracking is disabled.
+	Mem *MemStats
+
+	// Host describes the machine the runs were made on, or nil when
+	// --host-info is not set.
+	Host *Host
+
+	// Limits holds the cgroup resource limits, or nil when --cgroup-info
+	// is not set.
+	Limits *Limits
+}
+
+// RunN runs fn iterations times, timing each call with Measure, and
+// summarizes the compute durations.
+//
+// Every run must return the same result; RunN panics if they diverge, since
+// a benchmark whose answer changes between runs is broken.
+func RunN(iterations int, fn func() int64) Stats {
+	return runN(iterations, fn, nil, nil)
+}
+
+// progressFunc is called after each timed run with its 1-based number and
+// compute duration.
+type progressFunc func(run int, d time.Duration)
+
+// stopped reports whether stop has been closed. A nil stop never is.
+func stopped(stop <-chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// runN is RunN calling progress, if non-nil, after every timed run. Once
+// stop is closed it finishes the run in progress, always completing at
+// least one, and returns the stats gathered so far marked Partial.
+func runN(iterations int, fn func() int64, progress progressFunc, stop <-chan struct{}) Stats {
+	if iterations < 1 {
+		panic(fmt.Sprintf("benchlib: iterations must be >= 1, got %d", iterations))
+	}
+
+	samples := make([]time.Duration, 0, iterations)
+	var result int64
+	partial := false
+	for i := 0; i < iterations; i++ {
+		if i > 0 && stopped(stop) {
+			partial = true
+			break
+		}
+		d, r := Measure(fn)
+		if i == 0 {
+			result = r
+		} else if r != result {
+			panic(fmt.Sprintf("benchlib: run %d returned RESULT %d, run 1 returned %d", i+1, r, result))
+		}
+		samples = append(samples, d)
+		if progress != nil {
+			progress(i+1, d)
+		}
+	}
+
+	s := summarize(samples)
+	s.Result = result
+	s.Partial = partial
+	return s
+}
+
+// RunWarm calls fn warmup times untimed, to fill caches and fault in pages,
+// and then runs RunN(iterations, fn). Warmup timings are discarded, but
+// their results are checked: every warmup run must return the RESULT of
+// the timed runs, so a benchmark that is wrong only when cold still fails.
+// RunWarm panics on divergence, like RunN.
+func RunWarm(warmup, iterations int, fn func() int64) Stats {
+	return runWarm(warmup, iterations, fn, nil, nil)
+}
+
+// runWarm is RunWarm reporting the timed runs to progress and stopping
+// early, as runN does, when stop is closed. An interrupted warmup returns
+// Partial stats with no samples.
+func runWarm(warmup, iterations int, fn func() int64, progress progressFunc, stop <-chan struct{}) Stats {
+	if warmup < 0 {
+		panic(fmt.Sprintf("benchlib: warmup must be >= 0, got %d", warmup))
+	}
+	var want int64
+	for i := 0; i < warmup; i++ {
+		if stopped(stop) {
+			return Stats{Partial: true}
+		}
+		r := fn()
+		if i == 0 {
+			want = r
+		} else if r != want {
+			panic(fmt.Sprintf("benchlib: warmup run %d returned RESULT %d, warmup run 1 returned %d", i+1, r, want))
+		}
+	}
+
+	s := runN(iterations, fn, progress, stop)
+	if warmup > 0 && s.Result != want {
+		panic(fmt.Sprintf("benchlib: timed runs returned RESULT %d, warmup runs returned %d", s.Result, want))
+	}
+	return s
+}
+
+// Stopping criteria for RunAutoWarm.
+const (
+	// AutoWarmupWindow is the number of most recent warmup runs whose
+	// spread decides whether compute time has stabilized.
+	AutoWarmupWindow = 5
+	// AutoWarmupRSD is the relative standard deviation, in percent, below
+	// which the window counts as stable.
+	AutoWarmupRSD = 2.0
+	// AutoWarmupMaxRuns caps the number of warmup runs.
+	AutoWarmupMaxRuns = 50
+)
+
+// RunAutoWarm warms fn up until its compute time stabilizes and then runs
+// RunN(iterations, fn).
+//
+// Each warmup run is timed with Measure. After run n, for n >=
+// AutoWarmupWindow, the sample standard deviation of runs n-K+1..n (K =
+// AutoWarmupWindow) is divided by their mean; warmup stops as soon as this
+// RSD is strictly below AutoWarmupRSD percent, or unconditionally after
+// AutoWarmupMaxRuns runs. So at least K and at most AutoWarmupMaxRuns warmup
+// runs are made. The count is recorded in WarmupRuns and whether the RSD
+// criterion was met in WarmupStable. As with RunWarm, warmup timings are
+// discarded, and RunAutoWarm panics if any run's result diverges.
+func RunAutoWarm(iterations int, fn func() int64) Stats {
+	return runAutoWarm(iterations, fn, nil, nil)
+}
+
+// runAutoWarm is RunAutoWarm reporting the timed runs to progress and
+// stopping early like runWarm.
+func runAutoWarm(iterations int, fn func() int64, progress progressFunc, stop <-chan struct{}) Stats {
+	var samples []time.Duration
+	var want int64
+	stable := false
+	for len(samples) < AutoWarmupMaxRuns {
+		if stopped(stop) {
+			return Stats{Partial: true, WarmupRuns: len(samples)}
+		}
+		d, r := Measure(fn)
+		if len(samples) == 0 {
+			want = r
+		} else if r != want {
+			panic(fmt.Sprintf("benchlib: warmup run %d returned RESULT %d, warmup run 1 returned %d", len(samples)+1, r, want))
+		}
+		samples = append(samples, d)
+		if len(samples) >= AutoWarmupWindow && summarize(samples[len(samples)-AutoWarmupWindow:]).RSD() < AutoWarmupRSD {
+			stable = true
+			break
+		}
+	}
+
+	s := runN(iterations, fn, progress, stop)
+	if s.Result != want {
+		panic(fmt.Sprintf("benchlib: timed runs returned RESULT %d, warmup runs returned %d", s.Result, want))
+	}
+	s.WarmupRuns, s.WarmupStable = len(samples), stable
+	return s
+}
+
+// summarize computes Stats over samples, which must be non-empty.
+func summarize(samples []time.Duration) Stats {
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+
+	mean, stddev := meanStdDev(sorted)
+	return Stats{
+		Samples: samples,
+		Min:     sorted[0],
+		Max:     sorted[len(sorted)-1],
+		Mean:    mean,
+		Median:  percentile(sorted, 50),
+		StdDev:  stddev,
+		P95:     percentile(sorted, 95),
+	}
+}
+
+// meanStdDev returns the mean and sample standard deviation of samples,
+// which must be non-empty.
+func meanStdDev(samples []time.Duration) (mean, stddev time.Duration) {
+	var sum float64
+	for _, d := range samples {
+		sum += float64(d)
+	}
+	m := sum / float64(len(samples))
+
+	var sd float64
+	if len(samples) > 1 {
+		var sq float64
+		for _, d := range samples {
+			diff := float64(d) - m
+			sq += diff * diff
+		}
+		sd = math.Sqrt(sq / float64(len(samples)-1))
+	}
+	return time.Duration(math.Round(m)), time.Duration(math.Round(sd))
+}
+
+// Trim returns s with Mean and StdDev recomputed as a trimmed mean: the
+// fastest and slowest pct percent of samples are discarded first. pct is
+// the share cut from each end, in [0, 50); 0 leaves s unchanged.
+//
+// The count cut from each end is rounded down and capped so at least one
+// sample always remains, so trimming a handful of runs never fails.
+func (s Stats) Trim(pct float64) Stats {
+	k := trimCount(len(s.Samples), pct)
+	if k == 0 {
+		return s
+	}
+	sorted := slices.Clone(s.Samples)
+	slices.Sort(sorted)
+	s.Mean, s.StdDev = meanStdDev(sorted[k : len(sorted)-k])
+	s.Trimmed = 2 * k
+	return s
+}
+
+// tQuantile975 holds the 0.975 quantile of Student's t distribution for 1
+// to 30 degrees of freedom, to four decimals.
+var tQuantile975 = [...]float64{
+	12.7062, 4.3027, 3.1824, 2.7764, 2.5706, 2.4469, 2.3646, 2.3060, 2.2622, 2.2281,
+	2.2010, 2.1788, 2.1604, 2.1448, 2.1314, 2.1199, 2.1098, 2.1009, 2.0930, 2.0860,
+	2.0796, 2.0739, 2.0687, 2.0639, 2.0595, 2.0555, 2.0518, 2.0484, 2.0452, 2.0423,
+}
+
+// normalQuantile975 is the 0.975 quantile of the standard normal
+// distribution, used beyond the t table.
+const normalQuantile975 = 1.9600
+
+// tCritical returns the two-sided 95% critical value for df degrees of
+// freedom: from the t table up to 30, and the normal 1.96 above. At the
+// crossover, df = 31, the normal value is 4% below the exact 2.0395, and
+// the gap shrinks as df grows.
+func tCritical(df int) float64 {
+	if df <= len(tQuantile975) {
+		return tQuantile975[df-1]
+	}
+	return normalQuantile975
+}
+
+// CI95 returns the 95% confidence interval for the mean compute time, in
+// microseconds: Mean ± t·StdDev/√n, where n is the number of samples
+// behind Mean (those left after Trim) and t is tCritical(n−1). With fewer
+// than two samples there is no spread to estimate, and both ends are Mean.
+func (s Stats) CI95() (lo, hi float64) {
+	mean := float64(s.Mean) / float64(time.Microsecond)
+	n := len(s.Samples) - s.Trimmed
+	if n < 2 {
+		return mean, mean
+	}
+	half := tCritical(n-1) * float64(s.StdDev) / float64(time.Microsecond) / math.Sqrt(float64(n))
+	return mean - half, mean + half
+}
+
+// Percentile returns the p-th percentile of the samples, 0 <= p <= 100,
+// interpolating linearly between the two closest ranks as P95 does: on
+// the sorted samples x[0..n-1] it is x[r] + f·(x[r+1] − x[r]) for
+// p/100·(n−1) = r + f.
+func (s Stats) Percentile(p float64) time.Duration {
+	sorted := slices.Clone(s.Samples)
+	slices.Sort(sorted)
+	return percentile(sorted, p)
+}
+
+// percentileKey returns the output key of the p-th percentile, such as
+// COMPUTE_TIME_US_P90. A decimal point becomes an underscore, so 99.9 is
+// COMPUTE_TIME_US_P99_9.
+func percentileKey(p float64) string {
+	return "COMPUTE_TIME_US_P" + strings.ReplaceAll(strconv.FormatFloat(p, 'f', -1, 64), ".", "_")
+}
+
+// RSD returns the relative standard deviation StdDev/Mean as a
+// percentage, or 0 when Mean is zero.
+func (s Stats) RSD() float64 {
+	if s.Mean == 0 {
+		return 0
+	}
+	return float64(s.StdDev) / float64(s.Mean) * 100
+}
+
+// checkRSD reports an error if s is noisier than maxPct percent RSD. A
+// maxPct of zero disables the check.
+func (s Stats) checkRSD(maxPct float64) error {
+	if maxPct > 0 && s.RSD() > maxPct {
+		return fmt.Errorf("measurement too noisy (rsd=%.2f%%, max %g%%)", s.RSD(), maxPct)
+	}
+	return nil
+}
+
+// trimCount returns how many samples Trim discards from each end of n.
+func trimCount(n int, pct float64) int {
+	k := int(float64(n) * pct / 100)
+	if 2*k >= n {
+		k = (n - 1) / 2
+	}
+	return k
+}
+
+// percentile returns the p-th percentile of sorted using linear
+// interpolation between the two closest ranks, so P50 of an even-length
+// sample is the mean of the middle pair.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	frac := rank - float64(lo)
+	v := float64(sorted[lo]) + frac*float64(sorted[hi]-sorted[lo])
+	return time.Duration(math.Round(v))
+}
+
+// ReportStats prints the standardized output for a multi-run benchmark.
+// COMPUTE_TIME_US carries the mean, preceded by a <NAME>_TIME_US and
+// _NS pair for each of s.Phases; when more than one run was made the
+// distribution follows as additional COMPUTE_TIME_US_* lines, including
+// any s.Percentiles, and memory
+// lines follow when s.Mem is set. A WARMUP_RUNS line follows the three
+// standard lines after an automatic warmup. HOST, LIMITS, GOMAXPROCS and
+// GOROUTINES lines precede everything when s.Host, s.Limits, s.GOMAXPROCS
+// and s.Goroutines are set. Parsers of the
+// three-line format ignore the extra keys.
+func ReportStats(startup time.Duration, s Stats) {
+	if s.Host != nil {
+		printHost(*s.Host)
+	}
+	if s.Limits != nil {
+		printLimits(*s.Limits)
+	}
+	if s.GOMAXPROCS > 0 {
+		fmt.Printf("GOMAXPROCS: %d\n", s.GOMAXPROCS)
+	}
+	if s.Goroutines > 0 {
+		fmt.Printf("GOROUTINES: %d\n", s.Goroutines)
+	}
+	printPhase("STARTUP_TIME", startup)
+	for _, p := range s.Phases {
+		printPhase(p.key(), p.Duration)
+	}
+	printPhase("COMPUTE_TIME", s.Mean)
+	if s.HexResult {
+		fmt.Printf("RESULT_HEX: 0x%016x\n", uint64(s.Result))
+	} else {
+		fmt.Printf("RESULT: %d\n", s.Result)
+	}
+	if s.WarmupRuns > 0 {
+		fmt.Printf("WARMUP_RUNS: %d\n", s.WarmupRuns)
+	}
+	if s.Partial {
+		fmt.Println("PARTIAL: true")
+	}
+	if len(s.Samples) > 1 {
+		printDistribution(s)
+	}
+	if s.Mem != nil {
+		printMem(*s.Mem)
+	}
+}
+
+// printDistribution writes the COMPUTE_TIME_US_* summary lines.
+func printDistribution(s Stats) {
+	fmt.Printf("ITERATIONS: %d\n", len(s.Samples))
+	fmt.Printf("COMPUTE_TIME_US_MIN: %d\n", s.Min.Microseconds())
+	fmt.Printf("COMPUTE_TIME_US_MAX: %d\n", s.Max.Microseconds())
+	fmt.Printf("COMPUTE_TIME_US_MEDIAN: %d\n", s.Median.Microseconds())
+	fmt.Printf("COMPUTE_TIME_US_STDDEV: %d\n", s.StdDev.Microseconds())
+	lo, hi := s.CI95()
+	fmt.Printf("COMPUTE_TIME_US_CI95: [%.1f, %.1f]\n", lo, hi)
+	fmt.Printf("COMPUTE_TIME_US_P95: %d\n", s.P95.Microseconds())
+	for _, p := range s.Percentiles {
+		// P95 is always printed.
+		if p != 95 {
+			fmt.Printf("%s: %d\n", percentileKey(p), s.Percentile(p).Microseconds())
+		}
+	}
+	if s.Trimmed > 0 {
+		fmt.Printf("TRIMMED_SAMPLES: %d\n", s.Trimmed)
+	}
+}
@@ -0,0 +1,516 @@
+package benchlib
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClock makes each successive Measure call observe the next duration in
+// durations. It restores the real clock when the test ends.
+func fakeClock(t *testing.T, durations ...time.Duration) {
+	t.Helper()
+	base := time.Unix(0, 0)
+	var calls int
+	now = func() time.Time {
+		// Measure calls now twice per run: start, then end.
+		run, end := calls/2, calls%2 == 1
+		calls++
+		var elapsed time.Duration
+		for _, d := range durations[:run] {
+			elapsed += d
+		}
+		if end {
+			elapsed += durations[run]
+		}
+		return base.Add(elapsed)
+	}
+	t.Cleanup(func() { now = time.Now })
+}
+
+func us(values ...int) []time.Duration {
+	out := make([]time.Duration, len(values))
+	for i, v := range values {
+		out[i] = time.Duration(v) * time.Microsecond
+	}
+	return out
+}
+
+func TestRunNStats(t *testing.T) {
+	// Ten samples, deliberately out of order: sorted they are 10..100.
+	fakeClock(t, us(50, 10, 90, 30, 70, 20, 100, 40, 80, 60)...)
+
+	s := RunN(10, func() int64 { return 9592 })
+
+	if s.Result != 9592 {
+		t.Errorf("Result = %d, want 9592", s.Result)
+	}
+	if len(s.Samples) != 10 || s.Samples[0] != 50*time.Microsecond {
+		t.Errorf("Samples = %v, want 10 samples in run order", s.Samples)
+	}
+	checks := []struct {
+		name string
+		got  time.Duration
+		want time.Duration
+	}{
+		{"Min", s.Min, 10 * time.Microsecond},
+		{"Max", s.Max, 100 * time.Microsecond},
+		{"Mean", s.Mean, 55 * time.Microsecond},
+		// Middle pair 50 and 60.
+		{"Median", s.Median, 55 * time.Microsecond},
+		// rank 0.95*9 = 8.55 → 90 + 0.55*(100-90).
+		{"P95", s.P95, 95500 * time.Nanosecond},
+		// sqrt(8250/9) µs ≈ 30.2765µs.
+		{"StdDev", s.StdDev, 30277 * time.Nanosecond},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestRunNSingleIteration(t *testing.T) {
+	fakeClock(t, us(42)...)
+
+	s := RunN(1, func() int64 { return 1 })
+
+	for name, got := range map[string]time.Duration{
+		"Min": s.Min, "Max": s.Max, "Mean": s.Mean, "Median": s.Median, "P95": s.P95,
+	} {
+		if got != 42*time.Microsecond {
+			t.Errorf("%s = %v, want 42µs", name, got)
+		}
+	}
+	if s.StdDev != 0 {
+		t.Errorf("StdDev = %v, want 0", s.StdDev)
+	}
+}
+
+func TestRunNPanicsOnDivergentResult(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("RunN did not panic on divergent results")
+		}
+		if msg, _ := r.(string); !strings.Contains(msg, "run 3 returned RESULT 3") {
+			t.Errorf("panic = %v, want it to name run 3", r)
+		}
+	}()
+
+	calls := int64(0)
+	RunN(3, func() int64 {
+		calls++
+		if calls == 3 {
+			return 3
+		}
+		return 1
+	})
+}
+
+func TestRunNRejectsZeroIterations(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("RunN(0) did not panic")
+		}
+	}()
+	RunN(0, func() int64 { return 0 })
+}
+
+func TestRunWarmExcludesWarmupTimings(t *testing.T) {
+	// The warmup runs call fn directly, so the fake clock only advances
+	// for the three timed runs.
+	fakeClock(t, us(10, 20, 30)...)
+
+	calls := 0
+	s := RunWarm(2, 3, func() int64 {
+		calls++
+		return 9592
+	})
+
+	if calls != 5 {
+		t.Errorf("fn called %d times, want 2 warmup + 3 timed", calls)
+	}
+	if len(s.Samples) != 3 || s.Min != 10*time.Microsecond || s.Max != 30*time.Microsecond {
+		t.Errorf("Samples = %v, want only the 3 timed runs", s.Samples)
+	}
+	if s.Result != 9592 {
+		t.Errorf("Result = %d, want 9592", s.Result)
+	}
+}
+
+func TestRunNStopsWhenInterrupted(t *testing.T) {
+	fakeClock(t, us(10, 20, 30)...)
+	stop := make(chan struct{})
+	calls := 0
+	s := runN(10, func() int64 {
+		calls++
+		if calls == 3 {
+			close(stop) // the interrupt arrives during run 3
+		}
+		return 9592
+	}, nil, stop)
+
+	if calls != 3 || len(s.Samples) != 3 {
+		t.Errorf("fn called %d times with %d samples, want run 3 finished and no more", calls, len(s.Samples))
+	}
+	if !s.Partial || s.Result != 9592 || s.Max != 30*time.Microsecond {
+		t.Errorf("Partial, Result, Max = %v, %d, %v; want true, 9592, 30µs", s.Partial, s.Result, s.Max)
+	}
+
+	// A run that finishes every iteration is not partial, even if the
+	// interrupt arrives during the last one.
+	fakeClock(t, us(10, 20)...)
+	stop = make(chan struct{})
+	s = runN(2, func() int64 {
+		if calls++; calls == 5 {
+			close(stop)
+		}
+		return 9592
+	}, nil, stop)
+	if s.Partial || len(s.Samples) != 2 {
+		t.Errorf("Partial, samples = %v, %d; want false, 2", s.Partial, len(s.Samples))
+	}
+}
+
+func TestRunWarmInterruptedDuringWarmup(t *testing.T) {
+	stop := make(chan struct{})
+	calls := 0
+	s := runWarm(5, 10, func() int64 {
+		if calls++; calls == 2 {
+			close(stop)
+		}
+		return 9592
+	}, nil, stop)
+	if calls != 2 || !s.Partial || len(s.Samples) != 0 {
+		t.Errorf("calls, Partial, samples = %d, %v, %d; want 2, true, 0", calls, s.Partial, len(s.Samples))
+	}
+}
+
+func TestRunWarmPanicsOnWrongWarmupResult(t *testing.T) {
+	defer func() {
+		r := recover()
+		if msg, _ := r.(string); !strings.Contains(msg, "warmup runs returned 1") {
+			t.Errorf("panic = %v, want warmup mismatch", r)
+		}
+	}()
+
+	calls := 0
+	RunWarm(1, 2, func() int64 {
+		calls++
+		if calls == 1 {
+			return 1 // wrong only on the cold first run
+		}
+		return 2
+	})
+}
+
+func TestRunAutoWarmStopsWhenStable(t *testing.T) {
+	// Warmup settles at 100µs. The window of five ending at run 8
+	// (101, 100, 100, 100, 100) is the first with RSD below 2%; the last
+	// three durations are the timed runs.
+	fakeClock(t, us(500, 300, 200, 101, 100, 100, 100, 100, 40, 50, 60)...)
+
+	calls := 0
+	s := RunAutoWarm(3, func() int64 {
+		calls++
+		return 9592
+	})
+
+	if s.WarmupRuns != 8 || !s.WarmupStable {
+		t.Errorf("WarmupRuns = %d, WarmupStable = %v, want 8 and true", s.WarmupRuns, s.WarmupStable)
+	}
+	if calls != 11 {
+		t.Errorf("fn called %d times, want 8 warmup + 3 timed", calls)
+	}
+	if len(s.Samples) != 3 || s.Min != 40*time.Microsecond || s.Max != 60*time.Microsecond {
+		t.Errorf("Samples = %v, want only the 3 timed runs", s.Samples)
+	}
+}
+
+func TestRunAutoWarmHitsCap(t *testing.T) {
+	// Alternating 100µs and 200µs never gets below 2% RSD.
+	durations := make([]time.Duration, 0, AutoWarmupMaxRuns+2)
+	for i := 0; i < AutoWarmupMaxRuns; i++ {
+		durations = append(durations, time.Duration(100*(1+i%2))*time.Microsecond)
+	}
+	durations = append(durations, us(10, 20)...)
+	fakeClock(t, durations...)
+
+	s := RunAutoWarm(2, func() int64 { return 1 })
+
+	if s.WarmupRuns != AutoWarmupMaxRuns || s.WarmupStable {
+		t.Errorf("WarmupRuns = %d, WarmupStable = %v, want %d and false", s.WarmupRuns, s.WarmupStable, AutoWarmupMaxRuns)
+	}
+	if s.Mean != 15*time.Microsecond {
+		t.Errorf("Mean = %v, want 15µs from the timed runs", s.Mean)
+	}
+}
+
+func TestRunAutoWarmPanicsOnDivergentResult(t *testing.T) {
+	defer func() {
+		r := recover()
+		if msg, _ := r.(string); !strings.Contains(msg, "warmup run 2 returned RESULT 2") {
+			t.Errorf("panic = %v, want warmup divergence", r)
+		}
+	}()
+	fakeClock(t, us(100, 100, 100, 100, 100)...)
+
+	calls := 0
+	RunAutoWarm(1, func() int64 {
+		calls++
+		return int64(calls)
+	})
+}
+
+func TestTrimDropsOutliers(t *testing.T) {
+	// Eighteen samples near 100µs plus one very fast and one very slow
+	// outlier. Trimming 5% of 20 drops exactly one sample from each end.
+	samples := us(1, 100, 100, 100, 100, 100, 100, 100, 100, 100,
+		100, 100, 100, 100, 100, 100, 100, 100, 100, 5000)
+	s := summarize(samples)
+	if s.Mean != 340050*time.Nanosecond {
+		t.Fatalf("untrimmed Mean = %v, want 340.05µs", s.Mean)
+	}
+
+	got := s.Trim(5)
+	if got.Mean != 100*time.Microsecond || got.StdDev != 0 {
+		t.Errorf("Trim(5) Mean, StdDev = %v, %v; want 100µs, 0", got.Mean, got.StdDev)
+	}
+	if got.Trimmed != 2 {
+		t.Errorf("Trimmed = %d, want 2", got.Trimmed)
+	}
+	// Order statistics still describe every run.
+	if got.Min != time.Microsecond || got.Max != 5000*time.Microsecond || len(got.Samples) != 20 {
+		t.Errorf("Trim changed Min/Max/Samples: %v, %v, %d samples", got.Min, got.Max, len(got.Samples))
+	}
+}
+
+func TestTrimRoundsDown(t *testing.T) {
+	// 10% of 15 samples is 1.5; one sample goes from each end.
+	s := summarize(us(1, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 40)).Trim(10)
+	if s.Trimmed != 2 || s.Mean != 10*time.Microsecond {
+		t.Errorf("Trimmed, Mean = %d, %v; want 2, 10µs", s.Trimmed, s.Mean)
+	}
+}
+
+func TestTrimFewSamples(t *testing.T) {
+	tests := []struct {
+		samples     []time.Duration
+		pct         float64
+		wantMean    time.Duration
+		wantTrimmed int
+	}{
+		// 10% of fewer than 10 samples rounds to nothing.
+		{us(10, 20, 90), 10, 40 * time.Microsecond, 0},
+		// 45% of 3 samples would drop one per end: the median survives.
+		{us(10, 20, 90), 45, 20 * time.Microsecond, 2},
+		// 45% of 4 samples would drop one per end: the middle pair survives.
+		{us(10, 20, 30, 90), 45, 25 * time.Microsecond, 2},
+		// A single sample is never dropped.
+		{us(70), 49, 70 * time.Microsecond, 0},
+		{us(10, 20), 49, 15 * time.Microsecond, 0},
+	}
+	for _, tt := range tests {
+		s := summarize(tt.samples).Trim(tt.pct)
+		if s.Mean != tt.wantMean || s.Trimmed != tt.wantTrimmed {
+			t.Errorf("Trim(%v) of %v: Mean %v, Trimmed %d; want %v, %d",
+				tt.pct, tt.samples, s.Mean, s.Trimmed, tt.wantMean, tt.wantTrimmed)
+		}
+	}
+}
+
+func TestTrimZeroIsIdentity(t *testing.T) {
+	s := summarize(us(1, 50, 900))
+	if got := s.Trim(0); got.Mean != s.Mean || got.StdDev != s.StdDev || got.Trimmed != 0 {
+		t.Errorf("Trim(0) = %+v, want %+v", got, s)
+	}
+}
+
+func TestRSD(t *testing.T) {
+	stable := summarize(us(100, 101, 99, 100, 100, 102, 98, 100))
+	noisy := summarize(us(100, 40, 180, 90, 300, 60, 110, 120))
+
+	if rsd := stable.RSD(); rsd <= 0 || rsd > 2 {
+		t.Errorf("stable RSD = %.2f%%, want in (0, 2]", rsd)
+	}
+	if rsd := noisy.RSD(); rsd < 50 {
+		t.Errorf("noisy RSD = %.2f%%, want >= 50", rsd)
+	}
+	if err := stable.checkRSD(5); err != nil {
+		t.Errorf("stable samples rejected: %v", err)
+	}
+	err := noisy.checkRSD(5)
+	if err == nil || !strings.HasPrefix(err.Error(), "measurement too noisy (rsd=") {
+		t.Errorf("noisy checkRSD error = %v, want measurement too noisy", err)
+	}
+	if err := noisy.checkRSD(0); err != nil {
+		t.Errorf("checkRSD(0) = %v, want disabled", err)
+	}
+	// RSD uses the trimmed mean and stddev, so trimming can rescue a run
+	// with a single outlier at each end.
+	outliers := summarize(us(1, 100, 100, 100, 100, 100, 100, 100, 100, 5000))
+	if err := outliers.Trim(10).checkRSD(5); err != nil {
+		t.Errorf("trimmed outliers rejected: %v", err)
+	}
+	if zero := (Stats{}); zero.RSD() != 0 {
+		t.Errorf("RSD of zero Stats = %v, want 0", zero.RSD())
+	}
+}
+
+func TestReportStats(t *testing.T) {
+	s := summarize(us(10, 20, 30))
+	s.Result = 7
+
+	got := captureStdout(t, func() { ReportStats(5*time.Microsecond, s) })
+	want := "STARTUP_TIME_US: 5\n" +
+		"STARTUP_TIME_NS: 5000\n" +
+		"COMPUTE_TIME_US: 20\n" +
+		"COMPUTE_TIME_NS: 20000\n" +
+		"RESULT: 7\n" +
+		"ITERATIONS: 3\n" +
+		"COMPUTE_TIME_US_MIN: 10\n" +
+		"COMPUTE_TIME_US_MAX: 30\n" +
+		"COMPUTE_TIME_US_MEDIAN: 20\n" +
+		"COMPUTE_TIME_US_STDDEV: 10\n" +
+		"COMPUTE_TIME_US_CI95: [-4.8, 44.8]\n" +
+		"COMPUTE_TIME_US_P95: 29\n"
+	if got != want {
+		t.Errorf("ReportStats output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCI95(t *testing.T) {
+	// tenToHundred is 10..100µs: mean 55µs, sample stddev 30.2765µs.
+	tenToHundred := us(50, 10, 90, 30, 70, 20, 100, 40, 80, 60)
+	var many []int
+	for i := 0; i < 40; i++ {
+		many = append(many, 100+10*(i%2)) // mean 105µs, stddev 5.0637µs
+	}
+	tests := []struct {
+		name   string
+		s      Stats
+		lo, hi float64
+	}{
+		// t(0.975, 9) = 2.2622: 55 ± 2.2622·30.2765/√10.
+		{"known sample", summarize(tenToHundred), 33.341, 76.659},
+		// One degree of freedom: 15 ± 12.7062·7.0711/√2.
+		{"n=2", summarize(us(10, 20)), -48.531, 78.531},
+		// t(0.975, 2) = 4.3027: 20 ± 4.3027·10/√3.
+		{"n=3", summarize(us(10, 20, 30)), -4.842, 44.842},
+		// 39 degrees of freedom is past the table: 105 ± 1.96·5.0637/√40.
+		{"normal approximation", summarize(us(many...)), 103.431, 106.569},
+		// Trimming to 10 of 12 samples uses the t value for 9.
+		{"trimmed", summarize(append(tenToHundred, us(1, 5000)...)).Trim(10), 33.341, 76.659},
+	}
+	for _, tt := range tests {
+		lo, hi := tt.s.CI95()
+		if math.Abs(lo-tt.lo) > 0.001 || math.Abs(hi-tt.hi) > 0.001 {
+			t.Errorf("%s: CI95 = [%.3f, %.3f], want [%.3f, %.3f]", tt.name, lo, hi, tt.lo, tt.hi)
+		}
+	}
+
+	// A single sample has no spread; the interval collapses to the mean.
+	if lo, hi := summarize(us(20)).CI95(); lo != 20 || hi != 20 {
+		t.Errorf("single-sample CI95 = [%v, %v], want [20, 20]", lo, hi)
+	}
+}
+
+func TestTCriticalCrossover(t *testing.T) {
+	if got := tCritical(30); got != 2.0423 {
+		t.Errorf("tCritical(30) = %v, want 2.0423", got)
+	}
+	if got := tCritical(31); got != 1.96 {
+		t.Errorf("tCritical(31) = %v, want the normal 1.96", got)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	// Sorted, the samples are 10..100µs: rank p/100·9 falls between
+	// samples unless p is a multiple of 100/9.
+	s := summarize(us(50, 10, 90, 30, 70, 20, 100, 40, 80, 60))
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{0, 10 * time.Microsecond},
+		{50, 55 * time.Microsecond},     // rank 4.5
+		{90, 91 * time.Microsecond},     // rank 8.1
+		{99, 99100 * time.Nanosecond},   // rank 8.91
+		{99.9, 99910 * time.Nanosecond}, // rank 8.991
+		{100, 100 * time.Microsecond},
+	}
+	for _, tt := range tests {
+		if got := s.Percentile(tt.p); got != tt.want {
+			t.Errorf("Percentile(%g) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if s.Percentile(95) != s.P95 {
+		t.Errorf("Percentile(95) = %v, P95 = %v", s.Percentile(95), s.P95)
+	}
+}
+
+func TestReportStatsPercentiles(t *testing.T) {
+	s := summarize(us(50, 10, 90, 30, 70, 20, 100, 40, 80, 60))
+	s.Result, s.Percentiles = 7, []float64{50, 90, 95, 99, 99.9}
+
+	got := captureStdout(t, func() { ReportStats(0, s) })
+	want := "COMPUTE_TIME_US_P95: 95\n" +
+		"COMPUTE_TIME_US_P50: 55\n" +
+		"COMPUTE_TIME_US_P90: 91\n" +
+		"COMPUTE_TIME_US_P99: 99\n" +
+		"COMPUTE_TIME_US_P99_9: 99\n"
+	if !strings.HasSuffix(got, want) {
+		t.Errorf("ReportStats output:\n%s\nwant it to end with:\n%s", got, want)
+	}
+
+	// A single run prints only the standard lines.
+	one := summarize(us(20))
+	one.Result, one.Percentiles = 7, []float64{50, 90}
+	got = captureStdout(t, func() { ReportStats(5*time.Microsecond, one) })
+	if want := captureStdout(t, func() { Report(5*time.Microsecond, 20*time.Microsecond, 7) }); got != want {
+		t.Errorf("single-run ReportStats = %q, want %q", got, want)
+	}
+}
+
+func TestReportStatsHexResult(t *testing.T) {
+	s := summarize(us(20))
+	s.Result, s.HexResult = -6232655581607151700, true
+
+	got := captureStdout(t, func() { ReportStats(5*time.Microsecond, s) })
+	want := "STARTUP_TIME_US: 5\n" +
+		"STARTUP_TIME_NS: 5000\n" +
+		"COMPUTE_TIME_US: 20\n" +
+		"COMPUTE_TIME_NS: 20000\n" +
+		"RESULT_HEX: 0xa98128c542f337ac\n"
+	if got != want {
+		t.Errorf("ReportStats output:\n%s\nwant:\n%s", got, want)
+	}
+
+	s.Result = 255
+	if got := captureStdout(t, func() { ReportStats(0, s) }); !strings.Contains(got, "RESULT_HEX: 0x00000000000000ff\n") {
+		t.Errorf("ReportStats output:\n%s\nwant the result zero-padded to 16 digits", got)
+	}
+}
+
+func TestReportStatsWarmupRuns(t *testing.T) {
+	s := summarize(us(20))
+	s.Result, s.WarmupRuns = 7, 12
+
+	got := captureStdout(t, func() { ReportStats(5*time.Microsecond, s) })
+	if !strings.HasSuffix(got, "RESULT: 7\nWARMUP_RUNS: 12\n") {
+		t.Errorf("ReportStats output:\n%s\nwant a trailing WARMUP_RUNS: 12 line", got)
+	}
+}
+
+func TestReportStatsSingleRunMatchesReport(t *testing.T) {
+	s := summarize(us(20))
+	s.Result = 7
+
+	got := captureStdout(t, func() { ReportStats(5*time.Microsecond, s) })
+	want := captureStdout(t, func() { Report(5*time.Microsecond, 20*time.Microsecond, 7) })
+	if got != want {
+		t.Errorf("single-run ReportStats = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,34 @@
+package benchlib
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTimeout is returned, wrapped, by RunWithTimeout when fn does not
+// finish in time.
+var ErrTimeout = errors.New("timeout")
+
+// RunWithTimeout runs fn in a goroutine and returns its result, or an error
+// wrapping ErrTimeout if it has not returned after d. A non-positive d
+// means no limit.
+//
+// Go cannot stop a running goroutine, so on timeout fn keeps running in the
+// background; callers are expected to exit the process.
+func RunWithTimeout(d time.Duration, fn func() int64) (int64, error) {
+	if d <= 0 {
+		return fn(), nil
+	}
+	done := make(chan int64, 1)
+	go func() { done <- fn() }()
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case v := <-done:
+		return v, nil
+	case <-timer.C:
+		return 0, fmt.Errorf("%w: compute did not finish within %v", ErrTimeout, d)
+	}
+}
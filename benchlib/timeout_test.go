@@ -0,0 +1,41 @@
+package benchlib
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunWithTimeoutCompletes(t *testing.T) {
+	got, err := RunWithTimeout(time.Second, func() int64 { return 9592 })
+	if err != nil || got != 9592 {
+		t.Errorf("RunWithTimeout = %d, %v; want 9592, nil", got, err)
+	}
+}
+
+func TestRunWithTimeoutTrips(t *testing.T) {
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	start := time.Now()
+	_, err := RunWithTimeout(20*time.Millisecond, func() int64 {
+		<-release
+		return 1
+	})
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("err = %v, want ErrTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("RunWithTimeout returned after %v, long past the 20ms limit", elapsed)
+	}
+}
+
+func TestRunWithTimeoutZeroMeansNoLimit(t *testing.T) {
+	got, err := RunWithTimeout(0, func() int64 {
+		time.Sleep(10 * time.Millisecond)
+		return 7
+	})
+	if err != nil || got != 7 {
+		t.Errorf("RunWithTimeout(0) = %d, %v; want 7, nil", got, err)
+	}
+}
@@ -0,0 +1,100 @@
+package benchlib
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+)
+
+// atFail, if set, runs after Failf prints its message and before it exits.
+// Run sets it while the compute function runs, to finish a CPU profile
+// that would otherwise be left truncated.
+var atFail func()
+
+// failHooks holds the functions registered with OnFail, oldest first.
+var failHooks struct {
+	sync.Mutex
+	fns []*func()
+}
+
+// OnFail registers fn to run when Failf terminates the process, and
+// returns a function that unregisters it. Failf runs the registered
+// functions newest first, after completing the --cpuprofile.
+//
+// Deferred calls in the compute function do not run when it fails, nor
+// when --timeout fires, which Run reports with Failf while the compute
+// function is still going. A benchmark whose compute function creates
+// something that must not outlive the process, such as a large temporary
+// file, registers its cleanup here as well as deferring it:
+//
+//	defer benchlib.OnFail(func() { os.Remove(path) })()
+//
+// fn may run on another goroutine than the one that registered it.
+func OnFail(fn func()) (remove func()) {
+	p := &fn
+	failHooks.Lock()
+	failHooks.fns = append(failHooks.fns, p)
+	failHooks.Unlock()
+	return func() {
+		failHooks.Lock()
+		failHooks.fns = slices.DeleteFunc(failHooks.fns, func(q *func()) bool { return q == p })
+		failHooks.Unlock()
+	}
+}
+
+// Failf reports a benchmark failure and terminates the process. It prints
+// "FAILURE: " followed by the formatted message on stderr and exits with
+// status 1, so harnesses see a one-line reason instead of a stack trace.
+// Called from the compute function passed to Run, it first completes the
+// --cpuprofile, so failing runs can still be profiled. It then runs the
+// functions registered with OnFail.
+func Failf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "FAILURE: "+format+"\n", args...)
+	if atFail != nil {
+		atFail()
+	}
+	failHooks.Lock()
+	fns := slices.Clone(failHooks.fns)
+	failHooks.Unlock()
+	for _, fn := range slices.Backward(fns) {
+		(*fn)()
+	}
+	os.Exit(1)
+}
+
+// Validate checks a benchmark's RESULT against its expected value and
+// calls Failf with "expected <want> got <got>" on mismatch. It returns
+// normally when the values agree.
+func Validate(got, want int64) {
+	if got != want {
+		Failf("expected %d got %d", want, got)
+	}
+}
+
+// ValidateSeed is Validate for a benchmark whose inputs come from
+// NewRand(seed). Its expected value holds only for DefaultSeed, so for any
+// other seed it notes on stderr that the check was skipped and returns;
+// such runs rely on the benchmark's own invariants, like a sort checking
+// that its output is sorted.
+func ValidateSeed(seed, got, want int64) {
+	if seed != DefaultSeed {
+		fmt.Fprintf(os.Stderr, "NOTE: --seed=%d: RESULT %d not checked against %d\n", seed, got, want)
+		return
+	}
+	Validate(got, want)
+}
+
+// Must returns v, or fails the benchmark with "FAILURE: setup: <err>" when
+// err is non-nil. It lets main call error-returning setup in one line:
+//
+//	doc := benchlib.Must(generateDocument(r, records))
+//
+// Errors in the compute phase should go to Failf with their own context
+// instead.
+func Must[T any](v T, err error) T {
+	if err != nil {
+		Failf("setup: %v", err)
+	}
+	return v
+}
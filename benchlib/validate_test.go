@@ -0,0 +1,130 @@
+package benchlib
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// validateHelperEnv selects the Validate or ValidateSeed call made by the
+// re-executed test binary in TestValidateExit.
+const validateHelperEnv = "BENCHLIB_VALIDATE_HELPER"
+
+func TestValidateExit(t *testing.T) {
+	switch os.Getenv(validateHelperEnv) {
+	case "match":
+		Validate(9592, 9592)
+		os.Exit(0)
+	case "mismatch":
+		Validate(9591, 9592)
+		os.Exit(0) // not reached
+	case "seed-default-mismatch":
+		ValidateSeed(DefaultSeed, 9591, 9592)
+		os.Exit(0) // not reached
+	case "seed-other-mismatch":
+		ValidateSeed(7, 9591, 9592)
+		os.Exit(0)
+	}
+
+	tests := []struct {
+		mode       string
+		wantCode   int
+		wantStderr string
+	}{
+		{"match", 0, ""},
+		{"mismatch", 1, "FAILURE: expected 9592 got 9591\n"},
+		{"seed-default-mismatch", 1, "FAILURE: expected 9592 got 9591\n"},
+		// Another seed has other inputs, so the constant does not apply.
+		{"seed-other-mismatch", 0, "NOTE: --seed=7: RESULT 9591 not checked against 9592\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			cmd := exec.Command(os.Args[0], "-test.run=^TestValidateExit$")
+			cmd.Env = append(os.Environ(), validateHelperEnv+"="+tt.mode)
+			var stderr strings.Builder
+			cmd.Stderr = &stderr
+			err := cmd.Run()
+
+			code := 0
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				code = exitErr.ExitCode()
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d", code, tt.wantCode)
+			}
+			if stderr.String() != tt.wantStderr {
+				t.Errorf("stderr = %q, want %q", stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
+
+func TestMustReturnsValue(t *testing.T) {
+	if got := Must(42, nil); got != 42 {
+		t.Errorf("Must(42, nil) = %d, want 42", got)
+	}
+	if got := Must([]byte("doc"), nil); string(got) != "doc" {
+		t.Errorf("Must(doc, nil) = %q, want doc", got)
+	}
+}
+
+// mustHelperEnv makes the re-executed test binary in TestMustExit call Must
+// with an error.
+const mustHelperEnv = "BENCHLIB_MUST_HELPER"
+
+func TestMustExit(t *testing.T) {
+	if os.Getenv(mustHelperEnv) != "" {
+		Must(0, errors.New("generating input: out of range"))
+		os.Exit(0) // not reached
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestMustExit$")
+	cmd.Env = append(os.Environ(), mustHelperEnv+"=1")
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Fatalf("Must with an error: err = %v, want exit status 1", err)
+	}
+	if want := "FAILURE: setup: generating input: out of range\n"; stderr.String() != want {
+		t.Errorf("stderr = %q, want %q", stderr.String(), want)
+	}
+}
+
+// onFailHelperEnv makes the re-executed test binary in TestOnFail register
+// cleanups and call Failf.
+const onFailHelperEnv = "BENCHLIB_ON_FAIL_HELPER"
+
+func TestOnFail(t *testing.T) {
+	if os.Getenv(onFailHelperEnv) != "" {
+		say := func(s string) func() { return func() { os.Stderr.WriteString(s + "\n") } }
+		OnFail(say("first"))
+		remove := OnFail(say("removed"))
+		OnFail(say("last"))
+		remove()
+		remove() // a second call is a no-op
+		Failf("timeout")
+		os.Exit(0) // not reached
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestOnFail$")
+	cmd.Env = append(os.Environ(), onFailHelperEnv+"=1")
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Fatalf("Failf: err = %v, want exit status 1", err)
+	}
+	if want := "FAILURE: timeout\nlast\nfirst\n"; stderr.String() != want {
+		t.Errorf("stderr = %q, want %q", stderr.String(), want)
+	}
+}
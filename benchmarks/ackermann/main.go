@@ -0,0 +1,81 @@
+/*
+ * Ackermann Function
+ *
+ * Compute A(3, 10) with the textbook doubly recursive definition:
+ *
+ *	A(0, n) = n + 1
+ *	A(m, 0) = A(m−1, 1)
+ *	A(m, n) = A(m−1, A(m, n−1))
+ *
+ * For m = 3 the value has the closed form 2^(n+3) − 3, which is what the
+ * result is validated against. A(3, 10) makes 44,698,325 calls and nests
+ * at most 8,191 frames deep (A(3, n) + 2 in general), far past what
+ * fibonacci reaches. Go grows goroutine stacks on demand, so the depth
+ * needs no special handling. --n selects another A(3, n); each step
+ * roughly quadruples the call count, so it is capped at 12 (about 5
+ * seconds, 32,767 frames deep).
+ * Expected result: 8189
+ *
+ * This benchmark tests:
+ * - Function call overhead without memoization
+ * - Deep recursion and stack growth
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	m        = 3
+	defaultN = 10
+	maxN     = 12
+)
+
+func ackermann(m, n int) int {
+	if m == 0 {
+		return n + 1
+	}
+	if n == 0 {
+		return ackermann(m-1, 1)
+	}
+	return ackermann(m-1, ackermann(m, n-1))
+}
+
+// closedForm3 returns A(3, n) = 2^(n+3) − 3.
+func closedForm3(n int) int {
+	return 1<<(n+3) - 3
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "ackermann", Category: benchlib.CategoryRecursion, Expected: int64(closedForm3(defaultN))})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	n := flag.Int("n", defaultN, fmt.Sprintf("second argument of A(3, n), 0 to %d", maxN))
+	flag.Parse()
+	if *n < 0 || *n > maxN {
+		fmt.Fprintf(os.Stderr, "ackermann: --n must be between 0 and %d, got %d\n", maxN, *n)
+		os.Exit(2)
+	}
+
+	t0 := time.Now()
+	startup := time.Since(t0)
+
+	// Compute benchmark. The first call also pays for growing the stack;
+	// use --warmup to leave that out of the timings.
+	stats := benchlib.Run("ackermann", opts, startup, func() int64 {
+		return int64(ackermann(m, *n))
+	})
+
+	// Validate result
+	benchlib.Validate(stats.Result, int64(closedForm3(*n)))
+}
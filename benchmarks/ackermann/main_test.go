@@ -0,0 +1,84 @@
+package main
+
+import "testing"
+
+// table holds A(m, n) for m ≤ 3 and n ≤ 4, from the standard table of
+// values.
+var table = [4][5]int{
+	{1, 2, 3, 4, 5},
+	{2, 3, 4, 5, 6},
+	{3, 5, 7, 9, 11},
+	{5, 13, 29, 61, 125},
+}
+
+func TestAckermannTable(t *testing.T) {
+	for m, row := range table {
+		for n, want := range row {
+			if got := ackermann(m, n); got != want {
+				t.Errorf("A(%d, %d) = %d, want %d", m, n, got, want)
+			}
+		}
+	}
+}
+
+func TestAckermann4(t *testing.T) {
+	// A(4, 0) = A(3, 1) = 13 and A(4, 1) = A(3, 13) = 65533; the latter is
+	// far too slow, so only the first is checked.
+	if got := ackermann(4, 0); got != 13 {
+		t.Errorf("A(4, 0) = %d, want 13", got)
+	}
+}
+
+func TestClosedForm3(t *testing.T) {
+	limit := defaultN
+	if testing.Short() {
+		limit = 8
+	}
+	for n := 0; n <= limit; n++ {
+		if got, want := ackermann(3, n), closedForm3(n); got != want {
+			t.Errorf("A(3, %d) = %d, closed form %d", n, got, want)
+		}
+	}
+	if got := closedForm3(defaultN); got != 8189 {
+		t.Errorf("closedForm3(%d) = %d, want 8189", defaultN, got)
+	}
+}
+
+// trace evaluates A(m, n) like ackermann while counting calls and the
+// deepest nesting.
+func trace(m, n int) (value, calls, maxDepth int) {
+	depth := 0
+	var a func(m, n int) int
+	a = func(m, n int) int {
+		calls++
+		depth++
+		maxDepth = max(maxDepth, depth)
+		defer func() { depth-- }()
+		if m == 0 {
+			return n + 1
+		}
+		if n == 0 {
+			return a(m-1, 1)
+		}
+		return a(m-1, a(m, n-1))
+	}
+	value = a(m, n)
+	return value, calls, maxDepth
+}
+
+func TestDocumentedDepth(t *testing.T) {
+	// The header's depth bound, A(3, n) + 2, on small n.
+	for n := 0; n <= 8; n++ {
+		v, _, depth := trace(3, n)
+		if depth != v+2 {
+			t.Errorf("A(3, %d): depth %d, want %d", n, depth, v+2)
+		}
+	}
+	if testing.Short() {
+		t.Skip("tracing A(3, 10) in short mode")
+	}
+	v, calls, depth := trace(m, defaultN)
+	if v != 8189 || calls != 44698325 || depth != 8191 {
+		t.Errorf("A(3, 10): value %d, %d calls, depth %d; want 8189, 44698325, 8191", v, calls, depth)
+	}
+}
@@ -0,0 +1,351 @@
+/*
+ * AES-GCM Encryption Throughput
+ *
+ * Encrypt a 64 MiB deterministic buffer (benchlib.RandomBytes seeded with
+ * benchlib.DefaultSeed) with AES-128-GCM under a fixed key and nonce and no
+ * additional data. RESULT is the first 8 bytes of the 16-byte
+ * authentication tag as a big-endian int64; the tag is GHASH over the
+ * whole ciphertext, so it changes if any ciphertext byte does.
+ * Expected result: 0x128617080d5918c6 (1334779662913050822)
+ *
+ * The implementation is portable scalar code following FIPS 197 and NIST
+ * SP 800-38D: AES with 32-bit T-tables, counter mode, and GHASH with
+ * Shoup's 4-bit multiplication tables. crypto/aes and crypto/cipher, whose
+ * AES-NI and carry-less multiply assembly no portable language can match,
+ * are used only to check the output: by the tests, and after the timed
+ * runs when another --seed leaves no expected value. The expected value
+ * is their tag for the same input.
+ *
+ * The key and nonce are constants so every run and every language produce
+ * the same ciphertext. That is only acceptable in a benchmark: reusing a
+ * nonce under one key breaks GCM's confidentiality and authenticity.
+ *
+ * This benchmark tests:
+ * - Table lookups indexed by data bytes (AES rounds)
+ * - 64-bit shifts and xors in GF(2^128) multiplication
+ * - Sequential streaming over a buffer larger than L2
+ */
+
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"flag"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	bufferSize = 64 << 20
+
+	// expectedTag64 is the first half of the tag crypto/cipher's GCM
+	// computes for the same key, nonce and plaintext.
+	expectedTag64 uint64 = 0x128617080d5918c6
+)
+
+// Fixed, insecure-by-design parameters; see the file comment.
+var (
+	key   = [16]byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f}
+	nonce = [12]byte{0xca, 0xfe, 0xba, 0xbe, 0xfa, 0xce, 0xdb, 0xad, 0xde, 0xca, 0xf8, 0x88}
+)
+
+const (
+	blockSize = 16
+	tagSize   = 16
+	rounds    = 10 // AES-128
+)
+
+// sbox is the AES S-box and te the encryption T-tables: te[i][x] is column
+// i of MixColumns applied to a column holding only sbox[x], as a big-endian
+// word.
+var sbox, te = buildTables()
+
+// gmul multiplies a and b in GF(2^8) modulo x^8 + x^4 + x^3 + x + 1.
+func gmul(a, b byte) byte {
+	var p byte
+	for b != 0 {
+		if b&1 != 0 {
+			p ^= a
+		}
+		carry := a & 0x80
+		a <<= 1
+		if carry != 0 {
+			a ^= 0x1b
+		}
+		b >>= 1
+	}
+	return p
+}
+
+// buildTables derives the S-box, multiplicative inverse followed by the
+// affine map of FIPS 197 §5.1.1, and the T-tables from it.
+func buildTables() (s [256]byte, t [4][256]uint32) {
+	for x := 0; x < 256; x++ {
+		// inv is x^254, which is x^-1 for x != 0 and 0 for x == 0.
+		inv, p := byte(1), byte(x)
+		for e := 254; e > 0; e >>= 1 {
+			if e&1 != 0 {
+				inv = gmul(inv, p)
+			}
+			p = gmul(p, p)
+		}
+		b := inv
+		for i := 1; i <= 4; i++ {
+			b ^= inv<<i | inv>>(8-i)
+		}
+		s[x] = b ^ 0x63
+	}
+	for x := 0; x < 256; x++ {
+		v := s[x]
+		w := uint32(gmul(v, 2))<<24 | uint32(v)<<16 | uint32(v)<<8 | uint32(gmul(v, 3))
+		for i := range t {
+			t[i][x] = w>>(8*i) | w<<(32-8*i)
+		}
+	}
+	return s, t
+}
+
+// expandKey returns the 4·(rounds+1) round-key words for a 128-bit key.
+func expandKey(k [16]byte) [4 * (rounds + 1)]uint32 {
+	var w [4 * (rounds + 1)]uint32
+	for i := 0; i < 4; i++ {
+		w[i] = binary.BigEndian.Uint32(k[4*i:])
+	}
+	rcon := uint32(1)
+	for i := 4; i < len(w); i++ {
+		t := w[i-1]
+		if i%4 == 0 {
+			t = t<<8 | t>>24
+			t = uint32(sbox[t>>24])<<24 | uint32(sbox[t>>16&0xff])<<16 | uint32(sbox[t>>8&0xff])<<8 | uint32(sbox[t&0xff])
+			t ^= rcon << 24
+			rcon = uint32(gmul(byte(rcon), 2))
+		}
+		w[i] = w[i-4] ^ t
+	}
+	return w
+}
+
+// encryptBlock encrypts one block from src into dst with the round keys xk.
+func encryptBlock(xk *[4 * (rounds + 1)]uint32, dst, src []byte) {
+	s0 := binary.BigEndian.Uint32(src[0:]) ^ xk[0]
+	s1 := binary.BigEndian.Uint32(src[4:]) ^ xk[1]
+	s2 := binary.BigEndian.Uint32(src[8:]) ^ xk[2]
+	s3 := binary.BigEndian.Uint32(src[12:]) ^ xk[3]
+
+	k := 4
+	for r := 1; r < rounds; r++ {
+		t0 := te[0][s0>>24] ^ te[1][s1>>16&0xff] ^ te[2][s2>>8&0xff] ^ te[3][s3&0xff] ^ xk[k]
+		t1 := te[0][s1>>24] ^ te[1][s2>>16&0xff] ^ te[2][s3>>8&0xff] ^ te[3][s0&0xff] ^ xk[k+1]
+		t2 := te[0][s2>>24] ^ te[1][s3>>16&0xff] ^ te[2][s0>>8&0xff] ^ te[3][s1&0xff] ^ xk[k+2]
+		t3 := te[0][s3>>24] ^ te[1][s0>>16&0xff] ^ te[2][s1>>8&0xff] ^ te[3][s2&0xff] ^ xk[k+3]
+		s0, s1, s2, s3 = t0, t1, t2, t3
+		k += 4
+	}
+
+	// The last round has no MixColumns.
+	sub := func(a, b, c, d uint32) uint32 {
+		return uint32(sbox[a>>24])<<24 | uint32(sbox[b>>16&0xff])<<16 | uint32(sbox[c>>8&0xff])<<8 | uint32(sbox[d&0xff])
+	}
+	binary.BigEndian.PutUint32(dst[0:], sub(s0, s1, s2, s3)^xk[k])
+	binary.BigEndian.PutUint32(dst[4:], sub(s1, s2, s3, s0)^xk[k+1])
+	binary.BigEndian.PutUint32(dst[8:], sub(s2, s3, s0, s1)^xk[k+2])
+	binary.BigEndian.PutUint32(dst[12:], sub(s3, s0, s1, s2)^xk[k+3])
+}
+
+// fieldElement is an element of GF(2^128) in GCM's bit order: the
+// coefficient of x^0 is the most significant bit of hi, that of x^127 the
+// least significant bit of lo.
+type fieldElement struct {
+	hi, lo uint64
+}
+
+func loadElement(b []byte) fieldElement {
+	return fieldElement{binary.BigEndian.Uint64(b), binary.BigEndian.Uint64(b[8:])}
+}
+
+// mulX returns e·x, reduced modulo x^128 + x^7 + x^2 + x + 1.
+func (e fieldElement) mulX() fieldElement {
+	carry := e.lo & 1
+	e.lo = e.lo>>1 | e.hi<<63
+	e.hi >>= 1
+	if carry != 0 {
+		e.hi ^= 0xe1 << 56
+	}
+	return e
+}
+
+// ghash is the GHASH function keyed by H, using Shoup's method: the
+// product of H with each 4-bit polynomial is precomputed, so multiplying
+// by H takes 32 table lookups and shifts by x^4 per block.
+type ghash struct {
+	// table[v] is H times the nibble v read as the coefficients of x^0
+	// (its top bit) to x^3.
+	table [16]fieldElement
+	// reduce[r] is the reduction of x^4 times the low nibble r of lo,
+	// that is, of the coefficients x^124..x^127 shifted past x^127.
+	reduce [16]uint64
+	y      fieldElement
+}
+
+func newGHASH(h fieldElement) *ghash {
+	g := new(ghash)
+	for bit, p := 8, h; bit > 0; bit, p = bit>>1, p.mulX() {
+		g.table[bit] = p
+	}
+	for v := 1; v < 16; v++ {
+		if v&(v-1) != 0 { // not a power of two
+			hb := 8
+			for v&hb == 0 {
+				hb >>= 1
+			}
+			g.table[v] = fieldElement{g.table[hb].hi ^ g.table[v^hb].hi, g.table[hb].lo ^ g.table[v^hb].lo}
+		}
+	}
+	for r := 0; r < 16; r++ {
+		e := fieldElement{lo: uint64(r)}
+		for i := 0; i < 4; i++ {
+			e = e.mulX()
+		}
+		g.reduce[r] = e.hi // r·x^4 fits in the top bits of hi
+	}
+	return g
+}
+
+// mulH sets y to y·H.
+func (g *ghash) mulH() {
+	var z fieldElement
+	x := g.y
+	// Horner's rule from the last nibble, x^124..x^127, to the first.
+	for j := 31; j >= 0; j-- {
+		var n uint64
+		if j < 16 {
+			n = x.hi >> (60 - 4*uint(j)) & 0xf
+		} else {
+			n = x.lo >> (60 - 4*uint(j-16)) & 0xf
+		}
+		r := z.lo & 0xf
+		z.lo = z.lo>>4 | z.hi<<60
+		z.hi = z.hi>>4 ^ g.reduce[r]
+		z.hi ^= g.table[n].hi
+		z.lo ^= g.table[n].lo
+	}
+	g.y = z
+}
+
+// update absorbs data, zero-padded to a whole number of blocks.
+func (g *ghash) update(data []byte) {
+	for len(data) > 0 {
+		var block [blockSize]byte
+		n := copy(block[:], data)
+		e := loadElement(block[:])
+		g.y.hi ^= e.hi
+		g.y.lo ^= e.lo
+		g.mulH()
+		data = data[n:]
+	}
+}
+
+// seal encrypts plaintext with AES-128-GCM under k and a 96-bit nonce,
+// with no additional data, and returns the ciphertext with the tag
+// appended. dst must have room for len(plaintext)+tagSize bytes.
+func seal(dst []byte, k [16]byte, nonce [12]byte, plaintext []byte) []byte {
+	xk := expandKey(k)
+	var zero, hBlock [blockSize]byte
+	encryptBlock(&xk, hBlock[:], zero[:])
+	g := newGHASH(loadElement(hBlock[:]))
+
+	// J0 is the nonce followed by the 32-bit counter 1; the plaintext is
+	// encrypted from counter 2.
+	var counter, keystream [blockSize]byte
+	copy(counter[:], nonce[:])
+	binary.BigEndian.PutUint32(counter[12:], 1)
+	var tagMask [blockSize]byte
+	encryptBlock(&xk, tagMask[:], counter[:])
+
+	out := dst[:len(plaintext)+tagSize]
+	for i := 0; i < len(plaintext); i += blockSize {
+		ctr := binary.BigEndian.Uint32(counter[12:])
+		binary.BigEndian.PutUint32(counter[12:], ctr+1)
+		encryptBlock(&xk, keystream[:], counter[:])
+		end := min(i+blockSize, len(plaintext))
+		for j := i; j < end; j++ {
+			out[j] = plaintext[j] ^ keystream[j-i]
+		}
+	}
+	ciphertext := out[:len(plaintext)]
+	g.update(ciphertext)
+
+	// The lengths block: 0 bits of additional data, then the ciphertext
+	// length in bits.
+	var lengths [blockSize]byte
+	binary.BigEndian.PutUint64(lengths[8:], uint64(len(plaintext))*8)
+	g.update(lengths[:])
+
+	var tag [blockSize]byte
+	binary.BigEndian.PutUint64(tag[:], g.y.hi)
+	binary.BigEndian.PutUint64(tag[8:], g.y.lo)
+	for i := range tag {
+		out[len(plaintext)+i] = tag[i] ^ tagMask[i]
+	}
+	return out
+}
+
+// tag64 returns the first 8 bytes of sealed output's tag as an int64.
+func tag64(sealed []byte) int64 {
+	return int64(binary.BigEndian.Uint64(sealed[len(sealed)-tagSize:]))
+}
+
+// stdlibSeal is the crypto/cipher reference for seal.
+func stdlibSeal(k [16]byte, nonce [12]byte, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(k[:])
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nil, nonce[:], plaintext, nil), nil
+}
+
+func init() {
+	want := expectedTag64
+	benchlib.Register(benchlib.Info{Name: "aesgcm", Category: benchlib.CategoryNumeric, Expected: int64(want)})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	t0 := time.Now()
+
+	// Startup phase: build the plaintext and the output buffer, so compute
+	// times only encryption
+	plaintext := benchlib.RandomBytes(benchlib.NewRand(opts.Seed), bufferSize)
+	out := make([]byte, bufferSize+tagSize)
+
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("aesgcm", opts, startup, func() int64 {
+		return tag64(seal(out, key, nonce, plaintext))
+	})
+
+	// Validate result
+	if opts.Seed != benchlib.DefaultSeed {
+		sealed, err := stdlibSeal(key, nonce, plaintext)
+		if err != nil {
+			benchlib.Failf("crypto/cipher: %v", err)
+		}
+		if want := tag64(sealed); stats.Result != want {
+			benchlib.Failf("crypto/cipher tag starts %#016x, got %#016x", uint64(want), uint64(stats.Result))
+		}
+	}
+	// RESULT is signed; compare against the constant's bit pattern.
+	want := expectedTag64
+	benchlib.ValidateSeed(opts.Seed, stats.Result, int64(want))
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+func TestSBox(t *testing.T) {
+	// FIPS 197 figure 7.
+	for x, want := range map[byte]byte{0x00: 0x63, 0x01: 0x7c, 0x53: 0xed, 0xff: 0x16} {
+		if sbox[x] != want {
+			t.Errorf("sbox[%#02x] = %#02x, want %#02x", x, sbox[x], want)
+		}
+	}
+}
+
+func TestEncryptBlockMatchesStdlib(t *testing.T) {
+	r := benchlib.NewRand(1)
+	for i := 0; i < 100; i++ {
+		var k [16]byte
+		copy(k[:], benchlib.RandomBytes(r, 16))
+		src := benchlib.RandomBytes(r, blockSize)
+		block, err := aes.NewCipher(k[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := make([]byte, blockSize)
+		block.Encrypt(want, src)
+
+		xk := expandKey(k)
+		got := make([]byte, blockSize)
+		encryptBlock(&xk, got, src)
+		if !bytes.Equal(got, want) {
+			t.Fatalf("key %x block %x: encryptBlock = %x, want %x", k, src, got, want)
+		}
+	}
+}
+
+func TestSealMatchesStdlib(t *testing.T) {
+	r := benchlib.NewRand(2)
+	for _, n := range []int{0, 1, 15, 16, 17, 31, 32, 100, 1000, 4096 + 7} {
+		var k [16]byte
+		var iv [12]byte
+		copy(k[:], benchlib.RandomBytes(r, 16))
+		copy(iv[:], benchlib.RandomBytes(r, 12))
+		plaintext := benchlib.RandomBytes(r, n)
+
+		got := seal(make([]byte, n+tagSize), k, iv, plaintext)
+		want, err := stdlibSeal(k, iv, plaintext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%d bytes: seal = %x, want %x", n, got, want)
+		}
+	}
+}
+
+func TestExpectedTagMatchesStdlib(t *testing.T) {
+	plaintext := benchlib.RandomBytes(benchlib.NewRand(benchlib.DefaultSeed), bufferSize)
+	sealed, err := stdlibSeal(key, nonce, plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := binary.BigEndian.Uint64(sealed[bufferSize:]); got != expectedTag64 {
+		t.Errorf("crypto/cipher tag starts %#016x, want expectedTag64 = %#016x", got, expectedTag64)
+	}
+}
+
+func TestExpectedTag(t *testing.T) {
+	if testing.Short() {
+		t.Skip("full-size run")
+	}
+	plaintext := benchlib.RandomBytes(benchlib.NewRand(benchlib.DefaultSeed), bufferSize)
+	want := expectedTag64
+	if got := tag64(seal(make([]byte, bufferSize+tagSize), key, nonce, plaintext)); got != int64(want) {
+		t.Errorf("tag64 = %#016x, want %#016x", uint64(got), want)
+	}
+}
@@ -0,0 +1,207 @@
+/*
+ * Base64 Round Trip
+ *
+ * Encode a 64 MiB deterministic buffer (benchlib.RandomBytes seeded with
+ * benchlib.DefaultSeed) as padded standard base64 (RFC 4648 §4) and decode
+ * it back, using hand-written lookup tables rather than encoding/base64 so
+ * every language port does the same work. The decoded bytes must equal the
+ * input; RESULT is their byte sum plus the encoded length, 89,478,488
+ * characters.
+ * Expected result: 8645765948
+ *
+ * This benchmark tests:
+ * - Byte shuffling: splitting 3 bytes into 4 sextets and back
+ * - Table lookups in both directions
+ * - Streaming writes into large preallocated buffers
+ */
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	bufferSize = 64 << 20
+
+	// expected is the byte sum of the buffer, 8556287460, plus its encoded
+	// length.
+	expected = 8645765948
+
+	alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+	pad      = '='
+
+	// invalid marks bytes outside the alphabet in the decode table. Valid
+	// values fit in six bits, so any of the top two bits set means invalid.
+	invalid = 0xff
+)
+
+var errCorrupt = errors.New("base64: illegal input")
+
+// decodeTable maps an alphabet byte to its 6-bit value and every other byte
+// to invalid.
+var decodeTable = func() *[256]byte {
+	var t [256]byte
+	for i := range t {
+		t[i] = invalid
+	}
+	for i := 0; i < len(alphabet); i++ {
+		t[alphabet[i]] = byte(i)
+	}
+	return &t
+}()
+
+// encodedLen returns the padded encoded length of n bytes.
+func encodedLen(n int) int {
+	return (n + 2) / 3 * 4
+}
+
+// encode writes the padded base64 encoding of src into dst, which must hold
+// encodedLen(len(src)) bytes, and returns the number of bytes written.
+func encode(dst, src []byte) int {
+	di, si := 0, 0
+	for n := len(src) / 3 * 3; si < n; si += 3 {
+		v := uint(src[si])<<16 | uint(src[si+1])<<8 | uint(src[si+2])
+		dst[di] = alphabet[v>>18&0x3f]
+		dst[di+1] = alphabet[v>>12&0x3f]
+		dst[di+2] = alphabet[v>>6&0x3f]
+		dst[di+3] = alphabet[v&0x3f]
+		di += 4
+	}
+	switch len(src) - si {
+	case 1:
+		v := uint(src[si]) << 16
+		dst[di] = alphabet[v>>18&0x3f]
+		dst[di+1] = alphabet[v>>12&0x3f]
+		dst[di+2], dst[di+3] = pad, pad
+		di += 4
+	case 2:
+		v := uint(src[si])<<16 | uint(src[si+1])<<8
+		dst[di] = alphabet[v>>18&0x3f]
+		dst[di+1] = alphabet[v>>12&0x3f]
+		dst[di+2] = alphabet[v>>6&0x3f]
+		dst[di+3] = pad
+		di += 4
+	}
+	return di
+}
+
+// decode writes the bytes encoded by the padded base64 text src into dst,
+// which must hold len(src)/4*3 bytes, and returns the number written. src
+// must be a whole number of four-character groups with padding only at the
+// end; anything else, including nonzero bits in a padded final group,
+// returns errCorrupt.
+func decode(dst, src []byte) (int, error) {
+	if len(src)%4 != 0 {
+		return 0, errCorrupt
+	}
+	if len(src) == 0 {
+		return 0, nil
+	}
+	di := 0
+	last := len(src) - 4
+	for si := 0; si < last; si += 4 {
+		a, b, c, d := decodeTable[src[si]], decodeTable[src[si+1]], decodeTable[src[si+2]], decodeTable[src[si+3]]
+		if (a|b|c|d)&0xc0 != 0 {
+			return 0, errCorrupt
+		}
+		v := uint(a)<<18 | uint(b)<<12 | uint(c)<<6 | uint(d)
+		dst[di], dst[di+1], dst[di+2] = byte(v>>16), byte(v>>8), byte(v)
+		di += 3
+	}
+
+	g := src[last:]
+	a, b := decodeTable[g[0]], decodeTable[g[1]]
+	if (a|b)&0xc0 != 0 {
+		return 0, errCorrupt
+	}
+	v := uint(a)<<18 | uint(b)<<12
+	switch {
+	case g[2] == pad && g[3] == pad:
+		if v&0xffff != 0 {
+			return 0, errCorrupt
+		}
+		dst[di] = byte(v >> 16)
+		return di + 1, nil
+	case g[3] == pad:
+		c := decodeTable[g[2]]
+		if c&0xc0 != 0 {
+			return 0, errCorrupt
+		}
+		v |= uint(c) << 6
+		if v&0xff != 0 {
+			return 0, errCorrupt
+		}
+		dst[di], dst[di+1] = byte(v>>16), byte(v>>8)
+		return di + 2, nil
+	}
+	c, d := decodeTable[g[2]], decodeTable[g[3]]
+	if (c|d)&0xc0 != 0 {
+		return 0, errCorrupt
+	}
+	v |= uint(c)<<6 | uint(d)
+	dst[di], dst[di+1], dst[di+2] = byte(v>>16), byte(v>>8), byte(v)
+	return di + 3, nil
+}
+
+// byteSum returns the sum of the bytes of p.
+func byteSum(p []byte) int64 {
+	var sum int64
+	for _, b := range p {
+		sum += int64(b)
+	}
+	return sum
+}
+
+// roundTrip encodes src into enc, decodes that into dec, and returns the
+// byte sum of the decoded bytes plus the encoded length. It reports an
+// error if the decoded bytes differ from src.
+func roundTrip(enc, dec, src []byte) (int64, error) {
+	n := encode(enc, src)
+	m, err := decode(dec, enc[:n])
+	if err != nil {
+		return 0, err
+	}
+	if !bytes.Equal(dec[:m], src) {
+		return 0, fmt.Errorf("decoded %d bytes differ from the %d-byte input", m, len(src))
+	}
+	return byteSum(dec[:m]) + int64(n), nil
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "base64", Category: benchlib.CategoryText, Expected: expected})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	t0 := time.Now()
+
+	// Startup phase: build the input and allocate both output buffers so
+	// compute only times encoding and decoding
+	src := benchlib.RandomBytes(benchlib.NewRand(opts.Seed), bufferSize)
+	enc := make([]byte, encodedLen(len(src)))
+	dec := make([]byte, len(enc)/4*3)
+
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("base64", opts, startup, func() int64 {
+		sum, err := roundTrip(enc, dec, src)
+		if err != nil {
+			benchlib.Failf("%v", err)
+		}
+		return sum
+	})
+
+	// Validate result
+	benchlib.ValidateSeed(opts.Seed, stats.Result, expected)
+}
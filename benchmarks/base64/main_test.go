@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+func TestEncodeMatchesStdlib(t *testing.T) {
+	data := benchlib.RandomBytes(benchlib.NewRand(1), 100)
+	// Every length mod 3, so all three padding cases are covered.
+	for n := 0; n <= len(data); n++ {
+		src := data[:n]
+		dst := make([]byte, encodedLen(n))
+		got := dst[:encode(dst, src)]
+		if want := base64.StdEncoding.EncodeToString(src); string(got) != want {
+			t.Fatalf("encode(%d bytes) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestDecodeRoundTrip(t *testing.T) {
+	data := benchlib.RandomBytes(benchlib.NewRand(2), 100)
+	for n := 0; n <= len(data); n++ {
+		src := data[:n]
+		enc := []byte(base64.StdEncoding.EncodeToString(src))
+		dec := make([]byte, len(enc)/4*3)
+		m, err := decode(dec, enc)
+		if err != nil {
+			t.Fatalf("decode(%q): %v", enc, err)
+		}
+		if !bytes.Equal(dec[:m], src) {
+			t.Fatalf("decode(%q) = %x, want %x", enc, dec[:m], src)
+		}
+	}
+}
+
+// decode is as strict as base64.StdEncoding.Strict().
+func TestDecodeRejectsCorruptInput(t *testing.T) {
+	for _, s := range []string{
+		"QUJD=",    // not a multiple of four
+		"QU*D",     // byte outside the alphabet
+		"QQ==QUJD", // padding before the end
+		"QR==",     // nonzero bits under the padding
+		"QUI=QUJD",
+		"=AAA",
+	} {
+		dec := make([]byte, len(s)/4*3+3)
+		if _, err := decode(dec, []byte(s)); err == nil {
+			t.Errorf("decode(%q) succeeded, want an error", s)
+		}
+		if _, err := base64.StdEncoding.Strict().DecodeString(s); err == nil {
+			t.Errorf("encoding/base64 accepts %q; the test case is wrong", s)
+		}
+	}
+}
+
+func TestRoundTripResult(t *testing.T) {
+	src := []byte("Man")
+	enc := make([]byte, encodedLen(len(src)))
+	dec := make([]byte, len(enc)/4*3)
+	// "Man" is "TWFu": byte sum 77+97+110 = 284, plus 4 characters.
+	if got, err := roundTrip(enc, dec, src); err != nil || got != 288 {
+		t.Errorf("roundTrip(%q) = %d, %v; want 288", src, got, err)
+	}
+}
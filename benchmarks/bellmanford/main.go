@@ -0,0 +1,141 @@
+/*
+ * Bellman-Ford Shortest Paths
+ *
+ * Compute single-source shortest paths from node 0 of a deterministic
+ * directed graph of 10,000 nodes with 4 outgoing edges each, some of them
+ * negative, with Bellman-Ford: |V|−1 passes that relax every edge, then
+ * one more pass that fails if any edge can still be relaxed, which means
+ * a negative cycle is reachable. All |V|−1 passes always run, so the work
+ * does not depend on how quickly the distances settle.
+ *
+ * The graph comes from benchlib.NewRand(benchlib.DefaultSeed): first a
+ * potential p(v) in [0, 1000) for every node, then for each node u in turn
+ * and each of its edges a target v and a base weight b in [1, 1000]. The
+ * edge weight is b + p(u) − p(v), which can be negative, but around any
+ * cycle the potentials cancel, so every cycle weighs its positive base sum
+ * and no negative cycle exists; 6,566 of the 40,000 edges are negative.
+ * RESULT is the sum of the finite distances (9,793 nodes are reachable).
+ * Expected result: 21957816
+ *
+ * This benchmark tests:
+ * - Sequential streaming over an edge list, |V| times
+ * - Data-dependent compare-and-update of a distance array
+ * - Signed 64-bit integer arithmetic
+ */
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	nodes        = 10000
+	degree       = 4
+	maxWeight    = 1000
+	maxPotential = 1000
+
+	expectedResult = 21957816
+)
+
+// unreachable is the distance of a node with no path from the source.
+const unreachable = math.MaxInt64
+
+// errNegativeCycle reports a negative-weight cycle reachable from the
+// source, which leaves shortest paths undefined.
+var errNegativeCycle = errors.New("negative cycle reachable from the source")
+
+// edge is a directed, weighted edge.
+type edge struct {
+	from, to int32
+	weight   int64
+}
+
+// randomEdges returns degree edges out of each of n nodes, reweighted by
+// random node potentials as described in the header. Only r.Uint64 is
+// used, so the graph is reproducible from the SplitMix64 stream alone.
+func randomEdges(r *rand.Rand, n, degree, maxWeight, maxPotential int) []edge {
+	potential := make([]int64, n)
+	for v := range potential {
+		potential[v] = int64(r.Uint64() % uint64(maxPotential))
+	}
+	edges := make([]edge, 0, n*degree)
+	for u := 0; u < n; u++ {
+		for j := 0; j < degree; j++ {
+			v := int(r.Uint64() % uint64(n))
+			b := 1 + int64(r.Uint64()%uint64(maxWeight))
+			edges = append(edges, edge{from: int32(u), to: int32(v), weight: b + potential[u] - potential[v]})
+		}
+	}
+	return edges
+}
+
+// shortestPaths returns the distance from src to each of the n nodes,
+// unreachable where there is no path, or errNegativeCycle.
+func shortestPaths(n int, edges []edge, src int) ([]int64, error) {
+	dist := make([]int64, n)
+	for i := range dist {
+		dist[i] = unreachable
+	}
+	dist[src] = 0
+
+	for pass := 1; pass < n; pass++ {
+		for _, e := range edges {
+			if du := dist[e.from]; du != unreachable && du+e.weight < dist[e.to] {
+				dist[e.to] = du + e.weight
+			}
+		}
+	}
+	for _, e := range edges {
+		if du := dist[e.from]; du != unreachable && du+e.weight < dist[e.to] {
+			return nil, errNegativeCycle
+		}
+	}
+	return dist, nil
+}
+
+// distanceSum returns the sum of the finite distances.
+func distanceSum(dist []int64) int64 {
+	var sum int64
+	for _, d := range dist {
+		if d != unreachable {
+			sum += d
+		}
+	}
+	return sum
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "bellmanford", Category: benchlib.CategoryAlgorithm, Expected: expectedResult})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	t0 := time.Now()
+
+	// Startup phase: generate the graph
+	edges := randomEdges(benchlib.NewRand(opts.Seed), nodes, degree, maxWeight, maxPotential)
+
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("bellmanford", opts, startup, func() int64 {
+		dist, err := shortestPaths(nodes, edges, 0)
+		if err != nil {
+			benchlib.Failf("%v", err)
+		}
+		return distanceSum(dist)
+	})
+
+	// Validate result
+	benchlib.ValidateSeed(opts.Seed, stats.Result, expectedResult)
+}
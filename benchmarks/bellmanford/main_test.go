@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+func TestShortestPathsNegativeEdges(t *testing.T) {
+	//	0 →(4) 1 →(−2) 3
+	//	0 →(5) 2 →(−4) 1
+	//	3 →(1) 4, and 5 has no incoming edge.
+	// Via 2 the path to 1 costs 1, beating the direct 4; 3 and 4 follow.
+	edges := []edge{
+		{0, 1, 4}, {0, 2, 5}, {2, 1, -4}, {1, 3, -2}, {3, 4, 1}, {5, 0, -10},
+	}
+	dist, err := shortestPaths(6, edges, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []int64{0, 1, 5, -1, 0, unreachable}
+	if !slices.Equal(dist, want) {
+		t.Errorf("dist = %v, want %v", dist, want)
+	}
+	if got := distanceSum(dist); got != 5 {
+		t.Errorf("distanceSum = %d, want 5", got)
+	}
+}
+
+func TestShortestPathsEdgeOrderIndependent(t *testing.T) {
+	// The shortest path 0 → 1 → 2 → 3 needs three passes when its edges
+	// are listed backwards.
+	edges := []edge{{2, 3, -1}, {1, 2, -1}, {0, 1, -1}, {0, 3, 0}}
+	dist, err := shortestPaths(4, edges, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int64{0, -1, -2, -3}; !slices.Equal(dist, want) {
+		t.Errorf("dist = %v, want %v", dist, want)
+	}
+}
+
+func TestShortestPathsNegativeCycle(t *testing.T) {
+	// 1 → 2 → 3 → 1 weighs 2 − 3 − 1 = −2.
+	edges := []edge{{0, 1, 1}, {1, 2, 2}, {2, 3, -3}, {3, 1, -1}}
+	if _, err := shortestPaths(4, edges, 0); !errors.Is(err, errNegativeCycle) {
+		t.Errorf("err = %v, want %v", err, errNegativeCycle)
+	}
+}
+
+func TestShortestPathsUnreachableNegativeCycle(t *testing.T) {
+	// The cycle 2 ⇄ 3 is negative but cannot be reached from 0.
+	edges := []edge{{0, 1, 7}, {2, 3, -5}, {3, 2, 1}}
+	dist, err := shortestPaths(4, edges, 0)
+	if err != nil {
+		t.Fatalf("unreachable cycle reported: %v", err)
+	}
+	if want := []int64{0, 7, unreachable, unreachable}; !slices.Equal(dist, want) {
+		t.Errorf("dist = %v, want %v", dist, want)
+	}
+}
+
+func TestRandomEdgesHaveNoNegativeCycle(t *testing.T) {
+	// Small random graphs built like the benchmark's: the distances must
+	// exist and be tight, i.e. no edge relaxes them and every reachable
+	// node but the source is reached by some edge exactly.
+	r := benchlib.NewRand(1)
+	for trial := 0; trial < 20; trial++ {
+		const n = 50
+		edges := randomEdges(r, n, 3, 10, 100)
+		dist, err := shortestPaths(n, edges, 0)
+		if err != nil {
+			t.Fatalf("trial %d: %v", trial, err)
+		}
+		tight := make([]bool, n)
+		tight[0] = true
+		for _, e := range edges {
+			if dist[e.from] == unreachable {
+				continue
+			}
+			switch d := dist[e.from] + e.weight; {
+			case d < dist[e.to]:
+				t.Fatalf("trial %d: edge %v still relaxes dist %d", trial, e, dist[e.to])
+			case d == dist[e.to]:
+				tight[e.to] = true
+			}
+		}
+		for v, d := range dist {
+			if d != unreachable && !tight[v] {
+				t.Errorf("trial %d: node %d at distance %d has no tight edge", trial, v, d)
+			}
+		}
+	}
+}
+
+func TestExpectedResult(t *testing.T) {
+	edges := randomEdges(benchlib.NewRand(benchlib.DefaultSeed), nodes, degree, maxWeight, maxPotential)
+	dist, err := shortestPaths(nodes, edges, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := distanceSum(dist); got != expectedResult {
+		t.Errorf("distanceSum = %d, want %d", got, expectedResult)
+	}
+}
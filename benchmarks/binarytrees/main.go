@@ -0,0 +1,90 @@
+/*
+ * Binary Trees
+ *
+ * The Computer Language Benchmarks Game "binary-trees" workload at
+ * maxDepth 16: allocate a stretch tree of depth 17 and check it, keep a
+ * long-lived tree of depth 16 alive, then for each depth 4, 6, …, 16 build
+ * and check 2^(16−depth+4) short-lived perfect trees. Checking a tree
+ * counts its nodes. RESULT is the sum of every check.
+ * Expected result: 14985902
+ *
+ * This benchmark tests:
+ * - Allocation of many small objects (run with --mem)
+ * - Garbage collector throughput with a long-lived heap
+ * - Recursion
+ */
+
+package main
+
+import (
+	"flag"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	maxDepth = 16
+	minDepth = 4
+
+	expectedResult = 14985902
+)
+
+// node is a binary tree node; leaves have both children nil.
+type node struct {
+	left, right *node
+}
+
+// bottomUp builds a perfect binary tree of the given depth.
+func bottomUp(depth int) *node {
+	if depth <= 0 {
+		return &node{}
+	}
+	return &node{left: bottomUp(depth - 1), right: bottomUp(depth - 1)}
+}
+
+// check returns the number of nodes in t.
+func (t *node) check() int64 {
+	if t.left == nil {
+		return 1
+	}
+	return 1 + t.left.check() + t.right.check()
+}
+
+// binaryTrees runs the workload for max(minDepth+2, depth) and returns the
+// sum of all checks.
+func binaryTrees(depth int) int64 {
+	depth = max(minDepth+2, depth)
+
+	total := bottomUp(depth + 1).check()
+
+	longLived := bottomUp(depth)
+	for d := minDepth; d <= depth; d += 2 {
+		iterations := 1 << (depth - d + minDepth)
+		for i := 0; i < iterations; i++ {
+			total += bottomUp(d).check()
+		}
+	}
+	return total + longLived.check()
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "binarytrees", Category: benchlib.CategoryMemory, Expected: expectedResult})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	t0 := time.Now()
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("binarytrees", opts, startup, func() int64 {
+		return binaryTrees(maxDepth)
+	})
+
+	// Validate result
+	benchlib.Validate(stats.Result, expectedResult)
+}
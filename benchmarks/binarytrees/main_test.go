@@ -0,0 +1,38 @@
+package main
+
+import "testing"
+
+// expectedChecks is the closed form of binaryTrees: every check counts the
+// 2^(d+1)−1 nodes of a perfect tree of depth d.
+func expectedChecks(depth int) int64 {
+	depth = max(minDepth+2, depth)
+	nodes := func(d int) int64 { return 1<<(d+1) - 1 }
+	total := nodes(depth+1) + nodes(depth)
+	for d := minDepth; d <= depth; d += 2 {
+		total += int64(1<<(depth-d+minDepth)) * nodes(d)
+	}
+	return total
+}
+
+func TestCheckCountsNodes(t *testing.T) {
+	for depth, want := range []int64{1, 3, 7, 15, 31} {
+		if got := bottomUp(depth).check(); got != want {
+			t.Errorf("depth %d: check = %d, want %d", depth, got, want)
+		}
+	}
+}
+
+func TestBinaryTreesSmallDepth(t *testing.T) {
+	// Depths below minDepth+2 are raised to it, as in the reference.
+	for _, depth := range []int{0, 6, 7, 8, 10} {
+		if got, want := binaryTrees(depth), expectedChecks(depth); got != want {
+			t.Errorf("binaryTrees(%d) = %d, want %d", depth, got, want)
+		}
+	}
+}
+
+func TestExpectedResultMatchesClosedForm(t *testing.T) {
+	if got := expectedChecks(maxDepth); got != expectedResult {
+		t.Errorf("closed form at depth %d = %d, expectedResult = %d", maxDepth, got, int64(expectedResult))
+	}
+}
@@ -0,0 +1,152 @@
+/*
+ * Bloom Filter
+ *
+ * Insert 1,000,000 keys into a Bloom filter of m = 2^23 bits (1 MiB) with
+ * k = 6 hash functions, then query 1,000,000 keys: even-numbered queries
+ * are the inserted keys in order, odd-numbered ones fresh keys. Keys are
+ * successive r.Uint64() values from benchlib.NewRand(benchlib.DefaultSeed),
+ * the inserted ones drawn first. RESULT is the number of queries the
+ * filter answers "maybe present": the 500,000 inserted keys plus the false
+ * positives among the 500,000 fresh ones.
+ * Expected result: 508953
+ *
+ * With about 8.4 bits per key, k = 6 is the integer nearest the optimum
+ * (m/n)·ln 2 ≈ 5.8, and the expected false-positive rate is
+ * (1 − e^(−kn/m))^k ≈ 1.78%, some 8,900 of the fresh queries; this input
+ * gets 8,953. The k bit positions come from one 64-bit mix of the key by
+ * double hashing: h1 + i·h2 mod m for i = 0..k−1, with h1 and h2 the low
+ * and high halves of the mix and h2 forced odd. The filter is allocated
+ * and filled inside every compute run.
+ *
+ * This benchmark tests:
+ * - Integer hashing and multiply-heavy mixing
+ * - Scattered single-bit reads and writes over a 1 MiB array
+ */
+
+package main
+
+import (
+	"flag"
+	"math/rand"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	numKeys    = 1000000
+	numQueries = 1000000
+
+	// logBits is log2 of the filter size m in bits.
+	logBits   = 23
+	numHashes = 6
+
+	expectedPositives = 508953
+)
+
+// bloom is a Bloom filter of a power-of-two number of bits using numHashes
+// hashes.
+type bloom struct {
+	bits []uint64
+}
+
+func newBloom(logBits int) *bloom {
+	return &bloom{bits: make([]uint64, (1<<logBits)/64)}
+}
+
+// mix is the 64-bit finalizer of MurmurHash3, a bijection that spreads
+// every input bit over the whole output.
+func mix(x uint64) uint64 {
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+// add sets key's numHashes bits.
+func (b *bloom) add(key uint64) {
+	mask := uint32(len(b.bits)*64 - 1)
+	h := mix(key)
+	h1, h2 := uint32(h), uint32(h>>32)|1
+	for i := uint32(0); i < numHashes; i++ {
+		pos := (h1 + i*h2) & mask
+		b.bits[pos/64] |= 1 << (pos % 64)
+	}
+}
+
+// mayContain reports whether all of key's bits are set: always true for an
+// added key, and true for others at the false-positive rate.
+func (b *bloom) mayContain(key uint64) bool {
+	mask := uint32(len(b.bits)*64 - 1)
+	h := mix(key)
+	h1, h2 := uint32(h), uint32(h>>32)|1
+	for i := uint32(0); i < numHashes; i++ {
+		pos := (h1 + i*h2) & mask
+		if b.bits[pos/64]&(1<<(pos%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// makeQueries interleaves the first inserted keys with fresh keys from r:
+// query 2i is keys[i] and query 2i+1 a new r.Uint64().
+func makeQueries(r *rand.Rand, keys []uint64, n int) []uint64 {
+	queries := make([]uint64, n)
+	for i := range queries {
+		if i%2 == 0 {
+			queries[i] = keys[i/2]
+		} else {
+			queries[i] = r.Uint64()
+		}
+	}
+	return queries
+}
+
+// countPositives builds a filter of keys and returns how many queries it
+// reports as maybe present.
+func countPositives(keys, queries []uint64) int64 {
+	b := newBloom(logBits)
+	for _, k := range keys {
+		b.add(k)
+	}
+	var positives int64
+	for _, q := range queries {
+		if b.mayContain(q) {
+			positives++
+		}
+	}
+	return positives
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "bloom", Category: benchlib.CategoryMemory, Expected: expectedPositives})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	t0 := time.Now()
+
+	// Startup phase: draw the keys to insert and to query
+	r := benchlib.NewRand(opts.Seed)
+	keys := make([]uint64, numKeys)
+	for i := range keys {
+		keys[i] = r.Uint64()
+	}
+	queries := makeQueries(r, keys, numQueries)
+
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("bloom", opts, startup, func() int64 {
+		return countPositives(keys, queries)
+	})
+
+	// Validate result
+	benchlib.ValidateSeed(opts.Seed, stats.Result, expectedPositives)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+func TestNoFalseNegatives(t *testing.T) {
+	r := benchlib.NewRand(1)
+	b := newBloom(16)
+	keys := make([]uint64, 5000)
+	for i := range keys {
+		keys[i] = r.Uint64()
+		b.add(keys[i])
+	}
+	for _, k := range keys {
+		if !b.mayContain(k) {
+			t.Fatalf("added key %#x reported absent", k)
+		}
+	}
+}
+
+func TestFalsePositiveRate(t *testing.T) {
+	// 2^20 bits holding 125,000 keys is the benchmark's 8.4 bits per key.
+	const logBits, n, probes = 20, 125000, 200000
+	r := benchlib.NewRand(2)
+	b := newBloom(logBits)
+	for i := 0; i < n; i++ {
+		b.add(r.Uint64())
+	}
+	fp := 0
+	for i := 0; i < probes; i++ {
+		if b.mayContain(r.Uint64()) {
+			fp++
+		}
+	}
+	got := float64(fp) / probes
+	want := math.Pow(1-math.Exp(-numHashes*float64(n)/(1<<logBits)), numHashes)
+	// The count is binomial with a standard deviation near 0.03% here.
+	if math.Abs(got-want) > 0.002 {
+		t.Errorf("false-positive rate = %.4f, want %.4f ± 0.002", got, want)
+	}
+}
+
+func TestEmptyFilter(t *testing.T) {
+	b := newBloom(10)
+	if b.mayContain(0) || b.mayContain(42) {
+		t.Error("empty filter reports a key as present")
+	}
+}
+
+func TestMakeQueries(t *testing.T) {
+	keys := []uint64{10, 20, 30}
+	q := makeQueries(benchlib.NewRand(3), keys, 5)
+	if q[0] != 10 || q[2] != 20 || q[4] != 30 {
+		t.Errorf("even queries = %d, %d, %d; want the keys 10, 20, 30", q[0], q[2], q[4])
+	}
+}
+
+func TestExpectedPositives(t *testing.T) {
+	r := benchlib.NewRand(benchlib.DefaultSeed)
+	keys := make([]uint64, numKeys)
+	for i := range keys {
+		keys[i] = r.Uint64()
+	}
+	if got := countPositives(keys, makeQueries(r, keys, numQueries)); got != expectedPositives {
+		t.Errorf("countPositives = %d, want %d", got, expectedPositives)
+	}
+}
@@ -0,0 +1,200 @@
+/*
+ * Unbalanced Binary Search Tree
+ *
+ * Insert keys into a plain binary search tree, with no rebalancing, then
+ * look up as many keys again; RESULT is the total number of key
+ * comparisons the lookups make, one per node visited, a direct measure of
+ * the depth they reach.
+ *
+ * With the default --order=random the keys are those of rbtree: 1,000,000
+ * inserts and then 1,000,000 lookups of r.Uint64() % 2,000,000 from
+ * benchlib.NewRand(benchlib.DefaultSeed), so the two benchmarks contrast
+ * an unbalanced and a balanced tree on the same input. A random insertion
+ * order keeps the expected depth near 2·ln n, some 1.4 times that of a
+ * perfectly balanced tree; these lookups average 25.3 comparisons over
+ * the 787,294 distinct keys.
+ * Expected result: 25300813
+ *
+ * --order=sorted is the worst case: the 20,000 keys 0, 2, 4, ... inserted
+ * in ascending order build a tree that is one long chain to the right,
+ * every insert walking all of it. The 20,000 lookups, r.Uint64() % 40,000
+ * from the same generator, then cost about n/2 comparisons each instead
+ * of about log2 n.
+ * Expected result (--order=sorted): 200976753
+ *
+ * Duplicate inserts leave the tree unchanged. The tree is rebuilt from
+ * empty on every run.
+ *
+ * This benchmark tests:
+ * - Pointer chasing with data-dependent branches
+ * - Allocation of many small nodes
+ * - The cost of an unbalanced tree's depth
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	randomInserts  = 1000000
+	randomLookups  = 1000000
+	randomKeySpace = 2000000
+
+	// sortedKeys is kept small because building the chain costs
+	// sortedKeys²/2 comparisons.
+	sortedKeys = 20000
+
+	expectedRandom = 25300813
+	expectedSorted = 200976753
+)
+
+// Values accepted by --order.
+const (
+	orderRandom = "random"
+	orderSorted = "sorted"
+)
+
+// node is a tree node; a nil child is an empty subtree.
+type node struct {
+	key         uint32
+	left, right *node
+}
+
+// tree is a binary search tree of distinct keys. The zero value is an
+// empty tree.
+type tree struct {
+	root *node
+	size int
+}
+
+// insert adds key and reports whether it was new.
+func (t *tree) insert(key uint32) bool {
+	link := &t.root
+	for n := *link; n != nil; n = *link {
+		switch {
+		case key < n.key:
+			link = &n.left
+		case key > n.key:
+			link = &n.right
+		default:
+			return false
+		}
+	}
+	*link = &node{key: key}
+	t.size++
+	return true
+}
+
+// search reports whether key is in t and how many nodes it compared key
+// with on the way.
+func (t *tree) search(key uint32) (found bool, comparisons int) {
+	for n := t.root; n != nil; {
+		comparisons++
+		switch {
+		case key < n.key:
+			n = n.left
+		case key > n.key:
+			n = n.right
+		default:
+			return true, comparisons
+		}
+	}
+	return false, comparisons
+}
+
+// inorder returns the keys of t in ascending order. It walks with an
+// explicit stack, since a degenerate tree is as deep as it is large.
+func (t *tree) inorder() []uint32 {
+	keys := make([]uint32, 0, t.size)
+	var stack []*node
+	for n := t.root; n != nil || len(stack) > 0; n = n.right {
+		for ; n != nil; n = n.left {
+			stack = append(stack, n)
+		}
+		n = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		keys = append(keys, n.key)
+	}
+	return keys
+}
+
+// randomKeys returns n keys in [0, keySpace).
+func randomKeys(r *rand.Rand, n int, keySpace uint64) []uint32 {
+	keys := make([]uint32, n)
+	for i := range keys {
+		keys[i] = uint32(r.Uint64() % keySpace)
+	}
+	return keys
+}
+
+// evenKeys returns 0, 2, ..., 2(n-1).
+func evenKeys(n int) []uint32 {
+	keys := make([]uint32, n)
+	for i := range keys {
+		keys[i] = uint32(2 * i)
+	}
+	return keys
+}
+
+// countComparisons builds a tree of insertKeys and returns the total
+// comparisons made looking up each of lookupKeys.
+func countComparisons(insertKeys, lookupKeys []uint32) int64 {
+	var t tree
+	for _, k := range insertKeys {
+		t.insert(k)
+	}
+	var total int64
+	for _, k := range lookupKeys {
+		_, c := t.search(k)
+		total += int64(c)
+	}
+	return total
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "bst", Category: benchlib.CategoryMemory, Expected: expectedRandom})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	order := flag.String("order", orderRandom, "insertion order: random or sorted (the degenerate worst case)")
+	flag.Parse()
+	if *order != orderRandom && *order != orderSorted {
+		fmt.Fprintf(os.Stderr, "bst: unknown --order %q (want %s or %s)\n", *order, orderRandom, orderSorted)
+		os.Exit(2)
+	}
+
+	t0 := time.Now()
+
+	// Startup phase: draw the keys to insert and to look up
+	r := benchlib.NewRand(opts.Seed)
+	var insertKeys, lookupKeys []uint32
+	expected := int64(expectedRandom)
+	if *order == orderSorted {
+		insertKeys = evenKeys(sortedKeys)
+		lookupKeys = randomKeys(r, sortedKeys, 2*sortedKeys)
+		expected = expectedSorted
+	} else {
+		insertKeys = randomKeys(r, randomInserts, randomKeySpace)
+		lookupKeys = randomKeys(r, randomLookups, randomKeySpace)
+	}
+
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("bst", opts, startup, func() int64 {
+		return countComparisons(insertKeys, lookupKeys)
+	})
+
+	// Validate result
+	benchlib.ValidateSeed(opts.Seed, stats.Result, expected)
+}
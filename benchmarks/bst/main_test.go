@@ -0,0 +1,120 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+func newTree(keys ...uint32) *tree {
+	t := new(tree)
+	for _, k := range keys {
+		t.insert(k)
+	}
+	return t
+}
+
+func TestInsertAndSearch(t *testing.T) {
+	tr := new(tree)
+	if !tr.insert(50) || !tr.insert(30) || !tr.insert(70) {
+		t.Fatal("insert of a new key reported a duplicate")
+	}
+	if tr.insert(30) {
+		t.Error("second insert of 30 reported a new key")
+	}
+	if tr.size != 3 {
+		t.Errorf("size = %d, want 3", tr.size)
+	}
+	tests := []struct {
+		key         uint32
+		found       bool
+		comparisons int
+	}{
+		{50, true, 1},
+		{30, true, 2},
+		{70, true, 2},
+		{40, false, 2},
+		{99, false, 2},
+	}
+	for _, tt := range tests {
+		if found, c := tr.search(tt.key); found != tt.found || c != tt.comparisons {
+			t.Errorf("search(%d) = %v, %d; want %v, %d", tt.key, found, c, tt.found, tt.comparisons)
+		}
+	}
+	if found, c := new(tree).search(1); found || c != 0 {
+		t.Errorf("search of an empty tree = %v, %d; want false, 0", found, c)
+	}
+}
+
+func TestRandomInserts(t *testing.T) {
+	r := benchlib.NewRand(benchlib.DefaultSeed)
+	keys := randomKeys(r, 5000, 8000)
+	tr := newTree(keys...)
+
+	want := slices.Compact(slices.Sorted(slices.Values(keys)))
+	if got := tr.inorder(); !slices.Equal(got, want) {
+		t.Fatalf("inorder returned %d keys, want the %d distinct keys sorted", len(got), len(want))
+	}
+	if tr.size != len(want) {
+		t.Errorf("size = %d, want %d", tr.size, len(want))
+	}
+	for k := uint32(0); k < 8000; k++ {
+		_, want := slices.BinarySearch(want, k)
+		if found, _ := tr.search(k); found != want {
+			t.Errorf("search(%d) found = %v, want %v", k, found, want)
+		}
+	}
+}
+
+func TestInorder(t *testing.T) {
+	if got := new(tree).inorder(); len(got) != 0 {
+		t.Errorf("inorder of an empty tree = %v", got)
+	}
+	if got, want := newTree(4, 2, 6, 1, 3, 5, 7).inorder(), []uint32{1, 2, 3, 4, 5, 6, 7}; !slices.Equal(got, want) {
+		t.Errorf("inorder = %v, want %v", got, want)
+	}
+}
+
+func TestSortedInsertDegenerates(t *testing.T) {
+	keys := evenKeys(1000)
+	tr := newTree(keys...)
+	// A chain to the right: every node but the last has only a right child.
+	depth := 0
+	for n := tr.root; n != nil; n = n.right {
+		if n.left != nil {
+			t.Fatalf("node %d has a left child", n.key)
+		}
+		depth++
+	}
+	if depth != len(keys) {
+		t.Errorf("chain depth = %d, want %d", depth, len(keys))
+	}
+	if got := tr.inorder(); !slices.Equal(got, keys) {
+		t.Error("inorder of the chain is not the inserted keys")
+	}
+	// The last key, and any odd key above it, is at the bottom.
+	if _, c := tr.search(1998); c != 1000 {
+		t.Errorf("search(1998) made %d comparisons, want 1000", c)
+	}
+	if _, c := tr.search(1999); c != 1000 {
+		t.Errorf("search(1999) made %d comparisons, want 1000", c)
+	}
+}
+
+func TestCountComparisons(t *testing.T) {
+	// 50 at the root, 30 and 70 below: 1 + 2 + 2 + 2.
+	if got := countComparisons([]uint32{50, 30, 70}, []uint32{50, 30, 70, 60}); got != 7 {
+		t.Errorf("countComparisons = %d, want 7", got)
+	}
+}
+
+func TestSortedExpected(t *testing.T) {
+	if testing.Short() {
+		t.Skip("full degenerate build in short mode")
+	}
+	lookups := randomKeys(benchlib.NewRand(benchlib.DefaultSeed), sortedKeys, 2*sortedKeys)
+	if got := countComparisons(evenKeys(sortedKeys), lookups); got != expectedSorted {
+		t.Errorf("--order=sorted result = %d, want %d", got, expectedSorted)
+	}
+}
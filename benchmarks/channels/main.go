@@ -0,0 +1,111 @@
+/*
+ * Channel Producer/Consumer
+ *
+ * Producers send the integers 0..N-1 (N = 2,000,000, split into disjoint
+ * ranges) over one buffered channel; consumers drain it and sum what they
+ * receive. The total must equal N·(N−1)/2, proving no message was lost or
+ * duplicated.
+ * Expected result: 1999999000000
+ *
+ * This benchmark tests:
+ * - Channel send/receive and goroutine scheduling
+ * - Contention on a shared channel as goroutine counts grow
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	messages   = 2000000
+	bufferSize = 1024
+)
+
+// expectedSum is the closed form of 0 + 1 + … + (n−1).
+func expectedSum(n int) int64 {
+	return int64(n) * int64(n-1) / 2
+}
+
+// exchange sends 0..n-1 from producers goroutines to consumers goroutines
+// over a channel with the given buffer and returns the sum received.
+func exchange(n, producers, consumers, buffer int) int64 {
+	ch := make(chan int, buffer)
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		// Producer p sends [lo, hi); the last range absorbs the remainder.
+		lo := p * (n / producers)
+		hi := lo + n/producers
+		if p == producers-1 {
+			hi = n
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for v := lo; v < hi; v++ {
+				ch <- v
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(ch)
+	}()
+
+	sums := make([]int64, consumers)
+	var cwg sync.WaitGroup
+	for c := 0; c < consumers; c++ {
+		cwg.Add(1)
+		go func() {
+			defer cwg.Done()
+			var sum int64
+			for v := range ch {
+				sum += int64(v)
+			}
+			sums[c] = sum
+		}()
+	}
+	cwg.Wait()
+
+	var total int64
+	for _, s := range sums {
+		total += s
+	}
+	return total
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "channels", Category: benchlib.CategoryConcurrency, Expected: expectedSum(messages)})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	opts.Concurrent = true
+	producers := flag.Int("producers", 4, "number of producer goroutines")
+	consumers := flag.Int("consumers", 4, "number of consumer goroutines")
+	flag.Parse()
+	if *producers < 1 || *consumers < 1 {
+		fmt.Fprintf(os.Stderr, "channels: --producers and --consumers must be >= 1\n")
+		os.Exit(2)
+	}
+
+	t0 := time.Now()
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("channels", opts, startup, func() int64 {
+		return exchange(messages, *producers, *consumers, bufferSize)
+	})
+
+	// Validate result
+	benchlib.Validate(stats.Result, expectedSum(messages))
+}
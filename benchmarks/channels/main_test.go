@@ -0,0 +1,32 @@
+package main
+
+import "testing"
+
+func TestExchangeNoMessagesLost(t *testing.T) {
+	for _, producers := range []int{1, 2, 3, 8} {
+		for _, consumers := range []int{1, 2, 5, 16} {
+			for _, buffer := range []int{0, 1, 64} {
+				// 1001 is not divisible by most producer counts.
+				const n = 1001
+				if got, want := exchange(n, producers, consumers, buffer), expectedSum(n); got != want {
+					t.Errorf("producers=%d consumers=%d buffer=%d: sum = %d, want %d",
+						producers, consumers, buffer, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestExchangeMoreProducersThanMessages(t *testing.T) {
+	if got, want := exchange(3, 8, 2, 1), expectedSum(3); got != want {
+		t.Errorf("sum = %d, want %d", got, want)
+	}
+}
+
+func TestExpectedSum(t *testing.T) {
+	for n, want := range map[int]int64{1: 0, 2: 1, 10: 45, messages: 1999999000000} {
+		if got := expectedSum(n); got != want {
+			t.Errorf("expectedSum(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+/*
+ * Collatz Longest Chain
+ *
+ * Find the starting number below 1,000,000 whose Collatz sequence (n → n/2
+ * if n is even, 3n+1 if odd) takes the most steps to reach 1 (Project
+ * Euler 14). Every chain is walked from scratch with no memoization, so
+ * the work is about 130 million data-dependent branches. Values are int64:
+ * some chains peak far above 2^31. Ties go to the smaller start.
+ * Expected result: 837799 (524 steps)
+ *
+ * This benchmark tests:
+ * - Unpredictable branches in a tight loop
+ * - 64-bit integer shift, multiply and add
+ */
+
+package main
+
+import (
+	"flag"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	limit         = 1000000
+	expectedStart = 837799
+)
+
+// steps returns the number of Collatz steps from n down to 1, for n >= 1.
+func steps(n int64) int {
+	count := 0
+	for n != 1 {
+		if n%2 == 0 {
+			n /= 2
+		} else {
+			n = 3*n + 1
+		}
+		count++
+	}
+	return count
+}
+
+// longestChain returns the start in [1, below) with the most steps, and
+// that number of steps.
+func longestChain(below int64) (start int64, length int) {
+	start, length = 1, 0
+	for n := int64(2); n < below; n++ {
+		if s := steps(n); s > length {
+			start, length = n, s
+		}
+	}
+	return start, length
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "collatz", Category: benchlib.CategoryNumeric, Expected: expectedStart})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	t0 := time.Now()
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("collatz", opts, startup, func() int64 {
+		start, _ := longestChain(limit)
+		return start
+	})
+
+	// Validate result
+	benchlib.Validate(stats.Result, expectedStart)
+}
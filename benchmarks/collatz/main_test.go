@@ -0,0 +1,54 @@
+package main
+
+import "testing"
+
+func TestSteps(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want int
+	}{
+		{1, 0},
+		{2, 1},
+		{3, 7}, // 3 10 5 16 8 4 2 1
+		{6, 8},
+		{7, 16},
+		{9, 19},
+		{27, 111},
+		{97, 118},
+		{871, 178},
+		{837799, 524},
+	}
+	for _, tt := range tests {
+		if got := steps(tt.n); got != tt.want {
+			t.Errorf("steps(%d) = %d, want %d", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestStepsBeyondInt32(t *testing.T) {
+	// The chain from 113383 peaks at 2,482,111,348, above MaxInt32.
+	if got := steps(113383); got != 247 {
+		t.Errorf("steps(113383) = %d, want 247", got)
+	}
+}
+
+func TestLongestChain(t *testing.T) {
+	tests := []struct {
+		below     int64
+		wantStart int64
+		wantLen   int
+	}{
+		{2, 1, 0},
+		{10, 9, 19},
+		{100, 97, 118},
+		{1000, 871, 178},
+		{limit, expectedStart, 524},
+	}
+	for _, tt := range tests {
+		start, length := longestChain(tt.below)
+		if start != tt.wantStart || length != tt.wantLen {
+			t.Errorf("longestChain(%d) = %d (%d steps), want %d (%d steps)",
+				tt.below, start, length, tt.wantStart, tt.wantLen)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+/*
+ * CRC-32 Throughput
+ *
+ * Checksum 128 MiB with a table-driven CRC-32 (IEEE 802.3 polynomial,
+ * reflected, as used by zlib and Ethernet): a 1 MiB deterministic buffer
+ * (benchlib.RandomBytes seeded with benchlib.DefaultSeed) is streamed into
+ * one running CRC 128 times. The implementation is the classic one-byte-at-
+ * a-time table lookup, not hash/crc32, whose slicing-by-8 and CLMUL assembly
+ * would not be a fair cross-language baseline. With another --seed there
+ * is no expected value, and RESULT is checked against hash/crc32 instead.
+ * Expected result: 0x79c9b76f (printed as 2043262831)
+ *
+ * This benchmark tests:
+ * - Table lookups indexed by data-dependent bytes
+ * - A loop-carried dependency through the running CRC
+ * - Sequential streaming over a buffer larger than L2
+ */
+
+package main
+
+import (
+	"flag"
+	"hash/crc32"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	bufferSize = 1 << 20
+	rounds     = 128
+
+	// expectedCRC is crc32.ChecksumIEEE of the same 128 MiB.
+	expectedCRC = 0x79c9b76f
+
+	// ieee is the reversed IEEE 802.3 polynomial, crc32.IEEE.
+	ieee = 0xedb88320
+)
+
+// makeTable returns the 256-entry lookup table for the reflected
+// polynomial poly: entry b is the CRC register after shifting in b alone.
+func makeTable(poly uint32) *[256]uint32 {
+	var t [256]uint32
+	for b := range t {
+		crc := uint32(b)
+		for i := 0; i < 8; i++ {
+			if crc&1 == 1 {
+				crc = crc>>1 ^ poly
+			} else {
+				crc >>= 1
+			}
+		}
+		t[b] = crc
+	}
+	return &t
+}
+
+// update returns the CRC of the data whose CRC so far is crc followed by
+// p. Like crc32.Update, crc is the finished (complemented) value, so
+// update(0, t, p) starts a new checksum.
+func update(crc uint32, t *[256]uint32, p []byte) uint32 {
+	crc = ^crc
+	for _, b := range p {
+		crc = t[byte(crc)^b] ^ crc>>8
+	}
+	return ^crc
+}
+
+// checksumRepeated returns the CRC of buf concatenated with itself n times.
+func checksumRepeated(t *[256]uint32, buf []byte, n int) uint32 {
+	var crc uint32
+	for i := 0; i < n; i++ {
+		crc = update(crc, t, buf)
+	}
+	return crc
+}
+
+// stdlibCRC returns the CRC of buf concatenated with itself n times as
+// computed by hash/crc32, the reference for checksumRepeated.
+func stdlibCRC(buf []byte, n int) uint32 {
+	var crc uint32
+	for i := 0; i < n; i++ {
+		crc = crc32.Update(crc, crc32.IEEETable, buf)
+	}
+	return crc
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "crc32", Category: benchlib.CategoryNumeric, Expected: expectedCRC})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	t0 := time.Now()
+
+	// Startup phase: build the table and the input buffer so compute only
+	// times checksumming
+	table := makeTable(ieee)
+	buf := benchlib.RandomBytes(benchlib.NewRand(opts.Seed), bufferSize)
+
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("crc32", opts, startup, func() int64 {
+		return int64(checksumRepeated(table, buf, rounds))
+	})
+
+	// Validate result
+	if opts.Seed != benchlib.DefaultSeed {
+		if want := int64(stdlibCRC(buf, rounds)); stats.Result != want {
+			benchlib.Failf("hash/crc32 gives %d, got %d", want, stats.Result)
+		}
+	}
+	benchlib.ValidateSeed(opts.Seed, stats.Result, expectedCRC)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"hash/crc32"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+func TestTableMatchesStdlib(t *testing.T) {
+	if got, want := *makeTable(ieee), *crc32.MakeTable(crc32.IEEE); got != want {
+		t.Error("makeTable(ieee) differs from crc32.MakeTable(crc32.IEEE)")
+	}
+}
+
+func TestUpdateMatchesStdlib(t *testing.T) {
+	table := makeTable(ieee)
+	for _, s := range []string{
+		"",
+		"a",
+		"abc",
+		"123456789", // the standard check input; CRC-32/ISO-HDLC is 0xcbf43926
+		"The quick brown fox jumps over the lazy dog",
+		"\x00\x00\x00\x00",
+		"\xff\xff\xff\xff",
+	} {
+		if got, want := update(0, table, []byte(s)), crc32.ChecksumIEEE([]byte(s)); got != want {
+			t.Errorf("update(%q) = %#x, want %#x", s, got, want)
+		}
+	}
+	if got := update(0, table, []byte("123456789")); got != 0xcbf43926 {
+		t.Errorf("check value = %#x, want 0xcbf43926", got)
+	}
+}
+
+func TestUpdateStreams(t *testing.T) {
+	table := makeTable(ieee)
+	data := benchlib.RandomBytes(benchlib.NewRand(1), 1000)
+	whole := update(0, table, data)
+	for _, split := range []int{0, 1, 7, 500, 999, 1000} {
+		if got := update(update(0, table, data[:split]), table, data[split:]); got != whole {
+			t.Errorf("split at %d: %#x, want %#x", split, got, whole)
+		}
+	}
+}
+
+func TestExpectedCRC(t *testing.T) {
+	buf := benchlib.RandomBytes(benchlib.NewRand(benchlib.DefaultSeed), bufferSize)
+	if got := checksumRepeated(makeTable(ieee), buf, rounds); got != expectedCRC {
+		t.Errorf("crc = %#x, want %#x", got, expectedCRC)
+	}
+	if std := stdlibCRC(buf, rounds); std != expectedCRC {
+		t.Errorf("hash/crc32 = %#x, want %#x", std, expectedCRC)
+	}
+}
+
+func TestStdlibCRC(t *testing.T) {
+	// The check main makes under --seed=7, on a smaller buffer.
+	buf := benchlib.RandomBytes(benchlib.NewRand(7), 4099)
+	if got, want := checksumRepeated(makeTable(ieee), buf, 5), stdlibCRC(buf, 5); got != want {
+		t.Errorf("checksumRepeated = %#x, hash/crc32 gives %#x", got, want)
+	}
+}
@@ -0,0 +1,208 @@
+/*
+ * Dijkstra Shortest Paths
+ *
+ * Build a deterministic directed graph of 100,000 nodes with 5 outgoing
+ * edges each (targets and weights in [1, 1000] drawn from benchlib.NewRand
+ * seeded with benchlib.DefaultSeed) during startup, then compute
+ * single-source shortest paths from node 0 with a binary-heap Dijkstra.
+ * RESULT is the sum of all finite distances modulo 1,000,000,007 (99,335
+ * of the nodes are reachable).
+ * Expected result: 254673841
+ *
+ * This benchmark tests:
+ * - Priority-queue (binary heap) push/pop
+ * - Irregular, pointer-chasing memory access over adjacency lists
+ * - 64-bit integer arithmetic
+ */
+
+package main
+
+import (
+	"flag"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	nodes     = 100000
+	degree    = 5
+	maxWeight = 1000
+
+	expectedResult = 254673841
+
+	// resultModulus keeps the distance sum in range.
+	resultModulus = 1000000007
+)
+
+// unreachable is the distance of a node with no path from the source.
+const unreachable = math.MaxInt64
+
+// edge is a directed, weighted edge.
+type edge struct {
+	from, to int
+	weight   int
+}
+
+// graph stores adjacency lists in compressed sparse row form: the edges
+// leaving u are to[start[u]:start[u+1]] with matching weights.
+type graph struct {
+	start  []int
+	to     []int32
+	weight []int32
+}
+
+// newGraph builds a graph of n nodes from edges.
+func newGraph(n int, edges []edge) graph {
+	g := graph{
+		start:  make([]int, n+1),
+		to:     make([]int32, len(edges)),
+		weight: make([]int32, len(edges)),
+	}
+	for _, e := range edges {
+		g.start[e.from+1]++
+	}
+	for u := 0; u < n; u++ {
+		g.start[u+1] += g.start[u]
+	}
+	next := make([]int, n)
+	copy(next, g.start[:n])
+	for _, e := range edges {
+		i := next[e.from]
+		g.to[i] = int32(e.to)
+		g.weight[i] = int32(e.weight)
+		next[e.from]++
+	}
+	return g
+}
+
+// randomEdges returns degree edges out of each of n nodes with uniformly
+// chosen targets and weights in [1, maxWeight]. Only r.Uint64 is used, so
+// the graph is reproducible from the SplitMix64 stream alone.
+func randomEdges(r *rand.Rand, n, degree, maxWeight int) []edge {
+	edges := make([]edge, 0, n*degree)
+	for u := 0; u < n; u++ {
+		for j := 0; j < degree; j++ {
+			v := int(r.Uint64() % uint64(n))
+			w := 1 + int(r.Uint64()%uint64(maxWeight))
+			edges = append(edges, edge{from: u, to: v, weight: w})
+		}
+	}
+	return edges
+}
+
+// item is a heap entry: a tentative distance to node.
+type item struct {
+	dist int64
+	node int32
+}
+
+// minHeap is a binary min-heap of items ordered by dist.
+type minHeap []item
+
+func (h *minHeap) push(it item) {
+	*h = append(*h, it)
+	s := *h
+	i := len(s) - 1
+	for i > 0 {
+		parent := (i - 1) / 2
+		if s[parent].dist <= s[i].dist {
+			break
+		}
+		s[parent], s[i] = s[i], s[parent]
+		i = parent
+	}
+}
+
+func (h *minHeap) pop() item {
+	s := *h
+	top := s[0]
+	last := len(s) - 1
+	s[0] = s[last]
+	s = s[:last]
+	i := 0
+	for {
+		l := 2*i + 1
+		if l >= len(s) {
+			break
+		}
+		m := l
+		if r := l + 1; r < len(s) && s[r].dist < s[l].dist {
+			m = r
+		}
+		if s[i].dist <= s[m].dist {
+			break
+		}
+		s[i], s[m] = s[m], s[i]
+		i = m
+	}
+	*h = s
+	return top
+}
+
+// shortestPaths returns the distance from src to every node, unreachable
+// where there is no path. Stale heap entries are skipped on pop instead of
+// being decreased in place.
+func shortestPaths(g graph, src int) []int64 {
+	n := len(g.start) - 1
+	dist := make([]int64, n)
+	for i := range dist {
+		dist[i] = unreachable
+	}
+	dist[src] = 0
+
+	h := minHeap{{dist: 0, node: int32(src)}}
+	for len(h) > 0 {
+		it := h.pop()
+		u := int(it.node)
+		if it.dist > dist[u] {
+			continue
+		}
+		for i := g.start[u]; i < g.start[u+1]; i++ {
+			v := g.to[i]
+			if d := it.dist + int64(g.weight[i]); d < dist[v] {
+				dist[v] = d
+				h.push(item{dist: d, node: v})
+			}
+		}
+	}
+	return dist
+}
+
+// distanceSum returns the sum of the finite distances mod resultModulus.
+func distanceSum(dist []int64) int64 {
+	var sum int64
+	for _, d := range dist {
+		if d != unreachable {
+			sum = (sum + d) % resultModulus
+		}
+	}
+	return sum
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "dijkstra", Category: benchlib.CategoryAlgorithm, Expected: expectedResult})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	t0 := time.Now()
+
+	// Startup phase: generate the graph
+	g := newGraph(nodes, randomEdges(benchlib.NewRand(opts.Seed), nodes, degree, maxWeight))
+
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("dijkstra", opts, startup, func() int64 {
+		return distanceSum(shortestPaths(g, 0))
+	})
+
+	// Validate result
+	benchlib.ValidateSeed(opts.Seed, stats.Result, expectedResult)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+// The hand-drawn graph below has an isolated node 5. 0→2→1 (3) beats the
+// direct 0→1 (4); node 4 is reached at cost 9 by both 0→2→4 and
+// 0→2→1→3→4.
+func TestShortestPathsHandDrawn(t *testing.T) {
+	edges := []edge{
+		{0, 1, 4},
+		{0, 2, 1},
+		{2, 1, 2},
+		{1, 3, 1},
+		{3, 4, 5},
+		{2, 4, 8},
+		{4, 0, 1}, // back edge never improves anything
+	}
+	got := shortestPaths(newGraph(6, edges), 0)
+	want := []int64{0, 3, 1, 4, 9, unreachable}
+	if !slices.Equal(got, want) {
+		t.Errorf("shortestPaths = %v, want %v", got, want)
+	}
+	if sum := distanceSum(got); sum != 17 {
+		t.Errorf("distanceSum = %d, want 17 (unreachable nodes excluded)", sum)
+	}
+}
+
+func TestShortestPathsParallelEdges(t *testing.T) {
+	// The cheaper of two parallel edges and a self-loop must not confuse
+	// the relaxation.
+	edges := []edge{{0, 1, 7}, {0, 1, 2}, {1, 1, 1}}
+	if got := shortestPaths(newGraph(2, edges), 0); !slices.Equal(got, []int64{0, 2}) {
+		t.Errorf("shortestPaths = %v, want [0 2]", got)
+	}
+}
+
+func TestMinHeapOrder(t *testing.T) {
+	var h minHeap
+	for _, d := range []int64{5, 1, 9, 3, 3, 7, 0} {
+		h.push(item{dist: d})
+	}
+	var got []int64
+	for len(h) > 0 {
+		got = append(got, h.pop().dist)
+	}
+	if want := []int64{0, 1, 3, 3, 5, 7, 9}; !slices.Equal(got, want) {
+		t.Errorf("pop order = %v, want %v", got, want)
+	}
+}
+
+func TestRandomEdgesReproducible(t *testing.T) {
+	a := randomEdges(benchlib.NewRand(benchlib.DefaultSeed), 50, degree, maxWeight)
+	b := randomEdges(benchlib.NewRand(benchlib.DefaultSeed), 50, degree, maxWeight)
+	if !slices.Equal(a, b) {
+		t.Fatal("same seed produced different graphs")
+	}
+	for _, e := range a {
+		if e.to < 0 || e.to >= 50 || e.weight < 1 || e.weight > maxWeight {
+			t.Fatalf("edge out of range: %+v", e)
+		}
+	}
+}
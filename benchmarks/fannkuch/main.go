@@ -0,0 +1,134 @@
+/*
+ * Fannkuch-Redux
+ *
+ * For every permutation of 1..N, N = 11, count the flips of the pancake
+ * game: while the first element k is not 1, reverse the first k elements.
+ * The permutations are visited in the order of the Computer Language
+ * Benchmarks Game's reference program (repeated rotation of prefixes
+ * driven by a counter array), so the checksum — the flip counts summed
+ * with alternating sign by permutation index — matches every other port.
+ * RESULT is the maximum flip count; the checksum is printed as CHECKSUM
+ * and validated too. --n selects another size with a published answer.
+ * Expected result: 51 (checksum 556355)
+ *
+ * This benchmark tests:
+ * - Small-array reversal and copying
+ * - Data-dependent loop trip counts
+ * - Permutation enumeration
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const defaultN = 11
+
+// answer is the reference output for one N.
+type answer struct {
+	checksum int64
+	maxFlips int64
+}
+
+// known holds the published reference answers, indexed by N.
+var known = map[int]answer{
+	7:  {228, 16},
+	8:  {1616, 22},
+	9:  {8629, 30},
+	10: {73196, 38},
+	11: {556355, 51},
+	12: {3968050, 65},
+}
+
+// fannkuch visits every permutation of 0..n-1 (elements are 0-based, so
+// the game stops when the first element is 0) and returns the alternating
+// checksum and the maximum flip count. n must be at least 1.
+func fannkuch(n int) (checksum, maxFlips int64) {
+	perm1 := make([]int, n) // the current permutation
+	perm := make([]int, n)  // scratch copy that gets flipped
+	count := make([]int, n)
+	for i := range perm1 {
+		perm1[i] = i
+	}
+
+	r := n
+	for permCount := 0; ; permCount++ {
+		for ; r != 1; r-- {
+			count[r-1] = r
+		}
+
+		copy(perm, perm1)
+		var flips int64
+		for k := perm[0]; k != 0; k = perm[0] {
+			for i, j := 0, k; i < j; i, j = i+1, j-1 {
+				perm[i], perm[j] = perm[j], perm[i]
+			}
+			flips++
+		}
+		maxFlips = max(maxFlips, flips)
+		if permCount%2 == 0 {
+			checksum += flips
+		} else {
+			checksum -= flips
+		}
+
+		// Advance to the next permutation: rotate the first r+1 elements
+		// left by one until a rotation counter has not yet wrapped.
+		for {
+			if r == n {
+				return checksum, maxFlips
+			}
+			perm0 := perm1[0]
+			copy(perm1[:r], perm1[1:r+1])
+			perm1[r] = perm0
+			count[r]--
+			if count[r] > 0 {
+				break
+			}
+			r++
+		}
+	}
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "fannkuch", Category: benchlib.CategoryAlgorithm, Expected: known[defaultN].maxFlips})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	n := flag.Int("n", defaultN, "permutation length, 7 to 12")
+	flag.Parse()
+	want, ok := known[*n]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "fannkuch: --n must be in [7, 12], got %d\n", *n)
+		os.Exit(2)
+	}
+
+	t0 := time.Now()
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	var checksum int64
+	stats := benchlib.Run("fannkuch", opts, startup, func() int64 {
+		var maxFlips int64
+		checksum, maxFlips = fannkuch(*n)
+		return maxFlips
+	})
+
+	if opts.Format == benchlib.FormatText {
+		fmt.Printf("CHECKSUM: %d\n", checksum)
+	}
+
+	// Validate result
+	if checksum != want.checksum {
+		benchlib.Failf("checksum %d, expected %d", checksum, want.checksum)
+	}
+	benchlib.Validate(stats.Result, want.maxFlips)
+}
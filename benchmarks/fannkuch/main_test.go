@@ -0,0 +1,91 @@
+package main
+
+import "testing"
+
+func TestFannkuchSmall(t *testing.T) {
+	for _, n := range []int{7, 8, 9} {
+		checksum, maxFlips := fannkuch(n)
+		if want := known[n]; checksum != want.checksum || maxFlips != want.maxFlips {
+			t.Errorf("fannkuch(%d) = %d, %d; want %d, %d", n, checksum, maxFlips, want.checksum, want.maxFlips)
+		}
+	}
+}
+
+func TestFannkuchTrivial(t *testing.T) {
+	// The only permutations of one and two elements need 0 and 0, 1 flips.
+	for n, want := range map[int]answer{1: {0, 0}, 2: {-1, 1}} {
+		checksum, maxFlips := fannkuch(n)
+		if checksum != want.checksum || maxFlips != want.maxFlips {
+			t.Errorf("fannkuch(%d) = %d, %d; want %d, %d", n, checksum, maxFlips, want.checksum, want.maxFlips)
+		}
+	}
+}
+
+// flips plays the pancake game on a copy of p.
+func flips(p []int) int64 {
+	p = append([]int(nil), p...)
+	var n int64
+	for p[0] != 0 {
+		k := p[0]
+		for i, j := 0, k; i < j; i, j = i+1, j-1 {
+			p[i], p[j] = p[j], p[i]
+		}
+		n++
+	}
+	return n
+}
+
+func TestFlips(t *testing.T) {
+	for _, tt := range []struct {
+		perm []int
+		want int64
+	}{
+		{[]int{0, 1, 2, 3}, 0},
+		{[]int{1, 0, 2, 3}, 1},
+		// 3 1 2 0 → 0 2 1 3 stops after one flip.
+		{[]int{3, 1, 2, 0}, 1},
+		// 2 0 1 → 1 0 2 → 0 1 2.
+		{[]int{2, 0, 1}, 2},
+	} {
+		if got := flips(tt.perm); got != tt.want {
+			t.Errorf("flips(%v) = %d, want %d", tt.perm, got, tt.want)
+		}
+	}
+}
+
+// bruteMaxFlips enumerates permutations in its own order (Heap's
+// algorithm) and returns the maximum flip count, which unlike the
+// checksum does not depend on the visiting order.
+func bruteMaxFlips(n int) int64 {
+	p := make([]int, n)
+	for i := range p {
+		p[i] = i
+	}
+	var best int64
+	var heap func(k int)
+	heap = func(k int) {
+		if k == 1 {
+			best = max(best, flips(p))
+			return
+		}
+		for i := 0; i < k-1; i++ {
+			heap(k - 1)
+			if k%2 == 0 {
+				p[i], p[k-1] = p[k-1], p[i]
+			} else {
+				p[0], p[k-1] = p[k-1], p[0]
+			}
+		}
+		heap(k - 1)
+	}
+	heap(n)
+	return best
+}
+
+func TestMaxFlipsMatchesBruteForce(t *testing.T) {
+	for n := 1; n <= 8; n++ {
+		if _, got := fannkuch(n); got != bruteMaxFlips(n) {
+			t.Errorf("fannkuch(%d) max flips = %d, brute force %d", n, got, bruteMaxFlips(n))
+		}
+	}
+}
@@ -0,0 +1,175 @@
+/*
+ * Fast Fourier Transform
+ *
+ * Transform a complex signal of N = 2^20 samples with an iterative
+ * radix-2 Cooley–Tukey FFT: a bit-reversal permutation followed by log2 N
+ * butterfly passes whose stride doubles each pass, using a twiddle table
+ * of exp(−2πik/N) built in the startup phase. The signal's samples draw
+ * their real and then imaginary parts as benchlib.RandomFloat − 0.5 from
+ * benchlib.NewRand(benchlib.DefaultSeed). RESULT is benchlib.FloatChecksum
+ * of the magnitude spectrum |X[k]| scaled by 1e3.
+ *
+ * Parseval's identity, Σ|X[k]|² = N·Σ|x[n]|², is also checked, which is
+ * all that --n validates for lengths other than the default. N must be a
+ * power of two.
+ * Expected result: 388468969754
+ *
+ * This benchmark tests:
+ * - Complex multiply-add
+ * - Strided memory access with a stride that doubles every pass
+ * - Bit-reversal permutation
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"math/bits"
+	"math/cmplx"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	defaultN = 1 << 20
+
+	// scale turns magnitudes into checksum units.
+	scale = 1e3
+
+	expectedChecksum = 388468969754
+
+	// parsevalTolerance is the relative error allowed between the two
+	// sides of Parseval's identity.
+	parsevalTolerance = 1e-9
+)
+
+// twiddles returns exp(−2πik/n) for k in [0, n/2).
+func twiddles(n int) []complex128 {
+	w := make([]complex128, n/2)
+	for k := range w {
+		theta := -2 * math.Pi * float64(k) / float64(n)
+		w[k] = complex(math.Cos(theta), math.Sin(theta))
+	}
+	return w
+}
+
+// isPowerOfTwo reports whether n is 2^k for some k >= 0.
+func isPowerOfTwo(n int) bool {
+	return n > 0 && n&(n-1) == 0
+}
+
+// fft transforms x in place into its discrete Fourier transform
+// X[k] = Σ x[j]·exp(−2πijk/N), using w = twiddles(len(x)). It returns an
+// error, leaving x untouched, unless len(x) is a power of two.
+func fft(x, w []complex128) error {
+	n := len(x)
+	if !isPowerOfTwo(n) {
+		return fmt.Errorf("fft length %d is not a power of two", n)
+	}
+	if len(w) != n/2 {
+		return fmt.Errorf("twiddle table has %d entries, want %d", len(w), n/2)
+	}
+
+	// Reorder into bit-reversed index order so the butterflies can work in
+	// place.
+	shift := bits.UintSize - bits.TrailingZeros(uint(n))
+	for i := range x {
+		if j := int(bits.Reverse(uint(i)) >> shift); i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	if n == 1 {
+		return nil
+	}
+
+	// Each pass combines pairs of size/2-point transforms into size-point
+	// ones; the twiddle for butterfly k is w[k·n/size].
+	for size := 2; size <= n; size <<= 1 {
+		half, step := size/2, n/size
+		for start := 0; start < n; start += size {
+			for k := 0; k < half; k++ {
+				a, b := &x[start+k], &x[start+k+half]
+				t := w[k*step] * *b
+				*a, *b = *a+t, *a-t
+			}
+		}
+	}
+	return nil
+}
+
+// randomSignal returns n complex samples as described in the header.
+func randomSignal(r *rand.Rand, n int) []complex128 {
+	x := make([]complex128, n)
+	for i := range x {
+		re := benchlib.RandomFloat(r) - 0.5
+		im := benchlib.RandomFloat(r) - 0.5
+		x[i] = complex(re, im)
+	}
+	return x
+}
+
+// energy returns Σ|x[i]|².
+func energy(x []complex128) float64 {
+	var e float64
+	for _, v := range x {
+		e += real(v)*real(v) + imag(v)*imag(v)
+	}
+	return e
+}
+
+// checksum is benchlib.FloatChecksum of the scaled magnitudes, in index
+// order.
+func checksum(x []complex128) int64 {
+	mags := make([]float64, len(x))
+	for i, v := range x {
+		mags[i] = cmplx.Abs(v) * scale
+	}
+	return benchlib.FloatChecksum(mags)
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "fft", Category: benchlib.CategoryNumeric, Expected: expectedChecksum})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	n := flag.Int("n", defaultN, "signal length, a power of two")
+	flag.Parse()
+	if !isPowerOfTwo(*n) {
+		fmt.Fprintf(os.Stderr, "fft: --n must be a power of two, got %d\n", *n)
+		os.Exit(2)
+	}
+
+	t0 := time.Now()
+
+	// Startup phase: generate the signal and the twiddle table
+	signal := randomSignal(benchlib.NewRand(opts.Seed), *n)
+	w := twiddles(*n)
+	work := make([]complex128, *n)
+
+	startup := time.Since(t0)
+
+	// Compute benchmark. Each iteration transforms a fresh copy.
+	stats := benchlib.Run("fft", opts, startup, func() int64 {
+		copy(work, signal)
+		if err := fft(work, w); err != nil {
+			benchlib.Failf("%v", err)
+		}
+		return checksum(work)
+	})
+
+	// Validate result
+	got, want := energy(work), float64(*n)*energy(signal)
+	if math.Abs(got-want) > parsevalTolerance*want {
+		benchlib.Failf("Parseval check: spectrum energy %g, want %g", got, want)
+	}
+	if *n == defaultN {
+		benchlib.ValidateSeed(opts.Seed, stats.Result, expectedChecksum)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const tolerance = 1e-12
+
+func transform(t *testing.T, x []complex128) []complex128 {
+	t.Helper()
+	out := append([]complex128(nil), x...)
+	if err := fft(out, twiddles(len(out))); err != nil {
+		t.Fatalf("fft: %v", err)
+	}
+	return out
+}
+
+func TestFFTEightPoint(t *testing.T) {
+	// The DFT of 1..8 is 36 at k = 0 and −4 + 4i·cot(πk/8) elsewhere.
+	r2 := math.Sqrt2
+	want := []complex128{
+		36,
+		complex(-4, 4*(1+r2)),
+		complex(-4, 4),
+		complex(-4, 4*(r2-1)),
+		-4,
+		complex(-4, -4*(r2-1)),
+		complex(-4, -4),
+		complex(-4, -4*(1+r2)),
+	}
+	got := transform(t, []complex128{1, 2, 3, 4, 5, 6, 7, 8})
+	for k := range want {
+		if cmplx.Abs(got[k]-want[k]) > tolerance*36 {
+			t.Errorf("X[%d] = %v, want %v", k, got[k], want[k])
+		}
+	}
+}
+
+func TestFFTImpulseAndCosine(t *testing.T) {
+	// A unit impulse at 1 transforms to the twiddle factors themselves; a
+	// single cosine period puts N/2 into bins 1 and N−1.
+	imp := transform(t, []complex128{0, 1, 0, 0, 0, 0, 0, 0})
+	cos := make([]complex128, 8)
+	for j := range cos {
+		cos[j] = complex(math.Cos(2*math.Pi*float64(j)/8), 0)
+	}
+	cosX := transform(t, cos)
+	for k := 0; k < 8; k++ {
+		if want := cmplx.Exp(complex(0, -2*math.Pi*float64(k)/8)); cmplx.Abs(imp[k]-want) > tolerance {
+			t.Errorf("impulse X[%d] = %v, want %v", k, imp[k], want)
+		}
+		var want complex128
+		if k == 1 || k == 7 {
+			want = 4
+		}
+		if cmplx.Abs(cosX[k]-want) > tolerance*4 {
+			t.Errorf("cosine X[%d] = %v, want %v", k, cosX[k], want)
+		}
+	}
+}
+
+// dft is the O(N²) definition of the transform.
+func dft(x []complex128) []complex128 {
+	n := len(x)
+	out := make([]complex128, n)
+	for k := range out {
+		for j, v := range x {
+			out[k] += v * cmplx.Exp(complex(0, -2*math.Pi*float64(j*k%n)/float64(n)))
+		}
+	}
+	return out
+}
+
+func TestFFTMatchesDFT(t *testing.T) {
+	r := benchlib.NewRand(1)
+	for _, n := range []int{1, 2, 4, 16, 256} {
+		x := randomSignal(r, n)
+		got, want := transform(t, x), dft(x)
+		for k := range want {
+			if cmplx.Abs(got[k]-want[k]) > 1e-9 {
+				t.Errorf("n=%d: X[%d] = %v, DFT %v", n, k, got[k], want[k])
+			}
+		}
+	}
+}
+
+func TestFFTRejectsNonPowerOfTwo(t *testing.T) {
+	for _, n := range []int{0, 3, 6, 12, 1000} {
+		x := make([]complex128, n)
+		if n > 0 {
+			x[0] = 7
+		}
+		if err := fft(x, twiddles(n)); err == nil {
+			t.Errorf("fft of length %d succeeded", n)
+		}
+		if n > 0 && x[0] != 7 {
+			t.Errorf("fft of length %d modified its input", n)
+		}
+	}
+	if err := fft(make([]complex128, 8), twiddles(4)); err == nil {
+		t.Error("fft accepted a twiddle table for the wrong length")
+	}
+}
+
+func TestExpectedChecksum(t *testing.T) {
+	x := randomSignal(benchlib.NewRand(benchlib.DefaultSeed), defaultN)
+	X := transform(t, x)
+	if got := checksum(X); got != expectedChecksum {
+		t.Errorf("checksum = %d, want %d", got, expectedChecksum)
+	}
+	if got, want := energy(X), defaultN*energy(x); math.Abs(got-want) > parsevalTolerance*want {
+		t.Errorf("spectrum energy %g, want %g", got, want)
+	}
+}
@@ -4,6 +4,8 @@
  * Compute fib(35) using naive recursive algorithm.
  * Expected result: 9,227,465
  *
+ * --mode=iter swaps in an iterative loop as an arithmetic-only baseline.
+ *
  * This benchmark tests:
  * - Function call overhead
  * - Stack frame allocation
@@ -14,8 +16,12 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"os"
 	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
 )
 
 func fibonacci(n int) int {
@@ -25,7 +31,43 @@ func fibonacci(n int) int {
 	return fibonacci(n-1) + fibonacci(n-2)
 }
 
+// fibonacciIter computes fib(n) with a two-variable loop. It isolates the
+// integer arithmetic from the call overhead measured by fibonacci.
+func fibonacciIter(n int) int {
+	a, b := 0, 1
+	for i := 0; i < n; i++ {
+		a, b = b, a+b
+	}
+	return a
+}
+
+// Values accepted by --mode.
+const (
+	modeRecursive = "recursive"
+	modeIter      = "iter"
+)
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "fibonacci", Category: benchlib.CategoryRecursion, Expected: 9227465})
+}
+
 func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	mode := flag.String("mode", modeRecursive, "algorithm: recursive or iter")
+	flag.Parse()
+
+	var fib func(int) int
+	switch *mode {
+	case modeRecursive:
+		fib = fibonacci
+	case modeIter:
+		fib = fibonacciIter
+	default:
+		fmt.Fprintf(os.Stderr, "fibonacci: unknown --mode %q (want %s or %s)\n", *mode, modeRecursive, modeIter)
+		os.Exit(2)
+	}
+
 	// Measure startup time
 	t0 := time.Now()
 
@@ -41,24 +83,13 @@ func main() {
 		panic("warmup failed")
 	}
 
-	t1 := time.Now()
+	startup := time.Since(t0)
 
-	// Compute benchmark
-	result := fibonacci(n)
-
-	t2 := time.Now()
-
-	// Calculate times in microseconds
-	startupTimeUs := t1.Sub(t0).Microseconds()
-	computeTimeUs := t2.Sub(t1).Microseconds()
-
-	// Output standardized format
-	fmt.Printf("STARTUP_TIME_US: %d\n", startupTimeUs)
-	fmt.Printf("COMPUTE_TIME_US: %d\n", computeTimeUs)
-	fmt.Printf("RESULT: %d\n", result)
+	// Compute benchmark and output standardized format
+	stats := benchlib.Run("fibonacci", opts, startup, func() int64 {
+		return int64(fib(n))
+	})
 
 	// Validate result
-	if result != 9227465 {
-		panic(fmt.Sprintf("Expected fib(35) = 9227465, got %d", result))
-	}
+	benchlib.Validate(stats.Result, 9227465)
 }
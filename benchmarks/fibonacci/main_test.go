@@ -0,0 +1,21 @@
+package main
+
+import "testing"
+
+func TestFibonacciIterMatchesRecursive(t *testing.T) {
+	maxN := 40
+	if testing.Short() {
+		maxN = 25
+	}
+	for n := 0; n <= maxN; n++ {
+		if got, want := fibonacciIter(n), fibonacci(n); got != want {
+			t.Errorf("fibonacciIter(%d) = %d, fibonacci(%d) = %d", n, got, n, want)
+		}
+	}
+}
+
+func TestFibonacciExpectedResult(t *testing.T) {
+	if got := fibonacciIter(35); got != 9227465 {
+		t.Errorf("fibonacciIter(35) = %d, want 9227465", got)
+	}
+}
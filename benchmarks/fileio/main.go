@@ -0,0 +1,154 @@
+/*
+ * Sequential File I/O
+ *
+ * Write a 256 MiB file of benchlib.RandomBytes from
+ * benchlib.NewRand(benchlib.DefaultSeed) to a new temporary file in --dir
+ * (default the system temp directory) in 1 MiB writes, fsync and close it,
+ * then read it back in 1 MiB reads. RESULT is the CRC-32 (IEEE) of the
+ * bytes read back, which must also equal the CRC-32 of the data written,
+ * computed during startup. Each compute run writes and reads its own file
+ * and removes it before returning, whether or not the round trip
+ * succeeded; a run cut short by --timeout removes it on the way out too.
+ * Expected result: 88266045
+ *
+ * The fsync makes the write phase reach the device, but the read phase is
+ * normally served from the page cache the write just filled, so it
+ * measures the read path of the kernel rather than the disk. The two are
+ * timed as the write and read phases, which the text format prints as
+ * WRITE_TIME_US/_NS and READ_TIME_US/_NS; COMPUTE_TIME covers both, plus
+ * creating and removing the file.
+ *
+ * This benchmark tests:
+ * - Large sequential writes and fsync
+ * - Sequential reads through the page cache
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	fileSize    = 256 << 20
+	chunkSize   = 1 << 20
+	expectedCRC = 88266045
+)
+
+// writeFile writes data to f in chunkSize writes, then syncs and closes it.
+func writeFile(f *os.File, data []byte) error {
+	for off := 0; off < len(data); off += chunkSize {
+		if _, err := f.Write(data[off:min(off+chunkSize, len(data))]); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// write is the writeFile roundTrip calls; tests replace it to stall the
+// write phase.
+var write = writeFile
+
+// readCRC reads the file at path in chunkSize reads and returns the CRC-32
+// of its contents and its length.
+func readCRC(path string) (uint32, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+	buf := make([]byte, chunkSize)
+	var crc uint32
+	var n int64
+	for {
+		m, err := f.Read(buf)
+		crc = crc32.Update(crc, crc32.IEEETable, buf[:m])
+		n += int64(m)
+		if err == io.EOF {
+			return crc, n, nil
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+}
+
+// roundTrip writes data to a new file in dir, reads it back and returns
+// the CRC-32 of what was read, failing unless it is want and the length is
+// len(data). The writing and reading are timed as phases "write" and
+// "read". The file is removed before roundTrip returns, or by
+// benchlib.Failf if the process fails first, as on a --timeout.
+func roundTrip(dir string, data []byte, want uint32, phases *benchlib.Phases) (uint32, error) {
+	f, err := os.CreateTemp(dir, "fileio-*.bin")
+	if err != nil {
+		return 0, err
+	}
+	remove := func() { os.Remove(f.Name()) }
+	defer benchlib.OnFail(remove)()
+	defer remove()
+
+	phases.Measure("write", func() { err = write(f, data) })
+	if err != nil {
+		return 0, fmt.Errorf("writing %s: %v", f.Name(), err)
+	}
+
+	var crc uint32
+	var n int64
+	phases.Measure("read", func() { crc, n, err = readCRC(f.Name()) })
+	if err != nil {
+		return 0, fmt.Errorf("reading %s: %v", f.Name(), err)
+	}
+
+	switch {
+	case n != int64(len(data)):
+		return crc, fmt.Errorf("read back %d bytes, wrote %d", n, len(data))
+	case crc != want:
+		return crc, fmt.Errorf("read-back CRC %08x differs from written %08x", crc, want)
+	}
+	return crc, nil
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "fileio", Category: benchlib.CategoryIO, Expected: expectedCRC})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	dir := flag.String("dir", os.TempDir(), "directory to write the temporary file in")
+	flag.Parse()
+	opts.Phases = new(benchlib.Phases)
+
+	t0 := time.Now()
+
+	// Startup phase: generate the data and the CRC that reading it back
+	// must reproduce
+	data := benchlib.RandomBytes(benchlib.NewRand(opts.Seed), fileSize)
+	want := crc32.ChecksumIEEE(data)
+
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("fileio", opts, startup, func() int64 {
+		crc, err := roundTrip(*dir, data, want, opts.Phases)
+		if err != nil {
+			benchlib.Failf("%v", err)
+		}
+		return int64(crc)
+	})
+
+	// Validate result
+	benchlib.ValidateSeed(opts.Seed, stats.Result, expectedCRC)
+}
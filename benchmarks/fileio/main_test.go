@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+// assertEmpty fails unless dir has no entries.
+func assertEmpty(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("%s still holds %d entries, want the file removed", dir, len(entries))
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	// Neither a whole number of chunks nor less than one.
+	data := benchlib.RandomBytes(benchlib.NewRand(benchlib.DefaultSeed), 2*chunkSize+12345)
+	want := crc32.ChecksumIEEE(data)
+	dir := t.TempDir()
+	got, err := roundTrip(dir, data, want, new(benchlib.Phases))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("roundTrip CRC = %08x, want %08x", got, want)
+	}
+	assertEmpty(t, dir)
+}
+
+func TestRoundTripEmpty(t *testing.T) {
+	dir := t.TempDir()
+	if got, err := roundTrip(dir, nil, 0, new(benchlib.Phases)); err != nil || got != 0 {
+		t.Errorf("roundTrip(nil) = %08x, %v; want 0, nil", got, err)
+	}
+	assertEmpty(t, dir)
+}
+
+func TestRoundTripMismatchCleansUp(t *testing.T) {
+	data := []byte("sequential file I/O")
+	dir := t.TempDir()
+	_, err := roundTrip(dir, data, crc32.ChecksumIEEE(data)+1, new(benchlib.Phases))
+	if err == nil || !strings.Contains(err.Error(), "differs from written") {
+		t.Errorf("roundTrip with the wrong CRC = %v, want a mismatch error", err)
+	}
+	assertEmpty(t, dir)
+}
+
+func TestRoundTripMissingDir(t *testing.T) {
+	if _, err := roundTrip(t.TempDir()+"/missing", []byte("x"), 0, new(benchlib.Phases)); err == nil {
+		t.Error("roundTrip into a missing directory succeeded")
+	}
+}
+
+func TestRoundTripPhases(t *testing.T) {
+	data := benchlib.RandomBytes(benchlib.NewRand(benchlib.DefaultSeed), chunkSize)
+	phases := new(benchlib.Phases)
+	if _, err := roundTrip(t.TempDir(), data, crc32.ChecksumIEEE(data), phases); err != nil {
+		t.Fatal(err)
+	}
+	got := phases.Mean(1)
+	if len(got) != 2 || got[0].Name != "write" || got[1].Name != "read" {
+		t.Fatalf("phases = %v, want write then read", got)
+	}
+	for _, p := range got {
+		if p.Duration <= 0 {
+			t.Errorf("phase %s took %v", p.Name, p.Duration)
+		}
+	}
+}
+
+// timeoutHelperEnv makes the re-executed test binary in
+// TestTimeoutRemovesFile run a round trip into the directory it holds,
+// with a write that stalls past --timeout.
+const timeoutHelperEnv = "FILEIO_TIMEOUT_HELPER"
+
+func TestTimeoutRemovesFile(t *testing.T) {
+	if dir := os.Getenv(timeoutHelperEnv); dir != "" {
+		write = func(f *os.File, data []byte) error {
+			fmt.Println("writing")
+			time.Sleep(time.Minute)
+			return writeFile(f, data)
+		}
+		opts := benchlib.Options{Iterations: 1, Format: benchlib.FormatText, Timeout: 200 * time.Millisecond}
+		benchlib.Run("fileio", opts, 0, func() int64 {
+			crc, err := roundTrip(dir, []byte("sequential file I/O"), 0, new(benchlib.Phases))
+			if err != nil {
+				benchlib.Failf("%v", err)
+			}
+			return int64(crc)
+		})
+		os.Exit(0) // not reached
+	}
+
+	dir := t.TempDir()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestTimeoutRemovesFile$")
+	cmd.Env = append(os.Environ(), timeoutHelperEnv+"="+dir)
+	var stdout, stderr strings.Builder
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Fatalf("stalled run: err = %v, want exit status 1; stderr: %s", err, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "FAILURE: timeout") {
+		t.Errorf("stderr = %q, want a timeout failure", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "writing") {
+		t.Fatalf("the run timed out before creating its file; stdout: %s", stdout.String())
+	}
+	assertEmpty(t, dir)
+}
@@ -0,0 +1,138 @@
+/*
+ * Conway's Game of Life
+ *
+ * Evolve a 1024×1024 grid for 1000 generations under the B3/S23 rules. The
+ * grid is a torus: the left edge neighbors the right, the top the bottom,
+ * and each corner the other three. The initial pattern sets each cell,
+ * row by row, alive when benchlib.NewRand(benchlib.DefaultSeed).Uint64()
+ * is odd. RESULT is the number of live cells after the last generation.
+ * Expected result: 43887
+ *
+ * This benchmark tests:
+ * - Neighbor access across a large 2-D array
+ * - Double buffering with no allocation in the hot loop
+ * - Branchy per-cell rule evaluation
+ */
+
+package main
+
+import (
+	"flag"
+	"math/rand"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	size         = 1024
+	generations  = 1000
+	expectedLive = 43887
+)
+
+// grid is a square toroidal grid of cells, one byte per cell (0 dead,
+// 1 alive), stored row-major.
+type grid struct {
+	n     int
+	cells []uint8
+}
+
+// newGrid returns an all-dead n×n grid; n must be at least 2.
+func newGrid(n int) *grid {
+	return &grid{n: n, cells: make([]uint8, n*n)}
+}
+
+// randomGrid returns an n×n grid whose cells are alive when the next draw
+// from r is odd.
+func randomGrid(n int, r *rand.Rand) *grid {
+	g := newGrid(n)
+	for i := range g.cells {
+		g.cells[i] = uint8(r.Uint64() % 2)
+	}
+	return g
+}
+
+// next maps a 3×3 block sum (neighbors plus the cell itself) and the
+// cell's own state to its next state: born on 3 neighbors, survives on 2
+// or 3. Indexed as next[sum][self].
+var next = func() (t [10][2]uint8) {
+	for sum := range t {
+		t[sum][0] = b2u(sum == 3)
+		t[sum][1] = b2u(sum-1 == 2 || sum-1 == 3)
+	}
+	return t
+}()
+
+func b2u(b bool) uint8 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// step writes the generation after g into dst, which must be the same
+// size, using col as scratch space for n column sums. Row neighbors wrap
+// modulo n once per row; column neighbors wrap by treating the two edge
+// columns separately, so the inner loop has no modulo.
+func (g *grid) step(dst *grid, col []uint8) {
+	n, c := g.n, g.cells
+	for y := 0; y < n; y++ {
+		up := c[(y+n-1)%n*n:][:n]
+		row := c[y*n:][:n]
+		down := c[(y+1)%n*n:][:n]
+		for x := range col {
+			col[x] = up[x] + row[x] + down[x]
+		}
+		out := dst.cells[y*n:][:n]
+		out[0] = next[col[n-1]+col[0]+col[1]][row[0]]
+		for x := 1; x < n-1; x++ {
+			out[x] = next[col[x-1]+col[x]+col[x+1]][row[x]]
+		}
+		out[n-1] = next[col[n-2]+col[n-1]+col[0]][row[n-1]]
+	}
+}
+
+// live returns the number of live cells.
+func (g *grid) live() int64 {
+	var total int64
+	for _, c := range g.cells {
+		total += int64(c)
+	}
+	return total
+}
+
+// evolve runs gens generations starting from g, which it leaves unchanged,
+// and returns the final grid.
+func evolve(g *grid, gens int) *grid {
+	cur := newGrid(g.n)
+	copy(cur.cells, g.cells)
+	buf := newGrid(g.n)
+	col := make([]uint8, g.n)
+	for i := 0; i < gens; i++ {
+		cur.step(buf, col)
+		cur, buf = buf, cur
+	}
+	return cur
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "gameoflife", Category: benchlib.CategoryMemory, Expected: expectedLive})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	t0 := time.Now()
+	initial := randomGrid(size, benchlib.NewRand(opts.Seed))
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("gameoflife", opts, startup, func() int64 {
+		return evolve(initial, generations).live()
+	})
+
+	// Validate result
+	benchlib.ValidateSeed(opts.Seed, stats.Result, expectedLive)
+}
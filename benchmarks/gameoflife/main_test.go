@@ -0,0 +1,116 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+type cell struct{ x, y int }
+
+// gridOf returns an n×n grid with the given cells alive; coordinates are
+// taken modulo n, so patterns can be placed across an edge.
+func gridOf(n int, cells ...cell) *grid {
+	g := newGrid(n)
+	for _, c := range cells {
+		g.cells[(c.y+n)%n*n+(c.x+n)%n] = 1
+	}
+	return g
+}
+
+// liveCells returns the live cells of g in row-major order.
+func liveCells(g *grid) []cell {
+	var cells []cell
+	for i, c := range g.cells {
+		if c == 1 {
+			cells = append(cells, cell{i % g.n, i / g.n})
+		}
+	}
+	return cells
+}
+
+func checkCells(t *testing.T, label string, g *grid, want *grid) {
+	t.Helper()
+	if !slices.Equal(g.cells, want.cells) {
+		t.Errorf("%s: live cells %v, want %v", label, liveCells(g), liveCells(want))
+	}
+}
+
+func TestBlinkerAcrossCorner(t *testing.T) {
+	// A horizontal blinker centered on (0, 0) spans the left/right edge;
+	// its vertical phase spans the top/bottom edge.
+	const n = 6
+	horizontal := gridOf(n, cell{-1, 0}, cell{0, 0}, cell{1, 0})
+	vertical := gridOf(n, cell{0, -1}, cell{0, 0}, cell{0, 1})
+
+	checkCells(t, "generation 1", evolve(horizontal, 1), vertical)
+	checkCells(t, "generation 2", evolve(horizontal, 2), horizontal)
+}
+
+func TestGliderWrapsTorus(t *testing.T) {
+	// A south-east glider moves one cell diagonally every 4 generations.
+	// Placed at (6, 6) on an 8×8 torus it straddles the bottom-right
+	// corner and must come back to itself after 8·4 generations.
+	const n = 8
+	glider := func(dx, dy int) *grid {
+		return gridOf(n, cell{1 + dx, 0 + dy}, cell{2 + dx, 1 + dy},
+			cell{0 + dx, 2 + dy}, cell{1 + dx, 2 + dy}, cell{2 + dx, 2 + dy})
+	}
+	start := glider(6, 6)
+
+	// Hand-verified phase 2 of the glider after two generations.
+	checkCells(t, "generation 2", evolve(start, 2),
+		gridOf(n, cell{8, 7}, cell{6, 8}, cell{8, 8}, cell{7, 9}, cell{8, 9}))
+	for k := 1; k <= 2*n; k++ {
+		checkCells(t, "after glider period", evolve(start, 4*k), glider(6+k, 6+k))
+	}
+	if got := evolve(start, 4*n).live(); got != 5 {
+		t.Errorf("glider has %d cells after a full lap, want 5", got)
+	}
+}
+
+// naiveStep is a reference implementation that counts all eight neighbors
+// of every cell with explicit modular wraparound.
+func naiveStep(g *grid) *grid {
+	n := g.n
+	out := newGrid(n)
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			count := 0
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					if dx != 0 || dy != 0 {
+						count += int(g.cells[(y+dy+n)%n*n+(x+dx+n)%n])
+					}
+				}
+			}
+			if count == 3 || (count == 2 && g.cells[y*n+x] == 1) {
+				out.cells[y*n+x] = 1
+			}
+		}
+	}
+	return out
+}
+
+func TestStepMatchesNaive(t *testing.T) {
+	for _, n := range []int{2, 3, 17} {
+		want := randomGrid(n, benchlib.NewRand(benchlib.DefaultSeed))
+		for gen := 1; gen <= 20; gen++ {
+			want = naiveStep(want)
+			got := evolve(randomGrid(n, benchlib.NewRand(benchlib.DefaultSeed)), gen)
+			if !slices.Equal(got.cells, want.cells) {
+				t.Fatalf("n=%d generation %d: step disagrees with naive reference", n, gen)
+			}
+		}
+	}
+}
+
+func TestEvolveLeavesInputUnchanged(t *testing.T) {
+	g := randomGrid(16, benchlib.NewRand(benchlib.DefaultSeed))
+	before := slices.Clone(g.cells)
+	evolve(g, 5)
+	if !slices.Equal(g.cells, before) {
+		t.Error("evolve modified its input grid")
+	}
+}
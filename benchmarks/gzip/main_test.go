@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+func vocabulary() []string {
+	vocab := make([]string, vocabSize)
+	for i := range vocab {
+		vocab[i] = spell(i)
+	}
+	return vocab
+}
+
+// roundTrip compresses data and returns the stream and its decompression.
+func roundTrip(t *testing.T, data []byte) (stream, plain []byte) {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := compress(&buf, data, level); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err = io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes(), plain
+}
+
+func TestMakeText(t *testing.T) {
+	text := makeText(benchlib.NewRand(benchlib.DefaultSeed), vocabulary(), 1000)
+	if len(text) != 1000 {
+		t.Fatalf("len = %d, want 1000", len(text))
+	}
+	for i, line := range strings.Split(string(text), "\n")[:2] {
+		if n := len(strings.Fields(line)); n != wordsPerLine {
+			t.Errorf("line %d has %d words, want %d", i, n, wordsPerLine)
+		}
+	}
+}
+
+func TestSmallInput(t *testing.T) {
+	text := makeText(benchlib.NewRand(benchlib.DefaultSeed), vocabulary(), 1<<16)
+	stream, plain := roundTrip(t, text)
+	// The size compress/flate produces at level 6.
+	if len(stream) != 35128 {
+		t.Errorf("compressed size = %d, want 35128", len(stream))
+	}
+	if !bytes.Equal(plain, text) {
+		t.Error("decompressed text differs from the input")
+	}
+	if n, err := compressedSize(text, level); err != nil || n != int64(len(stream)) {
+		t.Errorf("compressedSize = %d, %v; want %d", n, err, len(stream))
+	}
+}
+
+func TestEmptyInput(t *testing.T) {
+	stream, plain := roundTrip(t, nil)
+	if len(plain) != 0 {
+		t.Errorf("decompressed %d bytes, want none", len(plain))
+	}
+	// A 10-byte header, an empty final block and the 8-byte trailer.
+	if len(stream) > 30 {
+		t.Errorf("empty input compressed to %d bytes", len(stream))
+	}
+}
+
+func TestBadLevel(t *testing.T) {
+	if _, err := compressedSize([]byte("x"), 42); err == nil {
+		t.Error("compressedSize at level 42 succeeded")
+	}
+}
@@ -0,0 +1,158 @@
+/*
+ * Tower of Hanoi
+ *
+ * Generate the full move sequence that transfers 28 disks from peg 0 to
+ * peg 2, by the classic recursion: move n−1 disks out of the way, move
+ * the largest, move the n−1 back on top of it. RESULT is the number of
+ * moves, 2^28 − 1.
+ * Expected result: 268435455
+ *
+ * By default each move is only counted. With --apply every move is also
+ * carried out on three peg stacks and checked: the source peg must be
+ * non-empty and its top disk smaller than the destination's. After the
+ * last move all disks must be on peg 2, largest at the bottom; any
+ * violation fails the benchmark.
+ *
+ * Unlike fibonacci, whose two calls shrink by different amounts, both
+ * recursive calls here take n−1, so the call tree is a perfect binary
+ * tree of depth 28.
+ *
+ * This benchmark tests:
+ * - Function call overhead in a perfectly balanced double recursion
+ * - With --apply, small-array stack updates on every call
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	disks = 28
+
+	expectedMoves = 1<<disks - 1
+)
+
+// countMoves returns the number of moves that transfer n disks from one
+// peg to another: 2^n − 1, generated one move at a time.
+func countMoves(n int) int64 {
+	if n == 0 {
+		return 0
+	}
+	return countMoves(n-1) + 1 + countMoves(n-1)
+}
+
+// pegs holds the three stacks of disks, numbered 1 (smallest) to n, each
+// listed bottom to top.
+type pegs [3][]uint8
+
+// newPegs returns pegs with n disks stacked on peg 0.
+func newPegs(n int) *pegs {
+	var p pegs
+	for i := range p {
+		p[i] = make([]uint8, 0, n)
+	}
+	for d := n; d >= 1; d-- {
+		p[0] = append(p[0], uint8(d))
+	}
+	return &p
+}
+
+// move moves the top disk of peg from to peg to, checking that the move is
+// legal.
+func (p *pegs) move(from, to int) error {
+	src := p[from]
+	if len(src) == 0 {
+		return fmt.Errorf("move from empty peg %d", from)
+	}
+	d := src[len(src)-1]
+	if dst := p[to]; len(dst) > 0 && dst[len(dst)-1] < d {
+		return fmt.Errorf("disk %d moved onto smaller disk %d on peg %d", d, dst[len(dst)-1], to)
+	}
+	p[from] = src[:len(src)-1]
+	p[to] = append(p[to], d)
+	return nil
+}
+
+// applyMoves carries out the moves that transfer the top n disks of peg
+// from to peg to via the third peg, and returns how many it made. It stops
+// at the first illegal move.
+func applyMoves(p *pegs, n, from, to, via int) (int64, error) {
+	if n == 0 {
+		return 0, nil
+	}
+	before, err := applyMoves(p, n-1, from, via, to)
+	if err != nil {
+		return 0, err
+	}
+	if err := p.move(from, to); err != nil {
+		return 0, err
+	}
+	after, err := applyMoves(p, n-1, via, to, from)
+	if err != nil {
+		return 0, err
+	}
+	return before + 1 + after, nil
+}
+
+// solved reports whether p holds all n disks on peg 2 in order.
+func (p *pegs) solved(n int) bool {
+	if len(p[0]) != 0 || len(p[1]) != 0 || len(p[2]) != n {
+		return false
+	}
+	for i, d := range p[2] {
+		if int(d) != n-i {
+			return false
+		}
+	}
+	return true
+}
+
+// solve applies the full solution for n disks to fresh pegs and returns
+// the move count, or an error if a move is illegal or the disks do not all
+// end up on peg 2.
+func solve(n int) (int64, error) {
+	p := newPegs(n)
+	moves, err := applyMoves(p, n, 0, 2, 1)
+	if err != nil {
+		return 0, err
+	}
+	if !p.solved(n) {
+		return 0, fmt.Errorf("final pegs %v, want all %d disks on peg 2", *p, n)
+	}
+	return moves, nil
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "hanoi", Category: benchlib.CategoryRecursion, Expected: expectedMoves})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	apply := flag.Bool("apply", false, "carry out every move on peg stacks and check it is legal")
+	flag.Parse()
+
+	t0 := time.Now()
+	startup := time.Since(t0)
+
+	// Compute benchmark. With --apply each run starts from fresh pegs.
+	stats := benchlib.Run("hanoi", opts, startup, func() int64 {
+		if !*apply {
+			return countMoves(disks)
+		}
+		moves, err := solve(disks)
+		if err != nil {
+			benchlib.Failf("%v", err)
+		}
+		return moves
+	})
+
+	// Validate result
+	benchlib.Validate(stats.Result, expectedMoves)
+}
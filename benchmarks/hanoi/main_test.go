@@ -0,0 +1,75 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMoveCounts(t *testing.T) {
+	for n, want := range map[int]int64{0: 0, 1: 1, 2: 3, 3: 7, 10: 1023, 16: 65535} {
+		if got := countMoves(n); got != want {
+			t.Errorf("countMoves(%d) = %d, want %d", n, got, want)
+		}
+		got, err := solve(n)
+		if err != nil {
+			t.Fatalf("solve(%d): %v", n, err)
+		}
+		if got != want {
+			t.Errorf("solve(%d) = %d moves, want %d", n, got, want)
+		}
+	}
+}
+
+func TestFinalPegs(t *testing.T) {
+	for _, n := range []int{1, 2, 5, 8} {
+		p := newPegs(n)
+		if _, err := applyMoves(p, n, 0, 2, 1); err != nil {
+			t.Fatal(err)
+		}
+		want := make([]uint8, n)
+		for i := range want {
+			want[i] = uint8(n - i)
+		}
+		if len(p[0]) != 0 || len(p[1]) != 0 || !slices.Equal(p[2], want) {
+			t.Errorf("%d disks: final pegs %v, want all on peg 2 as %v", n, *p, want)
+		}
+	}
+}
+
+func TestTwoDiskSequence(t *testing.T) {
+	p := newPegs(2)
+	// Disk 1 to the spare peg, disk 2 to the target, disk 1 on top of it.
+	for _, m := range [][2]int{{0, 1}, {0, 2}, {1, 2}} {
+		if err := p.move(m[0], m[1]); err != nil {
+			t.Fatalf("move %v: %v", m, err)
+		}
+	}
+	if !p.solved(2) {
+		t.Errorf("pegs %v not solved", *p)
+	}
+}
+
+func TestIllegalMoves(t *testing.T) {
+	p := newPegs(3)
+	if err := p.move(1, 2); err == nil {
+		t.Error("move from an empty peg succeeded")
+	}
+	if err := p.move(0, 1); err != nil {
+		t.Fatal(err)
+	}
+	// Disk 2 from peg 0 onto disk 1 on peg 1.
+	if err := p.move(0, 1); err == nil {
+		t.Errorf("larger disk moved onto a smaller one: %v", *p)
+	}
+	if p.solved(3) {
+		t.Error("a partial position reported solved")
+	}
+}
+
+func TestApplyMovesStopsAtIllegalMove(t *testing.T) {
+	// Disk 1 already sits on the target peg, so moving disk 3 there fails.
+	p := &pegs{{3, 2}, {}, {1}}
+	if moves, err := applyMoves(p, 2, 0, 2, 1); err == nil {
+		t.Errorf("applyMoves = %d moves, want an illegal-move error", moves)
+	}
+}
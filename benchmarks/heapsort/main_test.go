@@ -0,0 +1,120 @@
+package main
+
+import (
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+func TestHeapsort(t *testing.T) {
+	tests := map[string][]int{
+		"empty":           {},
+		"single":          {42},
+		"two ascending":   {1, 2},
+		"two descending":  {2, 1},
+		"all equal":       {9, 9, 9, 9, 9},
+		"duplicate heavy": {2, 2, 1, 2, 1, 1, 2, 0, 0, 2, 1, 2, 2, 2, 0, 1},
+		"sorted":          {1, 2, 3, 4, 5, 6, 7},
+		"reverse sorted":  {7, 6, 5, 4, 3, 2, 1},
+		"negative":        {3, -1, 0, -7, 2},
+	}
+	for name, in := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := slices.Clone(in)
+			heapsort(got)
+			want := slices.Clone(in)
+			sort.Ints(want)
+			if !slices.Equal(got, want) {
+				t.Errorf("heapsort(%v) = %v, want %v", in, got, want)
+			}
+		})
+	}
+}
+
+func TestHeapsortRandomSmall(t *testing.T) {
+	r := benchlib.NewRand(1)
+	for trial := 0; trial < 1000; trial++ {
+		// Small lengths and a small value range exercise odd and even
+		// heap sizes, a last node with one child, and many ties.
+		in := make([]int, r.Intn(40))
+		for i := range in {
+			in[i] = r.Intn(8)
+		}
+		got := slices.Clone(in)
+		heapsort(got)
+		want := slices.Clone(in)
+		sort.Ints(want)
+		if !slices.Equal(got, want) {
+			t.Fatalf("heapsort(%v) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+// isMaxHeap reports whether every node of heap is >= its children.
+func isMaxHeap(heap []int) bool {
+	for i := 1; i < len(heap); i++ {
+		if heap[(i-1)/2] < heap[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSiftDown(t *testing.T) {
+	tests := []struct {
+		name string
+		heap []int
+		i    int
+		want []int
+	}{
+		{"single node", []int{5}, 0, []int{5}},
+		{"already a heap", []int{9, 4, 7}, 0, []int{9, 4, 7}},
+		{"larger right child", []int{1, 4, 7}, 0, []int{7, 4, 1}},
+		{"equal children take the left", []int{1, 7, 7}, 0, []int{7, 1, 7}},
+		{"equal to the larger child stays", []int{7, 7, 3}, 0, []int{7, 7, 3}},
+		// The last internal node, index 1, has only a left child.
+		{"one child", []int{9, 2, 8, 5}, 1, []int{9, 5, 8, 2}},
+		{"to the bottom", []int{0, 9, 8, 7, 6, 5, 4}, 0, []int{9, 7, 8, 0, 6, 5, 4}},
+		{"subtree only", []int{0, 1, 8, 7, 6}, 1, []int{0, 7, 8, 1, 6}},
+	}
+	for _, tt := range tests {
+		got := slices.Clone(tt.heap)
+		siftDown(got, tt.i)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: siftDown(%v, %d) = %v, want %v", tt.name, tt.heap, tt.i, got, tt.want)
+		}
+	}
+}
+
+func TestHeapify(t *testing.T) {
+	in := benchlib.RandomInts(benchlib.NewRand(2), 1001)
+	for i := len(in)/2 - 1; i >= 0; i-- {
+		siftDown(in, i)
+	}
+	if !isMaxHeap(in) {
+		t.Error("bottom-up sift-down did not build a max-heap")
+	}
+}
+
+func TestHeapsortRandom(t *testing.T) {
+	in := benchlib.RandomInts(benchlib.NewRand(3), 5000)
+	want := slices.Clone(in)
+	sort.Ints(want)
+	heapsort(in)
+	if !slices.Equal(in, want) {
+		t.Error("heapsort disagrees with sort.Ints")
+	}
+}
+
+func TestExpectedChecksum(t *testing.T) {
+	xs := benchlib.RandomInts(benchlib.NewRand(benchlib.DefaultSeed), n)
+	heapsort(xs)
+	if !isSorted(xs) {
+		t.Fatal("output not sorted")
+	}
+	if got := benchlib.Checksum(xs); got != expectedChecksum {
+		t.Errorf("checksum = %d, want %d", got, expectedChecksum)
+	}
+}
@@ -0,0 +1,255 @@
+/*
+ * Huffman Coding
+ *
+ * Build a Huffman tree over an 8 MiB buffer and encode the buffer with it,
+ * packing codes MSB-first into bytes. Each input byte is the number of
+ * leading zero bits of one Uint64 from benchlib.NewRand(benchlib.DefaultSeed),
+ * a geometric distribution over 22 distinct symbols, so the codes have
+ * very different lengths.
+ *
+ * The tree is built by repeatedly merging the two lightest nodes. Ties in
+ * weight are broken by the smallest symbol a node contains, and the first
+ * node taken becomes the 0 branch, so every implementation builds the same
+ * tree and the same codes. RESULT is the length of the encoding in bits.
+ * Expected result: 16773822 (2.0 bits per byte)
+ *
+ * This benchmark tests:
+ * - Priority-queue tree construction with pointer nodes
+ * - Table lookup and bit packing in the encode loop
+ */
+
+package main
+
+import (
+	"flag"
+	"math/bits"
+	"math/rand"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	inputSize      = 8 << 20
+	expectedBitLen = 16773822
+)
+
+// node is a Huffman tree node. Leaves have no children.
+type node struct {
+	weight int64
+	// minSym is the smallest symbol in the subtree; it breaks weight ties.
+	minSym      int
+	left, right *node
+}
+
+func (n *node) leaf() bool { return n.left == nil }
+
+// lighter orders nodes by weight, then by smallest symbol. minSym values
+// of disjoint subtrees are distinct, so this is a strict total order.
+func lighter(a, b *node) bool {
+	if a.weight != b.weight {
+		return a.weight < b.weight
+	}
+	return a.minSym < b.minSym
+}
+
+// nodeHeap is a binary min-heap of nodes ordered by lighter.
+type nodeHeap []*node
+
+func (h *nodeHeap) push(n *node) {
+	*h = append(*h, n)
+	s := *h
+	i := len(s) - 1
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !lighter(s[i], s[parent]) {
+			break
+		}
+		s[parent], s[i] = s[i], s[parent]
+		i = parent
+	}
+}
+
+func (h *nodeHeap) pop() *node {
+	s := *h
+	top := s[0]
+	last := len(s) - 1
+	s[0] = s[last]
+	s = s[:last]
+	i := 0
+	for {
+		l := 2*i + 1
+		if l >= len(s) {
+			break
+		}
+		m := l
+		if r := l + 1; r < len(s) && lighter(s[r], s[l]) {
+			m = r
+		}
+		if !lighter(s[m], s[i]) {
+			break
+		}
+		s[i], s[m] = s[m], s[i]
+		i = m
+	}
+	*h = s
+	return top
+}
+
+// frequencies counts each byte value in data.
+func frequencies(data []byte) [256]int64 {
+	var freq [256]int64
+	for _, b := range data {
+		freq[b]++
+	}
+	return freq
+}
+
+// buildTree returns the Huffman tree for freq, or nil if every count is
+// zero. The lighter of each merged pair becomes the left (0) child.
+func buildTree(freq [256]int64) *node {
+	var h nodeHeap
+	for sym, w := range freq {
+		if w > 0 {
+			h.push(&node{weight: w, minSym: sym})
+		}
+	}
+	if len(h) == 0 {
+		return nil
+	}
+	for len(h) > 1 {
+		a := h.pop()
+		b := h.pop()
+		h.push(&node{weight: a.weight + b.weight, minSym: min(a.minSym, b.minSym), left: a, right: b})
+	}
+	return h.pop()
+}
+
+// code is a symbol's codeword: its low length bits, most significant first.
+type code struct {
+	bits   uint64
+	length int
+}
+
+// codeTable returns the codeword of every symbol in the tree. A tree that
+// is a single leaf gets the one-bit code 0, so it still encodes.
+func codeTable(root *node) [256]code {
+	var table [256]code
+	if root == nil {
+		return table
+	}
+	if root.leaf() {
+		table[root.minSym] = code{0, 1}
+		return table
+	}
+	var walk func(n *node, c code)
+	walk = func(n *node, c code) {
+		if n.leaf() {
+			table[n.minSym] = c
+			return
+		}
+		walk(n.left, code{c.bits << 1, c.length + 1})
+		walk(n.right, code{c.bits<<1 | 1, c.length + 1})
+	}
+	walk(root, code{})
+	return table
+}
+
+// bitWriter packs bits MSB-first into a byte slice.
+type bitWriter struct {
+	buf   []byte
+	acc   uint64 // pending bits, right-aligned; codes must be < 57 bits
+	nacc  int    // number of pending bits, < 8 between writes
+	total int64
+}
+
+func (w *bitWriter) write(c code) {
+	w.acc = w.acc<<c.length | c.bits
+	w.nacc += c.length
+	w.total += int64(c.length)
+	for w.nacc >= 8 {
+		w.nacc -= 8
+		w.buf = append(w.buf, byte(w.acc>>w.nacc))
+	}
+}
+
+// flush pads the final partial byte with zero bits.
+func (w *bitWriter) flush() {
+	if w.nacc > 0 {
+		w.buf = append(w.buf, byte(w.acc<<(8-w.nacc)))
+		w.nacc = 0
+	}
+}
+
+// encode Huffman-codes data and returns the tree, the packed bits and
+// their length in bits.
+func encode(data []byte) (*node, []byte, int64) {
+	root := buildTree(frequencies(data))
+	table := codeTable(root)
+	w := bitWriter{buf: make([]byte, 0, len(data))}
+	for _, b := range data {
+		w.write(table[b])
+	}
+	w.flush()
+	return root, w.buf, w.total
+}
+
+// decode reads bitLen bits of packed against root and returns the decoded
+// symbols.
+func decode(root *node, packed []byte, bitLen int64) []byte {
+	var out []byte
+	if root == nil {
+		return out
+	}
+	n := root
+	for i := int64(0); i < bitLen; i++ {
+		bit := packed[i/8] >> (7 - i%8) & 1
+		if !n.leaf() {
+			if bit == 0 {
+				n = n.left
+			} else {
+				n = n.right
+			}
+		}
+		if n.leaf() {
+			out = append(out, byte(n.minSym))
+			n = root
+		}
+	}
+	return out
+}
+
+// skewedBytes returns n bytes, each the leading-zero count of one r.Uint64.
+func skewedBytes(r *rand.Rand, n int) []byte {
+	data := make([]byte, n)
+	for i := range data {
+		data[i] = byte(bits.LeadingZeros64(r.Uint64()))
+	}
+	return data
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "huffman", Category: benchlib.CategoryText, Expected: expectedBitLen})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	t0 := time.Now()
+
+	// Startup phase: generate the input
+	data := skewedBytes(benchlib.NewRand(opts.Seed), inputSize)
+
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("huffman", opts, startup, func() int64 {
+		_, _, bitLen := encode(data)
+		return bitLen
+	})
+
+	// Validate result
+	benchlib.ValidateSeed(opts.Seed, stats.Result, expectedBitLen)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+// codeString renders c as a string of 0s and 1s.
+func codeString(c code) string {
+	return fmt.Sprintf("%0*b", c.length, c.bits)
+}
+
+func TestTieBreaking(t *testing.T) {
+	tests := []struct {
+		name  string
+		freq  map[byte]int64
+		codes map[byte]string
+	}{
+		{
+			// Equal weights merge in symbol order: (a b) then (c d).
+			name:  "four equal",
+			freq:  map[byte]int64{'d': 1, 'c': 1, 'b': 1, 'a': 1},
+			codes: map[byte]string{'a': "00", 'b': "01", 'c': "10", 'd': "11"},
+		},
+		{
+			// (a b) weighs 2, so the lone c is lighter and takes the 0.
+			name:  "three equal",
+			freq:  map[byte]int64{'c': 1, 'a': 1, 'b': 1},
+			codes: map[byte]string{'c': "0", 'a': "10", 'b': "11"},
+		},
+		{
+			// The merged (b c) ties with leaf a at weight 2; a's smaller
+			// symbol wins the 0 branch.
+			name:  "leaf ties internal node",
+			freq:  map[byte]int64{'a': 2, 'b': 1, 'c': 1},
+			codes: map[byte]string{'a': "0", 'b': "10", 'c': "11"},
+		},
+		{
+			// Here the internal node (a b) has the smaller symbol, so it
+			// goes left of the equally heavy leaf c.
+			name:  "internal node ties leaf",
+			freq:  map[byte]int64{'a': 1, 'b': 1, 'c': 2},
+			codes: map[byte]string{'a': "00", 'b': "01", 'c': "1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var freq [256]int64
+			for sym, w := range tt.freq {
+				freq[sym] = w
+			}
+			table := codeTable(buildTree(freq))
+			for sym, want := range tt.codes {
+				if got := codeString(table[sym]); got != want {
+					t.Errorf("code(%c) = %s, want %s", sym, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := map[string][]byte{
+		"empty":         {},
+		"single symbol": bytes.Repeat([]byte{'z'}, 13),
+		"text":          []byte("abracadabra, this is a test of huffman coding"),
+		"all bytes": func() []byte {
+			b := make([]byte, 256)
+			for i := range b {
+				b[i] = byte(i)
+			}
+			return b
+		}(),
+		"skewed": skewedBytes(benchlib.NewRand(benchlib.DefaultSeed), 10000),
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			root, packed, bitLen := encode(data)
+			if want := (bitLen + 7) / 8; int64(len(packed)) != want {
+				t.Errorf("packed %d bits into %d bytes, want %d", bitLen, len(packed), want)
+			}
+			if got := decode(root, packed, bitLen); !bytes.Equal(got, data) {
+				t.Errorf("decode(encode(x)) = %q, want %q", got, data)
+			}
+		})
+	}
+}
+
+func TestSingleSymbolUsesOneBit(t *testing.T) {
+	_, _, bitLen := encode([]byte("aaaa"))
+	if bitLen != 4 {
+		t.Errorf("bit length = %d, want 4", bitLen)
+	}
+}
+
+func TestExpectedBitLength(t *testing.T) {
+	_, _, got := encode(skewedBytes(benchlib.NewRand(benchlib.DefaultSeed), inputSize))
+	if got != expectedBitLen {
+		t.Errorf("bit length = %d, want %d", got, expectedBitLen)
+	}
+}
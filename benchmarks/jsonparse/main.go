@@ -0,0 +1,120 @@
+/*
+ * JSON Parsing
+ *
+ * Generate a deterministic JSON array of 20,000 records (benchlib.NewRand
+ * seeded with benchlib.DefaultSeed) during startup, then decode it 10 times
+ * with encoding/json. RESULT is the sum of every record's "score" field over
+ * all passes. Decoding is timed as the parse phase, reported as
+ * PARSE_TIME_US and PARSE_TIME_NS; the rest of COMPUTE_TIME is summing the
+ * scores.
+ * Expected result: 100595850490
+ *
+ * This benchmark tests:
+ * - Byte-level lexing and reflection-driven decoding
+ * - Allocation of strings and slices for decoded values
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	records        = 20000
+	passes         = 10
+	expectedResult = 100595850490
+)
+
+// record is one element of the generated document.
+type record struct {
+	ID     int      `json:"id"`
+	Name   string   `json:"name"`
+	Score  int      `json:"score"`
+	Active bool     `json:"active"`
+	Tags   []string `json:"tags"`
+	Ratio  float64  `json:"ratio"`
+}
+
+var tagPool = []string{"alpha", "beta", "gamma", "delta", "epsilon", "zeta", "eta", "theta"}
+
+// generateDocument builds n pseudo-random records and encodes them as a
+// JSON array.
+func generateDocument(r *rand.Rand, n int) ([]byte, error) {
+	rs := make([]record, n)
+	for i := range rs {
+		tags := make([]string, r.Intn(4))
+		for j := range tags {
+			tags[j] = tagPool[r.Intn(len(tagPool))]
+		}
+		rs[i] = record{
+			ID:     i,
+			Name:   fmt.Sprintf("user-%06d", r.Intn(1000000)),
+			Score:  r.Intn(1000000),
+			Active: r.Intn(2) == 1,
+			Tags:   tags,
+			Ratio:  r.Float64(),
+		}
+	}
+	doc, err := json.Marshal(rs)
+	if err != nil {
+		return nil, fmt.Errorf("generating document: %w", err)
+	}
+	return doc, nil
+}
+
+// scoreSum decodes doc, timing the decoding as phase "parse" of phases,
+// and returns the sum of its records' scores.
+func scoreSum(doc []byte, phases *benchlib.Phases) (int64, error) {
+	var rs []record
+	var err error
+	phases.Measure("parse", func() { err = json.Unmarshal(doc, &rs) })
+	if err != nil {
+		return 0, err
+	}
+	var sum int64
+	for _, rec := range rs {
+		sum += int64(rec.Score)
+	}
+	return sum, nil
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "jsonparse", Category: benchlib.CategoryText, Expected: expectedResult})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+	opts.Phases = new(benchlib.Phases)
+
+	t0 := time.Now()
+
+	// Startup phase: generate the document
+	doc := benchlib.Must(generateDocument(benchlib.NewRand(opts.Seed), records))
+
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("jsonparse", opts, startup, func() int64 {
+		var total int64
+		for i := 0; i < passes; i++ {
+			sum, err := scoreSum(doc, opts.Phases)
+			if err != nil {
+				benchlib.Failf("decoding document: %v", err)
+			}
+			total += sum
+		}
+		return total
+	})
+
+	// Validate result
+	benchlib.ValidateSeed(opts.Seed, stats.Result, expectedResult)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+func TestScoreSumFixedDocument(t *testing.T) {
+	doc := []byte(`[
+		{"id": 0, "name": "a", "score": 10, "active": true, "tags": ["x"], "ratio": 0.5},
+		{"id": 1, "name": "b", "score": 32, "active": false, "tags": [], "ratio": 0},
+		{"id": 2, "name": "c", "score": 0, "active": true, "tags": null, "ratio": 1.25, "extra": {"ignored": [1, 2]}}
+	]`)
+	phases := new(benchlib.Phases)
+	got, err := scoreSum(doc, phases)
+	if err != nil {
+		t.Fatalf("scoreSum: %v", err)
+	}
+	if got != 42 {
+		t.Errorf("scoreSum = %d, want 42", got)
+	}
+	if p := phases.Mean(1); len(p) != 1 || p[0].Name != "parse" || p[0].Duration <= 0 {
+		t.Errorf("phases = %v, want a parse phase", p)
+	}
+}
+
+func TestScoreSumRejectsMalformed(t *testing.T) {
+	if _, err := scoreSum([]byte(`[{"score": 1},`), new(benchlib.Phases)); err == nil {
+		t.Error("scoreSum accepted truncated JSON")
+	}
+}
+
+func TestGenerateDocumentDeterministic(t *testing.T) {
+	a, err := generateDocument(benchlib.NewRand(benchlib.DefaultSeed), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := generateDocument(benchlib.NewRand(benchlib.DefaultSeed), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(a) != string(b) {
+		t.Error("generateDocument output differs for the same seed")
+	}
+}
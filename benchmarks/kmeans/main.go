@@ -0,0 +1,157 @@
+/*
+ * K-Means Clustering
+ *
+ * Cluster N = 100,000 2D points into K = 10 clusters with Lloyd's
+ * algorithm for a fixed 100 iterations. The points come from
+ * benchlib.NewRand(benchlib.DefaultSeed): first K true centers with both
+ * coordinates benchlib.RandomFloat·100, then for each point a center
+ * (Uint64() % K) and an offset of (RandomFloat − 0.5)·20 in x, then y.
+ * The initial centroids are K distinct points chosen by Uint64() % N from
+ * the same generator, repeats skipped.
+ *
+ * Each iteration assigns every point to the nearest centroid by squared
+ * Euclidean distance (ties to the lower index), then moves each centroid
+ * to the mean of its points, summed in point order; an empty cluster keeps
+ * its centroid. RESULT is benchlib.FloatChecksum of the final centroids
+ * scaled by 1e6, as x0, y0, x1, y1, ...
+ * Expected result: 846981390 (converged after 66 iterations)
+ *
+ * This benchmark tests:
+ * - Floating-point distance computations in a tight inner loop
+ * - Alternating assignment and reduction phases over a large array
+ */
+
+package main
+
+import (
+	"flag"
+	"math/rand"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	points     = 100000
+	clusters   = 10
+	iterations = 100
+
+	// spread is the side of the square each cluster's points fall in.
+	spread = 20.0
+
+	// scale turns centroid coordinates into checksum units (micro-units).
+	scale = 1e6
+
+	expectedChecksum = 846981390
+)
+
+type point struct{ x, y float64 }
+
+// clusteredPoints returns n points scattered around k random centers, as
+// described in the header.
+func clusteredPoints(r *rand.Rand, n, k int) []point {
+	centers := make([]point, k)
+	for i := range centers {
+		centers[i] = point{benchlib.RandomFloat(r) * 100, benchlib.RandomFloat(r) * 100}
+	}
+	ps := make([]point, n)
+	for i := range ps {
+		c := centers[r.Uint64()%uint64(k)]
+		dx := (benchlib.RandomFloat(r) - 0.5) * spread
+		dy := (benchlib.RandomFloat(r) - 0.5) * spread
+		ps[i] = point{c.x + dx, c.y + dy}
+	}
+	return ps
+}
+
+// initialCentroids picks k distinct points of ps by drawing indices from r,
+// skipping indices already chosen. k must not exceed len(ps).
+func initialCentroids(r *rand.Rand, ps []point, k int) []point {
+	chosen := make(map[uint64]bool, k)
+	cs := make([]point, 0, k)
+	for len(cs) < k {
+		i := r.Uint64() % uint64(len(ps))
+		if !chosen[i] {
+			chosen[i] = true
+			cs = append(cs, ps[i])
+		}
+	}
+	return cs
+}
+
+// nearest returns the index of the centroid closest to p, preferring the
+// lower index on ties.
+func nearest(p point, cs []point) int {
+	best, bestDist := 0, 0.0
+	for j, c := range cs {
+		dx, dy := p.x-c.x, p.y-c.y
+		d := dx*dx + dy*dy
+		if j == 0 || d < bestDist {
+			best, bestDist = j, d
+		}
+	}
+	return best
+}
+
+// kmeans runs iters Lloyd iterations from start and returns the final
+// centroids and each point's cluster. start is not modified.
+func kmeans(ps, start []point, iters int) ([]point, []int) {
+	cs := append([]point(nil), start...)
+	assign := make([]int, len(ps))
+	sums := make([]point, len(cs))
+	counts := make([]int, len(cs))
+	for it := 0; it < iters; it++ {
+		clear(sums)
+		clear(counts)
+		for i, p := range ps {
+			j := nearest(p, cs)
+			assign[i] = j
+			sums[j].x += p.x
+			sums[j].y += p.y
+			counts[j]++
+		}
+		for j, n := range counts {
+			if n > 0 {
+				cs[j] = point{sums[j].x / float64(n), sums[j].y / float64(n)}
+			}
+		}
+	}
+	return cs, assign
+}
+
+// checksum is benchlib.FloatChecksum of the scaled centroid coordinates.
+func checksum(cs []point) int64 {
+	values := make([]float64, 0, 2*len(cs))
+	for _, c := range cs {
+		values = append(values, c.x*scale, c.y*scale)
+	}
+	return benchlib.FloatChecksum(values)
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "kmeans", Category: benchlib.CategoryNumeric, Expected: expectedChecksum})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	t0 := time.Now()
+
+	// Startup phase: generate the points and pick the initial centroids
+	r := benchlib.NewRand(opts.Seed)
+	ps := clusteredPoints(r, points, clusters)
+	start := initialCentroids(r, ps, clusters)
+
+	startup := time.Since(t0)
+
+	// Compute benchmark. Each iteration restarts from the same centroids.
+	stats := benchlib.Run("kmeans", opts, startup, func() int64 {
+		cs, _ := kmeans(ps, start, iterations)
+		return checksum(cs)
+	})
+
+	// Validate result
+	benchlib.ValidateSeed(opts.Seed, stats.Result, expectedChecksum)
+}
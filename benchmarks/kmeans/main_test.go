@@ -0,0 +1,102 @@
+package main
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+// threeBlobs is a tiny dataset with three well-separated clusters of four
+// points each, centered on (0, 0), (10, 10) and (20, 0).
+var threeBlobs = []point{
+	{-1, 0}, {1, 0}, {0, -1}, {0, 1},
+	{9, 10}, {11, 10}, {10, 9}, {10, 11},
+	{19, 0}, {21, 0}, {20, -1}, {20, 1},
+}
+
+func near(a, b point) bool {
+	return math.Abs(a.x-b.x) < 1e-12 && math.Abs(a.y-b.y) < 1e-12
+}
+
+func TestKMeansConvergesOnBlobs(t *testing.T) {
+	// Start from one point of each blob; the centroids should move to the
+	// blob centers and stay there.
+	start := []point{threeBlobs[0], threeBlobs[5], threeBlobs[11]}
+	want := []point{{0, 0}, {10, 10}, {20, 0}}
+	for _, iters := range []int{1, 2, 10} {
+		cs, assign := kmeans(threeBlobs, start, iters)
+		for j := range want {
+			if !near(cs[j], want[j]) {
+				t.Errorf("%d iterations: centroid %d = %v, want %v", iters, j, cs[j], want[j])
+			}
+		}
+		for i, a := range assign {
+			if a != i/4 {
+				t.Errorf("%d iterations: point %d in cluster %d, want %d", iters, i, a, i/4)
+			}
+		}
+	}
+	if start[0] != threeBlobs[0] {
+		t.Error("kmeans modified the initial centroids")
+	}
+}
+
+func TestKMeansReachesFixedPoint(t *testing.T) {
+	// All initial centroids in the same blob: the iteration should still
+	// settle on a fixed point.
+	start := []point{threeBlobs[0], threeBlobs[1], threeBlobs[2]}
+	prev, _ := kmeans(threeBlobs, start, 20)
+	next, _ := kmeans(threeBlobs, start, 21)
+	if !slices.Equal(prev, next) {
+		t.Errorf("centroids still moving after 20 iterations: %v then %v", prev, next)
+	}
+}
+
+func TestKMeansEmptyClusterKeepsCentroid(t *testing.T) {
+	far := point{1000, 1000}
+	cs, _ := kmeans(threeBlobs[:4], []point{{0, 0}, far}, 3)
+	if cs[1] != far {
+		t.Errorf("empty cluster centroid moved to %v", cs[1])
+	}
+	if !near(cs[0], point{0, 0}) {
+		t.Errorf("centroid 0 = %v, want (0, 0)", cs[0])
+	}
+}
+
+func TestNearestPrefersLowerIndexOnTies(t *testing.T) {
+	cs := []point{{5, 0}, {-1, 0}, {1, 0}}
+	if got := nearest(point{0, 0}, cs); got != 1 {
+		t.Errorf("nearest = %d, want 1", got)
+	}
+	if got := nearest(point{5, 0}, cs); got != 0 {
+		t.Errorf("nearest = %d, want 0", got)
+	}
+}
+
+func TestInitialCentroidsDistinct(t *testing.T) {
+	r := benchlib.NewRand(1)
+	ps := make([]point, 12)
+	for i := range ps {
+		ps[i] = point{float64(i), 0}
+	}
+	// Asking for every point forces the repeat-skipping path.
+	cs := initialCentroids(r, ps, len(ps))
+	seen := make(map[point]bool)
+	for _, c := range cs {
+		if seen[c] {
+			t.Fatalf("centroid %v chosen twice in %v", c, cs)
+		}
+		seen[c] = true
+	}
+}
+
+func TestExpectedChecksum(t *testing.T) {
+	r := benchlib.NewRand(benchlib.DefaultSeed)
+	ps := clusteredPoints(r, points, clusters)
+	cs, _ := kmeans(ps, initialCentroids(r, ps, clusters), iterations)
+	if got := checksum(cs); got != expectedChecksum {
+		t.Errorf("checksum = %d, want %d", got, expectedChecksum)
+	}
+}
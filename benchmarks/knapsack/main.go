@@ -0,0 +1,94 @@
+/*
+ * 0/1 Knapsack
+ *
+ * Choose a subset of 2,000 items, each usable at most once, maximizing
+ * total value subject to total weight <= capacity W = 100,000, about a
+ * tenth of the items' combined weight. Each item draws its weight and then
+ * its value as 1 + r.Uint64()%1000 from benchlib.NewRand seeded with
+ * benchlib.DefaultSeed.
+ * Expected result: 372121
+ *
+ * The DP table would be 2,000 × 100,001 entries; row i depends only on row
+ * i−1, so one rolling row of W+1 entries is kept. best[c] is the highest
+ * value reachable with capacity c using the items so far, and each item
+ * sweeps c from W down to its weight, so best[c−w] still holds the value
+ * without that item and the item is counted at most once.
+ *
+ * This benchmark tests:
+ * - Dynamic programming over a large array (800 KB, beyond L1/L2)
+ * - A backwards sweep reading at a data-dependent offset behind the write
+ * - Branch-light max of two candidates
+ */
+
+package main
+
+import (
+	"flag"
+	"math/rand"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	numItems = 2000
+	capacity = 100000
+
+	// maxDraw bounds item weights and values to [1, maxDraw].
+	maxDraw = 1000
+
+	expectedValue = 372121
+)
+
+// item is one candidate for the knapsack.
+type item struct {
+	weight int
+	value  int64
+}
+
+// randomItems returns n items whose weights and values are in [1, maxDraw].
+func randomItems(r *rand.Rand, n int) []item {
+	items := make([]item, n)
+	for i := range items {
+		items[i].weight = 1 + int(r.Uint64()%maxDraw)
+		items[i].value = 1 + int64(r.Uint64()%maxDraw)
+	}
+	return items
+}
+
+// knapsack returns the highest total value of a subset of items whose
+// total weight is at most capacity.
+func knapsack(items []item, capacity int) int64 {
+	best := make([]int64, capacity+1)
+	for _, it := range items {
+		for c := capacity; c >= it.weight; c-- {
+			best[c] = max(best[c], best[c-it.weight]+it.value)
+		}
+	}
+	return best[capacity]
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "knapsack", Category: benchlib.CategoryAlgorithm, Expected: expectedValue})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	t0 := time.Now()
+
+	// Startup phase: generate the items
+	items := randomItems(benchlib.NewRand(opts.Seed), numItems)
+
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("knapsack", opts, startup, func() int64 {
+		return knapsack(items, capacity)
+	})
+
+	// Validate result
+	benchlib.ValidateSeed(opts.Seed, stats.Result, expectedValue)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+func TestKnapsackByHand(t *testing.T) {
+	tests := []struct {
+		name     string
+		items    []item
+		capacity int
+		want     int64
+	}{
+		{"no items", nil, 10, 0},
+		{"zero capacity", []item{{1, 5}}, 0, 0},
+		{"single item fits", []item{{4, 7}}, 4, 7},
+		{"single item too heavy", []item{{5, 7}}, 4, 0},
+		// Unbounded knapsack would take the item five times.
+		{"item used once", []item{{1, 10}}, 5, 10},
+		// Weights 3+4 beat the greedy by value density (1+5, worth 8).
+		{"textbook", []item{{1, 1}, {3, 4}, {4, 5}, {5, 7}}, 7, 9},
+		{"everything fits", []item{{2, 3}, {3, 4}, {4, 5}}, 9, 12},
+	}
+	for _, tt := range tests {
+		if got := knapsack(tt.items, tt.capacity); got != tt.want {
+			t.Errorf("%s: knapsack = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+// bruteForce tries every subset of items.
+func bruteForce(items []item, capacity int) int64 {
+	var best int64
+	for mask := 0; mask < 1<<len(items); mask++ {
+		var w int
+		var v int64
+		for i, it := range items {
+			if mask&(1<<i) != 0 {
+				w += it.weight
+				v += it.value
+			}
+		}
+		if w <= capacity {
+			best = max(best, v)
+		}
+	}
+	return best
+}
+
+func TestKnapsackMatchesBruteForce(t *testing.T) {
+	r := benchlib.NewRand(1)
+	for trial := 0; trial < 20; trial++ {
+		items := randomItems(r, 12)
+		capacity := 1 + int(r.Uint64()%3000)
+		if got, want := knapsack(items, capacity), bruteForce(items, capacity); got != want {
+			t.Fatalf("trial %d: knapsack = %d, want %d (capacity %d, items %v)", trial, got, want, capacity, items)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+/*
+ * Levenshtein Edit Distance
+ *
+ * Compute the edit distance between two 5,000-character strings over the
+ * alphabet ACGT, generated during startup from benchlib.NewRand seeded with
+ * benchlib.DefaultSeed (the first string, then the second, one r.Uint64
+ * per character). Insertions, deletions and substitutions each cost 1.
+ * Expected result: 2596
+ *
+ * The full DP table would be 25M cells (200 MB of ints); only the previous
+ * row is needed to compute the next, so a single rolling row of 5,001
+ * entries is kept and updated in place.
+ *
+ * This benchmark tests:
+ * - Dynamic programming with a tight dependency chain
+ * - Sequential access to a small, cache-resident array
+ * - Byte comparisons and min-of-three branches
+ */
+
+package main
+
+import (
+	"flag"
+	"math/rand"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	length   = 5000
+	alphabet = "ACGT"
+
+	expectedDistance = 2596
+)
+
+// randomString returns n characters drawn uniformly from alphabet.
+func randomString(r *rand.Rand, n int) []byte {
+	s := make([]byte, n)
+	for i := range s {
+		s[i] = alphabet[r.Uint64()%uint64(len(alphabet))]
+	}
+	return s
+}
+
+// levenshtein returns the edit distance between a and b. row[j] holds the
+// distance between the current prefix of a and b[:j]; diag carries the
+// previous row's value at j−1 before it is overwritten.
+func levenshtein(a, b []byte) int {
+	row := make([]int, len(b)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		diag := row[0]
+		row[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			next := min(row[j]+1, row[j-1]+1, diag+cost)
+			diag = row[j]
+			row[j] = next
+		}
+	}
+	return row[len(b)]
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "levenshtein", Category: benchlib.CategoryText, Expected: expectedDistance})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	t0 := time.Now()
+
+	// Startup phase: generate both strings
+	r := benchlib.NewRand(opts.Seed)
+	a := randomString(r, length)
+	b := randomString(r, length)
+
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("levenshtein", opts, startup, func() int64 {
+		return int64(levenshtein(a, b))
+	})
+
+	// Validate result
+	benchlib.ValidateSeed(opts.Seed, stats.Result, expectedDistance)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+// levenshteinTable is the textbook full-table DP, kept as an independent
+// reference for the rolling-row implementation.
+func levenshteinTable(a, b []byte) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+func TestLevenshteinKnownPairs(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},            // insertions only
+		{"abc", "", 3},            // deletions only
+		{"abc", "abc", 0},         // identical
+		{"abc", "abd", 1},         // one substitution
+		{"abc", "xyz", 3},         // all substitutions
+		{"abc", "abxc", 1},        // one insertion
+		{"abcd", "acd", 1},        // one deletion
+		{"kitten", "sitting", 3},  // 2 substitutions + 1 insertion
+		{"flaw", "lawn", 2},       // deletion + insertion
+		{"GATTACA", "GCATGCU", 4}, // mixed
+		{"intention", "execution", 5},
+	}
+	for _, tt := range tests {
+		if got := levenshtein([]byte(tt.a), []byte(tt.b)); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := levenshtein([]byte(tt.b), []byte(tt.a)); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d (symmetry)", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestLevenshteinMatchesTable(t *testing.T) {
+	r := benchlib.NewRand(benchlib.DefaultSeed)
+	for _, n := range []int{1, 7, 64, 300} {
+		a, b := randomString(r, n), randomString(r, n+n/3)
+		if got, want := levenshtein(a, b), levenshteinTable(a, b); got != want {
+			t.Errorf("n=%d: rolling = %d, table = %d", n, got, want)
+		}
+	}
+}
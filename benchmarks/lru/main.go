@@ -0,0 +1,168 @@
+/*
+ * LRU Cache
+ *
+ * Drive a 65,536-entry least-recently-used cache (a hash map of keys to
+ * nodes of an intrusive doubly linked list, most recent at the front)
+ * through 4,000,000 operations on keys in [0, 262144). Each operation takes
+ * two draws from benchlib.NewRand(benchlib.DefaultSeed): the key, then the
+ * kind. One in four operations is a put storing the operation's index;
+ * the rest are gets, and a get that misses puts the key (cache-aside).
+ * RESULT is the number of get hits.
+ * Expected result: 743726
+ *
+ * This benchmark tests:
+ * - Hash-map lookup, insert and delete
+ * - Pointer updates on a doubly linked list
+ * - Eviction under a steady miss rate
+ */
+
+package main
+
+import (
+	"flag"
+	"math/rand"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	capacity     = 1 << 16
+	keySpace     = 1 << 18
+	operations   = 4000000
+	expectedHits = 743726
+)
+
+// node is one cache entry and its position in the recency list.
+type node struct {
+	key, value int64
+	prev, next *node
+}
+
+// lruCache is a fixed-capacity map that evicts the least recently used
+// entry when a put would exceed capacity. Both get and put count as a use.
+type lruCache struct {
+	capacity int
+	entries  map[int64]*node
+	// head is a sentinel: head.next is the most recently used entry and
+	// head.prev the least recently used.
+	head node
+}
+
+func newLRU(capacity int) *lruCache {
+	c := &lruCache{capacity: capacity, entries: make(map[int64]*node, capacity)}
+	c.head.prev = &c.head
+	c.head.next = &c.head
+	return c
+}
+
+func (c *lruCache) unlink(n *node) {
+	n.prev.next = n.next
+	n.next.prev = n.prev
+}
+
+func (c *lruCache) pushFront(n *node) {
+	n.prev = &c.head
+	n.next = c.head.next
+	c.head.next.prev = n
+	c.head.next = n
+}
+
+// get returns the value stored under key and marks it most recently used.
+func (c *lruCache) get(key int64) (int64, bool) {
+	n, ok := c.entries[key]
+	if !ok {
+		return 0, false
+	}
+	c.unlink(n)
+	c.pushFront(n)
+	return n.value, true
+}
+
+// put stores value under key, replacing any previous value, and marks it
+// most recently used. Inserting a new key into a full cache first evicts
+// the least recently used entry; overwriting never evicts.
+func (c *lruCache) put(key, value int64) {
+	if n, ok := c.entries[key]; ok {
+		n.value = value
+		c.unlink(n)
+		c.pushFront(n)
+		return
+	}
+	var n *node
+	if len(c.entries) == c.capacity {
+		// Reuse the evicted node rather than allocating a new one.
+		n = c.head.prev
+		c.unlink(n)
+		delete(c.entries, n.key)
+	} else {
+		n = new(node)
+	}
+	n.key, n.value = key, value
+	c.entries[key] = n
+	c.pushFront(n)
+}
+
+// len returns the number of cached entries.
+func (c *lruCache) len() int { return len(c.entries) }
+
+// op is one cache access.
+type op struct {
+	key int64
+	put bool
+}
+
+// accessSequence returns n operations on keys in [0, keys), drawing the
+// key then the kind from r for each.
+func accessSequence(r *rand.Rand, n, keys int) []op {
+	ops := make([]op, n)
+	for i := range ops {
+		ops[i].key = int64(r.Uint64() % uint64(keys))
+		ops[i].put = r.Uint64()%4 == 0
+	}
+	return ops
+}
+
+// replay runs ops against a fresh cache of the given capacity and returns
+// the number of get hits.
+func replay(ops []op, capacity int) int64 {
+	c := newLRU(capacity)
+	var hits int64
+	for i, o := range ops {
+		if o.put {
+			c.put(o.key, int64(i))
+			continue
+		}
+		if _, ok := c.get(o.key); ok {
+			hits++
+		} else {
+			c.put(o.key, int64(i))
+		}
+	}
+	return hits
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "lru", Category: benchlib.CategoryMemory, Expected: expectedHits})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	t0 := time.Now()
+
+	// Startup phase: generate the access sequence
+	ops := accessSequence(benchlib.NewRand(opts.Seed), operations, keySpace)
+
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("lru", opts, startup, func() int64 {
+		return replay(ops, capacity)
+	})
+
+	// Validate result
+	benchlib.ValidateSeed(opts.Seed, stats.Result, expectedHits)
+}
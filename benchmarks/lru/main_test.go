@@ -0,0 +1,134 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+// order returns the cached keys from most to least recently used, checking
+// that the backward links agree with the forward ones.
+func order(t *testing.T, c *lruCache) []int64 {
+	t.Helper()
+	var keys []int64
+	for n := c.head.next; n != &c.head; n = n.next {
+		if n.next.prev != n {
+			t.Fatalf("broken back link after key %d", n.key)
+		}
+		keys = append(keys, n.key)
+	}
+	if len(keys) != c.len() {
+		t.Fatalf("list holds %d entries, map %d", len(keys), c.len())
+	}
+	return keys
+}
+
+func checkOrder(t *testing.T, c *lruCache, want ...int64) {
+	t.Helper()
+	if got := order(t, c); !slices.Equal(got, want) {
+		t.Errorf("recency order = %v, want %v", got, want)
+	}
+}
+
+func TestEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newLRU(3)
+	c.put(1, 10)
+	c.put(2, 20)
+	c.put(3, 30)
+	checkOrder(t, c, 3, 2, 1)
+
+	c.put(4, 40) // evicts 1
+	checkOrder(t, c, 4, 3, 2)
+	if _, ok := c.get(1); ok {
+		t.Error("key 1 still cached after eviction")
+	}
+	c.put(5, 50) // evicts 2
+	checkOrder(t, c, 5, 4, 3)
+}
+
+func TestGetPromotes(t *testing.T) {
+	c := newLRU(3)
+	c.put(1, 10)
+	c.put(2, 20)
+	c.put(3, 30)
+	if v, ok := c.get(1); !ok || v != 10 {
+		t.Fatalf("get(1) = %d, %v; want 10, true", v, ok)
+	}
+	checkOrder(t, c, 1, 3, 2)
+
+	c.put(4, 40) // 2 is now the least recent
+	checkOrder(t, c, 4, 1, 3)
+
+	// A miss must not disturb the order.
+	if _, ok := c.get(2); ok {
+		t.Error("get(2) hit after eviction")
+	}
+	checkOrder(t, c, 4, 1, 3)
+}
+
+func TestPutOverwrites(t *testing.T) {
+	c := newLRU(2)
+	c.put(1, 10)
+	c.put(2, 20)
+	c.put(1, 11) // overwrite: no eviction, 1 becomes most recent
+	checkOrder(t, c, 1, 2)
+	if v, _ := c.get(1); v != 11 {
+		t.Errorf("get(1) = %d after overwrite, want 11", v)
+	}
+
+	c.put(3, 30) // evicts 2, not the freshly overwritten 1
+	checkOrder(t, c, 3, 1)
+	if v, ok := c.get(1); !ok || v != 11 {
+		t.Errorf("get(1) = %d, %v; want 11, true", v, ok)
+	}
+}
+
+func TestCapacityOne(t *testing.T) {
+	c := newLRU(1)
+	c.put(1, 10)
+	c.put(2, 20)
+	checkOrder(t, c, 2)
+	if v, ok := c.get(2); !ok || v != 20 {
+		t.Errorf("get(2) = %d, %v; want 20, true", v, ok)
+	}
+}
+
+// naiveReplay is a reference for replay that keeps the cache as a slice in
+// recency order, most recent first.
+func naiveReplay(ops []op, capacity int) int64 {
+	var keys []int64
+	touch := func(k int64) bool {
+		i := slices.Index(keys, k)
+		if i >= 0 {
+			keys = slices.Delete(keys, i, i+1)
+		} else if len(keys) == capacity {
+			keys = keys[:capacity-1]
+		}
+		keys = slices.Insert(keys, 0, k)
+		return i >= 0
+	}
+	var hits int64
+	for _, o := range ops {
+		if touch(o.key) && !o.put {
+			hits++
+		}
+	}
+	return hits
+}
+
+func TestReplayMatchesNaive(t *testing.T) {
+	for _, capacity := range []int{1, 7, 64} {
+		ops := accessSequence(benchlib.NewRand(benchlib.DefaultSeed), 20000, 4*capacity)
+		if got, want := replay(ops, capacity), naiveReplay(ops, capacity); got != want {
+			t.Errorf("capacity %d: replay = %d hits, naive = %d", capacity, got, want)
+		}
+	}
+}
+
+func TestExpectedHits(t *testing.T) {
+	ops := accessSequence(benchlib.NewRand(benchlib.DefaultSeed), operations, keySpace)
+	if got := replay(ops, capacity); got != expectedHits {
+		t.Errorf("hits = %d, want %d", got, expectedHits)
+	}
+}
@@ -0,0 +1,134 @@
+/*
+ * LU Decomposition
+ *
+ * Factorize a dense N×N matrix (N = 512) as P·A = L·U by Gaussian
+ * elimination with partial pivoting, in place and row-major. The entries
+ * of A are benchlib.RandomFloat − 0.5, drawn row by row from
+ * benchlib.NewRand(benchlib.DefaultSeed). The pivot of column k is the
+ * entry of largest magnitude on or below the diagonal, the topmost one on
+ * a tie, so the row order is fully determined. RESULT is
+ * benchlib.FloatChecksum of |U[i][i]| scaled by 1e6, in row order.
+ * Expected result: 2231575359
+ *
+ * This benchmark tests:
+ * - Floating-point multiply-subtract over a shrinking trailing submatrix
+ * - Row swaps and data-dependent pivot search
+ * - Row-major streaming through a 2 MiB matrix
+ */
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"math"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	size = 512
+
+	// scale turns diagonal magnitudes into checksum units.
+	scale = 1e6
+
+	expectedChecksum = 2231575359
+)
+
+var errSingular = errors.New("matrix is singular")
+
+// randomMatrix returns an n×n row-major matrix of RandomFloat − 0.5 values
+// from NewRand(seed).
+func randomMatrix(seed int64, n int) []float64 {
+	r := benchlib.NewRand(seed)
+	a := make([]float64, n*n)
+	for i := range a {
+		a[i] = benchlib.RandomFloat(r) - 0.5
+	}
+	return a
+}
+
+// factor overwrites the n×n row-major matrix a with its LU factorization:
+// U on and above the diagonal, and the multipliers of the unit lower
+// triangular L below it. perm, of length n, receives the row permutation:
+// row i of P·A is row perm[i] of the original a. factor returns
+// errSingular if a column has no nonzero pivot.
+func factor(a []float64, n int, perm []int) error {
+	for i := range perm {
+		perm[i] = i
+	}
+	for k := 0; k < n; k++ {
+		p := k
+		best := math.Abs(a[k*n+k])
+		for i := k + 1; i < n; i++ {
+			if v := math.Abs(a[i*n+k]); v > best {
+				p, best = i, v
+			}
+		}
+		if best == 0 {
+			return errSingular
+		}
+		if p != k {
+			rk, rp := a[k*n:(k+1)*n], a[p*n:(p+1)*n]
+			for j := range rk {
+				rk[j], rp[j] = rp[j], rk[j]
+			}
+			perm[k], perm[p] = perm[p], perm[k]
+		}
+
+		pivot := a[k*n+k]
+		rowK := a[k*n+k+1 : (k+1)*n]
+		for i := k + 1; i < n; i++ {
+			l := a[i*n+k] / pivot
+			a[i*n+k] = l
+			rowI := a[i*n+k+1 : (i+1)*n]
+			for j, u := range rowK {
+				// The conversion rounds the product, so compilers that
+				// fuse multiply-add cannot change the result.
+				rowI[j] -= float64(l * u)
+			}
+		}
+	}
+	return nil
+}
+
+// diagonalChecksum returns the checksum of the diagonal of U in lu.
+func diagonalChecksum(lu []float64, n int) int64 {
+	d := make([]float64, n)
+	for i := range d {
+		d[i] = math.Abs(lu[i*n+i]) * scale
+	}
+	return benchlib.FloatChecksum(d)
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "lu", Category: benchlib.CategoryNumeric, Expected: expectedChecksum})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	t0 := time.Now()
+
+	// Startup phase: generate the matrix; every run factorizes a fresh copy
+	orig := randomMatrix(opts.Seed, size)
+	a := make([]float64, len(orig))
+	perm := make([]int, size)
+
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("lu", opts, startup, func() int64 {
+		copy(a, orig)
+		if err := factor(a, size, perm); err != nil {
+			benchlib.Failf("%v", err)
+		}
+		return diagonalChecksum(a, size)
+	})
+
+	// Validate result
+	benchlib.ValidateSeed(opts.Seed, stats.Result, expectedChecksum)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+// reconstruct returns L·U from the packed factorization lu.
+func reconstruct(lu []float64, n int) []float64 {
+	out := make([]float64, n*n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			var sum float64
+			for k := 0; k <= min(i, j); k++ {
+				l := 1.0
+				if k < i {
+					l = lu[i*n+k]
+				}
+				sum += l * lu[k*n+j]
+			}
+			out[i*n+j] = sum
+		}
+	}
+	return out
+}
+
+func TestFactorReconstructs(t *testing.T) {
+	const n = 16
+	orig := randomMatrix(benchlib.DefaultSeed, n)
+	lu := slices.Clone(orig)
+	perm := make([]int, n)
+	if err := factor(lu, n, perm); err != nil {
+		t.Fatal(err)
+	}
+
+	got := reconstruct(lu, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if want := orig[perm[i]*n+j]; math.Abs(got[i*n+j]-want) > 1e-12 {
+				t.Fatalf("(L·U)[%d][%d] = %g, want (P·A)[%d][%d] = %g", i, j, got[i*n+j], i, j, want)
+			}
+		}
+	}
+	// Partial pivoting bounds every multiplier by 1.
+	for i := 0; i < n; i++ {
+		for k := 0; k < i; k++ {
+			if math.Abs(lu[i*n+k]) > 1 {
+				t.Errorf("|L[%d][%d]| = %g > 1", i, k, math.Abs(lu[i*n+k]))
+			}
+		}
+	}
+}
+
+func TestFactorByHand(t *testing.T) {
+	// Column 0 pivots on row 1 (|4| > |2|); in what remains, rows 0 and 2
+	// tie at magnitude 1.5 in column 1 and the topmost wins.
+	a := []float64{
+		2, 4, 1,
+		4, 5, 6,
+		-2, -4, 0,
+	}
+	perm := make([]int, 3)
+	if err := factor(a, 3, perm); err != nil {
+		t.Fatal(err)
+	}
+	want := []float64{
+		4, 5, 6,
+		0.5, 1.5, -2,
+		-0.5, -1, 1,
+	}
+	if !slices.Equal(a, want) || !slices.Equal(perm, []int{1, 0, 2}) {
+		t.Errorf("factor = %v, perm %v; want %v, perm [1 0 2]", a, perm, want)
+	}
+}
+
+func TestFactorSingular(t *testing.T) {
+	a := []float64{
+		1, 2,
+		2, 4,
+	}
+	if err := factor(a, 2, make([]int, 2)); !errors.Is(err, errSingular) {
+		t.Errorf("factor of a singular matrix = %v, want errSingular", err)
+	}
+}
+
+func TestDiagonalChecksum(t *testing.T) {
+	lu := []float64{
+		-1.5, 9,
+		9, 0.25,
+	}
+	// (1.5 + 0.25) × 1e6
+	if got := diagonalChecksum(lu, 2); got != 1750000 {
+		t.Errorf("diagonalChecksum = %d, want 1750000", got)
+	}
+}
@@ -0,0 +1,78 @@
+/*
+ * Mandelbrot Set
+ *
+ * Iterate z = z² + c over a 1000×1000 grid covering [-1.5, 0.5] × [-1, 1]
+ * (the Benchmarks Game mapping) with a cap of 256 iterations, counting the
+ * points whose orbit never leaves |z| ≤ 2.
+ * Expected result: 380263 points in the set
+ *
+ * This benchmark tests:
+ * - Floating-point multiply/add throughput
+ * - Data-dependent loop exits (branch prediction)
+ */
+
+package main
+
+import (
+	"flag"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	size          = 1000
+	maxIterations = 256
+	expectedCount = 380263
+)
+
+// inSet reports whether c = cr + ci·i stays bounded for maxIterations.
+func inSet(cr, ci float64) bool {
+	var zr, zi float64
+	for i := 0; i < maxIterations; i++ {
+		zr2, zi2 := zr*zr, zi*zi
+		if zr2+zi2 > 4 {
+			return false
+		}
+		zi = 2*zr*zi + ci
+		zr = zr2 - zi2 + cr
+	}
+	return true
+}
+
+// countInSet returns how many points of an n×n grid lie in the set. Pixel
+// (x, y) maps to c = (2x/n − 1.5) + (2y/n − 1)i.
+func countInSet(n int) int {
+	count := 0
+	for y := 0; y < n; y++ {
+		ci := 2*float64(y)/float64(n) - 1
+		for x := 0; x < n; x++ {
+			cr := 2*float64(x)/float64(n) - 1.5
+			if inSet(cr, ci) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "mandelbrot", Category: benchlib.CategoryNumeric, Expected: expectedCount})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	t0 := time.Now()
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("mandelbrot", opts, startup, func() int64 {
+		return int64(countInSet(size))
+	})
+
+	// Validate result
+	benchlib.Validate(stats.Result, expectedCount)
+}
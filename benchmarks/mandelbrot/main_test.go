@@ -0,0 +1,52 @@
+package main
+
+import "testing"
+
+// inSetComplex is a straightforward complex128 reference for inSet.
+func inSetComplex(c complex128) bool {
+	var z complex128
+	for i := 0; i < maxIterations; i++ {
+		if real(z)*real(z)+imag(z)*imag(z) > 4 {
+			return false
+		}
+		z = z*z + c
+	}
+	return true
+}
+
+func TestCountInSetSmallGridGolden(t *testing.T) {
+	if got := countInSet(16); got != 102 {
+		t.Errorf("countInSet(16) = %d, want 102", got)
+	}
+}
+
+func TestInSetMatchesComplexReference(t *testing.T) {
+	const n = 64
+	for y := 0; y < n; y++ {
+		ci := 2*float64(y)/n - 1
+		for x := 0; x < n; x++ {
+			cr := 2*float64(x)/n - 1.5
+			if got, want := inSet(cr, ci), inSetComplex(complex(cr, ci)); got != want {
+				t.Errorf("inSet(%v, %v) = %v, want %v", cr, ci, got, want)
+			}
+		}
+	}
+}
+
+func TestInSetKnownPoints(t *testing.T) {
+	tests := []struct {
+		cr, ci float64
+		want   bool
+	}{
+		{0, 0, true},    // fixed point
+		{-1, 0, true},   // period-2 cycle
+		{0.25, 0, true}, // cusp of the main cardioid
+		{1, 0, false},
+		{-1.5, 1, false},
+	}
+	for _, tt := range tests {
+		if got := inSet(tt.cr, tt.ci); got != tt.want {
+			t.Errorf("inSet(%v, %v) = %v, want %v", tt.cr, tt.ci, got, tt.want)
+		}
+	}
+}
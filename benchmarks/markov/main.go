@@ -0,0 +1,196 @@
+/*
+ * Markov Chain Text Generation
+ *
+ * Build an order-2 word Markov chain from a generated 500,000-word corpus,
+ * then walk it to generate 500,000 words. The vocabulary is 4,000 words,
+ * word i spelled as i+1 in bijective base 26 ("a" .. "z", "aa", ...), and
+ * corpus word j is word floor(4000·u³) for the j-th RandomFloat u of
+ * benchlib.NewRand(benchlib.DefaultSeed), a skewed distribution with a
+ * few very common words. Generation draws from a fresh generator seeded
+ * with benchlib.DefaultSeed in every compute run, so every run generates
+ * the same text.
+ *
+ * The chain maps each pair of consecutive words to the counts of the
+ * words that follow it. Go randomizes map iteration order, so nothing is
+ * ever chosen by walking a map: after counting, each pair's followers are
+ * sorted by word, and the pairs themselves are sorted (by first word, then
+ * second) for choosing where to start. A walk starts at pair
+ * keys[r.Uint64() mod len(keys)] and emits one follower per step, chosen by
+ * x = r.Uint64() mod total as the first follower whose running count
+ * exceeds x. A pair with no followers, which only the corpus's last pair
+ * can be, restarts the walk. This corpus has 389,358 distinct pairs. The
+ * chain is built inside every compute run.
+ * RESULT is ∑ (i+1)·id over the generated words, i counting from 0.
+ * Expected result: 124956285423850
+ *
+ * This benchmark tests:
+ * - String-keyed map insertion and lookup
+ * - Sorting many small string slices
+ * - Weighted random selection
+ */
+
+package main
+
+import (
+	"cmp"
+	"flag"
+	"math/rand"
+	"slices"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	vocabSize      = 4000
+	corpusLen      = 500000
+	generateLen    = 500000
+	expectedResult = 124956285423850
+)
+
+// prefix is the two words preceding a position in the text.
+type prefix [2]string
+
+// follower is a word that follows a prefix, with the running count of it
+// and every follower sorted before it.
+type follower struct {
+	word       string
+	cumulative int
+}
+
+// chain is an order-2 Markov chain. keys lists every prefix in sorted
+// order; next holds each one's followers sorted by word, possibly none.
+type chain struct {
+	keys []prefix
+	next map[prefix][]follower
+}
+
+// spell returns word i of the vocabulary: i+1 in bijective base 26.
+func spell(i int) string {
+	var buf []byte
+	for n := i + 1; n > 0; n = (n - 1) / 26 {
+		buf = append(buf, 'a'+byte((n-1)%26))
+	}
+	slices.Reverse(buf)
+	return string(buf)
+}
+
+// makeCorpus returns n words drawn from vocab with probability skewed
+// towards the start of it.
+func makeCorpus(r *rand.Rand, vocab []string, n int) []string {
+	words := make([]string, n)
+	for i := range words {
+		u := benchlib.RandomFloat(r)
+		words[i] = vocab[int(float64(len(vocab))*u*u*u)]
+	}
+	return words
+}
+
+func comparePrefix(a, b prefix) int {
+	if c := cmp.Compare(a[0], b[0]); c != 0 {
+		return c
+	}
+	return cmp.Compare(a[1], b[1])
+}
+
+// build counts the followers of every prefix in corpus and freezes the
+// counts into sorted slices, the only order selection ever depends on.
+func build(corpus []string) *chain {
+	counts := make(map[prefix]map[string]int)
+	for i := 0; i+2 <= len(corpus); i++ {
+		p := prefix{corpus[i], corpus[i+1]}
+		m := counts[p]
+		if m == nil {
+			m = make(map[string]int)
+			counts[p] = m
+		}
+		if i+2 < len(corpus) {
+			m[corpus[i+2]]++
+		}
+	}
+
+	c := &chain{keys: make([]prefix, 0, len(counts)), next: make(map[prefix][]follower, len(counts))}
+	for p, m := range counts {
+		c.keys = append(c.keys, p)
+		words := make([]string, 0, len(m))
+		for w := range m {
+			words = append(words, w)
+		}
+		slices.Sort(words)
+		fs := make([]follower, len(words))
+		total := 0
+		for i, w := range words {
+			total += m[w]
+			fs[i] = follower{word: w, cumulative: total}
+		}
+		c.next[p] = fs
+	}
+	slices.SortFunc(c.keys, comparePrefix)
+	return c
+}
+
+// generate walks c for n words using r.
+func (c *chain) generate(r *rand.Rand, n int) []string {
+	out := make([]string, 0, n)
+	p := c.keys[r.Uint64()%uint64(len(c.keys))]
+	for len(out) < n {
+		fs := c.next[p]
+		if len(fs) == 0 {
+			p = c.keys[r.Uint64()%uint64(len(c.keys))]
+			continue
+		}
+		x := int(r.Uint64() % uint64(fs[len(fs)-1].cumulative))
+		i, _ := slices.BinarySearchFunc(fs, x, func(f follower, x int) int {
+			// The first follower whose running count exceeds x.
+			if f.cumulative <= x {
+				return -1
+			}
+			return 1
+		})
+		w := fs[i].word
+		out = append(out, w)
+		p = prefix{p[1], w}
+	}
+	return out
+}
+
+// checksum returns ∑ (i+1)·id(words[i]).
+func checksum(words []string, ids map[string]int) int64 {
+	var sum int64
+	for i, w := range words {
+		sum += int64(i+1) * int64(ids[w])
+	}
+	return sum
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "markov", Category: benchlib.CategoryText, Expected: expectedResult})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	t0 := time.Now()
+
+	// Startup phase: spell the vocabulary and draw the corpus
+	vocab := make([]string, vocabSize)
+	ids := make(map[string]int, vocabSize)
+	for i := range vocab {
+		vocab[i] = spell(i)
+		ids[vocab[i]] = i
+	}
+	corpus := makeCorpus(benchlib.NewRand(opts.Seed), vocab, corpusLen)
+
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("markov", opts, startup, func() int64 {
+		c := build(corpus)
+		return checksum(c.generate(benchlib.NewRand(opts.Seed), generateLen), ids)
+	})
+
+	// Validate result
+	benchlib.ValidateSeed(opts.Seed, stats.Result, expectedResult)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+func TestSpell(t *testing.T) {
+	for i, want := range map[int]string{0: "a", 25: "z", 26: "aa", 51: "az", 52: "ba", 701: "zz", 702: "aaa"} {
+		if got := spell(i); got != want {
+			t.Errorf("spell(%d) = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	c := build(strings.Fields("the cat sat on the mat the cat ran on the rug"))
+	want := []prefix{
+		{"cat", "ran"}, {"cat", "sat"}, {"mat", "the"}, {"on", "the"}, {"ran", "on"},
+		{"sat", "on"}, {"the", "cat"}, {"the", "mat"}, {"the", "rug"},
+	}
+	if !slices.Equal(c.keys, want) {
+		t.Errorf("keys = %q, want %q", c.keys, want)
+	}
+	if got, want := c.next[prefix{"on", "the"}], []follower{{"mat", 1}, {"rug", 2}}; !slices.Equal(got, want) {
+		t.Errorf("followers of (on, the) = %v, want %v", got, want)
+	}
+	if got := c.next[prefix{"the", "rug"}]; len(got) != 0 {
+		t.Errorf("followers of the last pair = %v, want none", got)
+	}
+}
+
+func TestGenerateTinyCorpus(t *testing.T) {
+	corpus := strings.Fields("the cat sat on the mat the cat ran on the rug")
+	want := strings.Fields("on the mat the cat sat on the mat the cat ran")
+	// Build anew each time, so a dependence on map iteration order would
+	// show up as a mismatch.
+	for range 20 {
+		got := build(corpus).generate(benchlib.NewRand(benchlib.DefaultSeed), len(want))
+		if !slices.Equal(got, want) {
+			t.Fatalf("generate = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestGenerateRestartsAtDeadEnd(t *testing.T) {
+	// (b, c) has no followers, and (a, b) leads only to it.
+	c := build([]string{"a", "b", "c"})
+	if got, want := c.generate(benchlib.NewRand(benchlib.DefaultSeed), 5), []string{"c", "c", "c", "c", "c"}; !slices.Equal(got, want) {
+		t.Errorf("generate = %q, want %q", got, want)
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	ids := map[string]int{"a": 0, "b": 1, "c": 2}
+	// 1·1 + 2·2 + 3·0 + 4·1
+	if got := checksum([]string{"b", "c", "a", "b"}, ids); got != 9 {
+		t.Errorf("checksum = %d, want 9", got)
+	}
+}
@@ -1,16 +1,30 @@
-// Matrix Multiply Benchmark (128×128)
+// Matrix Multiply Benchmark (128×128, --n for other sizes)
 // Naive O(n³) implementation (no SIMD)
 // Expected: Baseline for comparison - trueno should be ~7× faster
 
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"os"
+	"runtime"
+	"sync"
 	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
 )
 
+// size is the default matrix dimension; --n overrides it.
 const size = 128
 
+// errChecksumOverflow reports a product whose checksum does not fit in an
+// int64. The default sequential inputs have entries below 100, so every
+// element of the product is below 9702·n and the total stays in range up
+// to n ≈ 97,000; larger values or sizes can exceed it.
+var errChecksumOverflow = errors.New("checksum overflow")
+
 // Naive matrix multiplication O(n³)
 func matmul(a, b [][]float64) [][]float64 {
 	n := len(a)
@@ -31,41 +45,194 @@ func matmul(a, b [][]float64) [][]float64 {
 	return c
 }
 
-func main() {
-	t0 := time.Now()
+// matmulTiled computes the same product as matmul, blocking the i/j/k loops
+// into blockSize×blockSize tiles so each tile of b stays in cache while it
+// is reused. Within a tile the loops run i-k-j, walking rows of b and c
+// contiguously.
+//
+// For every c[i][j] the k terms are still accumulated in ascending order
+// starting from zero, exactly as in matmul, so the two products are
+// bit-identical.
+func matmulTiled(a, b [][]float64, blockSize int) [][]float64 {
+	n := len(a)
+	c := make([][]float64, n)
+	for i := range c {
+		c[i] = make([]float64, n)
+	}
+
+	for i0 := 0; i0 < n; i0 += blockSize {
+		iMax := min(i0+blockSize, n)
+		for j0 := 0; j0 < n; j0 += blockSize {
+			jMax := min(j0+blockSize, n)
+			for k0 := 0; k0 < n; k0 += blockSize {
+				kMax := min(k0+blockSize, n)
+				for i := i0; i < iMax; i++ {
+					ci := c[i]
+					for k := k0; k < kMax; k++ {
+						aik := a[i][k]
+						bk := b[k]
+						for j := j0; j < jMax; j++ {
+							ci[j] += aik * bk[j]
+						}
+					}
+				}
+			}
+		}
+	}
+	return c
+}
+
+// matmulParallel computes the same product as matmul with the rows of c
+// split across workers goroutines. Worker w owns a contiguous band of rows;
+// when len(a) is not divisible by workers the first len(a)%workers bands
+// get one extra row, so every row is computed exactly once. Each element is
+// accumulated in the same order as matmul, so the result is bit-identical.
+func matmulParallel(a, b [][]float64, workers int) [][]float64 {
+	n := len(a)
+	c := make([][]float64, n)
+	for i := range c {
+		c[i] = make([]float64, n)
+	}
+
+	var wg sync.WaitGroup
+	start := 0
+	for w := 0; w < workers; w++ {
+		rows := n / workers
+		if w < n%workers {
+			rows++
+		}
+		lo, hi := start, start+rows
+		start = hi
+		if lo == hi {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := lo; i < hi; i++ {
+				for j := 0; j < n; j++ {
+					sum := 0.0
+					for k := 0; k < n; k++ {
+						sum += a[i][k] * b[k][j]
+					}
+					c[i][j] = sum
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return c
+}
 
-	// Initialize matrices with sequential values
-	a := make([][]float64, size)
-	b := make([][]float64, size)
-	for i := 0; i < size; i++ {
-		a[i] = make([]float64, size)
-		b[i] = make([]float64, size)
-		for j := 0; j < size; j++ {
-			idx := i*size + j
+// newInputs builds the two n×n input matrices with sequential values.
+func newInputs(n int) (a, b [][]float64) {
+	a = make([][]float64, n)
+	b = make([][]float64, n)
+	for i := 0; i < n; i++ {
+		a[i] = make([]float64, n)
+		b[i] = make([]float64, n)
+		for j := 0; j < n; j++ {
+			idx := i*n + j
 			a[i][j] = float64(idx % 100)
 			b[i][j] = float64((idx * 2) % 100)
 		}
 	}
+	return a, b
+}
 
-	t1 := time.Now()
-	startupTimeUs := t1.Sub(t0).Microseconds()
+// newRandomInputs builds two n×n matrices from benchlib.RandomFloats of
+// NewRand(seed), filling a row by row and then b.
+func newRandomInputs(seed int64, n int) (a, b [][]float64) {
+	values := benchlib.RandomFloats(benchlib.NewRand(seed), 2*n*n)
+	a = make([][]float64, n)
+	b = make([][]float64, n)
+	for i := 0; i < n; i++ {
+		a[i] = values[i*n : (i+1)*n]
+		b[i] = values[(n+i)*n : (n+i+1)*n]
+	}
+	return a, b
+}
 
-	// Perform matrix multiplication
-	c := matmul(a, b)
+// checksum reduces c, in row-major order, with benchlib.FloatChecksum so
+// ports can reproduce the exact integer.
+func checksum(c [][]float64) int64 {
+	flat := make([]float64, 0, len(c)*len(c))
+	for _, row := range c {
+		flat = append(flat, row...)
+	}
+	return benchlib.FloatChecksum(flat)
+}
 
-	t2 := time.Now()
-	computeTimeUs := t2.Sub(t1).Microseconds()
+// checkedChecksum is checksum, but returns errChecksumOverflow instead of
+// benchlib.InvalidFloatChecksum when the total is outside the int64 range
+// or rounds to -2^63, the value InvalidFloatChecksum reserves.
+func checkedChecksum(c [][]float64) (int64, error) {
+	sum := checksum(c)
+	if sum == benchlib.InvalidFloatChecksum {
+		return 0, errChecksumOverflow
+	}
+	return sum, nil
+}
 
-	// Verify result (checksum)
-	sum := 0.0
-	for i := 0; i < size; i++ {
-		for j := 0; j < size; j++ {
-			sum += c[i][j]
-		}
+func init() {
+	benchlib.Register(benchlib.Info{Name: "matrix-multiply", Category: benchlib.CategoryNumeric, Expected: 5078978272})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	tiled := flag.Bool("tiled", false, "use the cache-tiled multiply")
+	blockSize := flag.Int("block", 32, "tile size for --tiled")
+	random := flag.Bool("random", false, "fill inputs from the RNG seeded by --seed instead of sequential values")
+	parallel := flag.Bool("parallel", false, "split output rows across --workers goroutines")
+	workers := flag.Int("workers", runtime.NumCPU(), "worker goroutines for --parallel")
+	n := flag.Int("n", size, "matrix dimension")
+	flag.Parse()
+	// Only --parallel runs the compute phase across goroutines.
+	opts.Concurrent = *parallel
+	if *n < 1 {
+		fmt.Fprintf(os.Stderr, "matrix-multiply: --n must be >= 1, got %d\n", *n)
+		os.Exit(2)
+	}
+	if *blockSize < 1 {
+		fmt.Fprintf(os.Stderr, "matrix-multiply: --block must be >= 1, got %d\n", *blockSize)
+		os.Exit(2)
+	}
+	if *workers < 1 {
+		fmt.Fprintf(os.Stderr, "matrix-multiply: --workers must be >= 1, got %d\n", *workers)
+		os.Exit(2)
+	}
+	if *tiled && *parallel {
+		fmt.Fprintf(os.Stderr, "matrix-multiply: --tiled and --parallel are mutually exclusive\n")
+		os.Exit(2)
 	}
 
-	// Standardized output format
-	fmt.Printf("STARTUP_TIME_US: %d\n", startupTimeUs)
-	fmt.Printf("COMPUTE_TIME_US: %d\n", computeTimeUs)
-	fmt.Printf("RESULT: %d\n", int64(sum))
+	t0 := time.Now()
+
+	// Initialize matrices with sequential (default) or seeded random values
+	a, b := newInputs(*n)
+	if *random {
+		a, b = newRandomInputs(opts.Seed, *n)
+	}
+
+	startup := time.Since(t0)
+
+	// Perform matrix multiplication, checksum the product, and report. An
+	// overflowing checksum fails before any RESULT is printed.
+	benchlib.Run("matrix-multiply", opts, startup, func() int64 {
+		var c [][]float64
+		switch {
+		case *tiled:
+			c = matmulTiled(a, b, *blockSize)
+		case *parallel:
+			c = matmulParallel(a, b, *workers)
+		default:
+			c = matmul(a, b)
+		}
+		sum, err := checkedChecksum(c)
+		if err != nil {
+			benchlib.Failf("%v (n=%d)", err, *n)
+		}
+		return sum
+	})
 }
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+func randomMatrix(r *rand.Rand, n int) [][]float64 {
+	m := make([][]float64, n)
+	for i := range m {
+		m[i] = make([]float64, n)
+		for j := range m[i] {
+			m[i][j] = r.Float64()*2 - 1
+		}
+	}
+	return m
+}
+
+func TestMatmulTiledMatchesNaive(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 7, 32, 33, 100} {
+		for _, block := range []int{1, 8, 16, 64} {
+			a, b := randomMatrix(r, n), randomMatrix(r, n)
+			want := matmul(a, b)
+			got := matmulTiled(a, b, block)
+			for i := range want {
+				for j := range want[i] {
+					if diff := math.Abs(got[i][j] - want[i][j]); diff > 1e-12 {
+						t.Fatalf("n=%d block=%d: c[%d][%d] = %v, want %v", n, block, i, j, got[i][j], want[i][j])
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestMatmulTiledChecksumIdentical(t *testing.T) {
+	a, b := newInputs(size)
+	want := checksum(matmul(a, b))
+	for _, block := range []int{16, 32, 48, size} {
+		if got := checksum(matmulTiled(a, b, block)); got != want {
+			t.Errorf("block=%d: checksum = %d, want %d", block, got, want)
+		}
+	}
+}
+
+func TestNewRandomInputsReproducible(t *testing.T) {
+	a1, b1 := newRandomInputs(benchlib.DefaultSeed, 16)
+	a2, b2 := newRandomInputs(benchlib.DefaultSeed, 16)
+	if checksum(matmul(a1, b1)) != checksum(matmul(a2, b2)) {
+		t.Error("random inputs differ between calls")
+	}
+}
+
+func TestMatmulParallelMatchesSerial(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for _, n := range []int{1, 5, 17, 64, 100} {
+		a, b := randomMatrix(r, n), randomMatrix(r, n)
+		want := matmul(a, b)
+		// Worker counts that divide n, do not divide it, and exceed it.
+		for _, workers := range []int{1, 2, 3, 4, 7, n, n + 3} {
+			got := matmulParallel(a, b, workers)
+			for i := range want {
+				for j := range want[i] {
+					if got[i][j] != want[i][j] {
+						t.Fatalf("n=%d workers=%d: c[%d][%d] = %v, want %v", n, workers, i, j, got[i][j], want[i][j])
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestMatmulParallelChecksumIdentical(t *testing.T) {
+	a, b := newInputs(size)
+	want := checksum(matmul(a, b))
+	for _, workers := range []int{3, 8, 13} {
+		if got := checksum(matmulParallel(a, b, workers)); got != want {
+			t.Errorf("workers=%d: checksum = %d, want %d", workers, got, want)
+		}
+	}
+}
+
+func TestCheckedChecksumDefaultSize(t *testing.T) {
+	a, b := newInputs(size)
+	got, err := checkedChecksum(matmul(a, b))
+	if err != nil {
+		t.Fatalf("checkedChecksum at the default size: %v", err)
+	}
+	if want := checksum(matmul(a, b)); got != want {
+		t.Errorf("checkedChecksum = %d, checksum = %d", got, want)
+	}
+}
+
+func TestCheckedChecksumOverflow(t *testing.T) {
+	// Every element of the product of two 4×4 matrices of ±1e9 is ±4e18,
+	// so the 16 elements total ±6.4e19, past the int64 range (≈ ±9.2e18).
+	filled := func(v float64) [][]float64 {
+		m := make([][]float64, 4)
+		for i := range m {
+			m[i] = []float64{v, v, v, v}
+		}
+		return m
+	}
+	pos, neg := filled(1e9), filled(-1e9)
+	for name, c := range map[string][][]float64{
+		"positive": matmul(pos, pos),
+		"negative": matmul(neg, pos),
+	} {
+		if _, err := checkedChecksum(c); !errors.Is(err, errChecksumOverflow) {
+			t.Errorf("%s total: error = %v, want %v", name, err, errChecksumOverflow)
+		}
+	}
+}
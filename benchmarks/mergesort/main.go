@@ -0,0 +1,99 @@
+/*
+ * Merge Sort
+ *
+ * Sort 1,000,000 pseudo-random ints (benchlib.RandomInts seeded with
+ * benchlib.DefaultSeed) using a top-down merge sort that allocates a fresh
+ * slice for every merge. Run with --mem to see the allocation footprint.
+ * RESULT is the benchlib.Checksum of the sorted slice; SORT_TIME is the
+ * sort alone.
+ * Expected result: -6106578116107860387 (same input as quicksort)
+ *
+ * This benchmark tests:
+ * - Allocation throughput and GC pressure
+ * - Sequential memory access (merging)
+ * - Recursion
+ */
+
+package main
+
+import (
+	"flag"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	n                = 1000000
+	expectedChecksum = -6106578116107860387
+)
+
+// mergeSort returns a sorted copy of xs. Each call allocates its output, so
+// sorting n elements allocates O(n log n) bytes in total.
+func mergeSort(xs []int) []int {
+	if len(xs) <= 1 {
+		return append([]int(nil), xs...)
+	}
+	mid := len(xs) / 2
+	return merge(mergeSort(xs[:mid]), mergeSort(xs[mid:]))
+}
+
+// merge combines two sorted slices into a newly allocated sorted slice.
+// Ties take from left first, keeping the sort stable.
+func merge(left, right []int) []int {
+	out := make([]int, 0, len(left)+len(right))
+	i, j := 0, 0
+	for i < len(left) && j < len(right) {
+		if right[j] < left[i] {
+			out = append(out, right[j])
+			j++
+		} else {
+			out = append(out, left[i])
+			i++
+		}
+	}
+	out = append(out, left[i:]...)
+	return append(out, right[j:]...)
+}
+
+func isSorted(xs []int) bool {
+	for i := 1; i < len(xs); i++ {
+		if xs[i-1] > xs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "mergesort", Category: benchlib.CategoryAlgorithm, Expected: expectedChecksum})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+	opts.Phases = new(benchlib.Phases)
+
+	t0 := time.Now()
+
+	// Startup phase: generate the input
+	input := benchlib.RandomInts(benchlib.NewRand(opts.Seed), n)
+
+	startup := time.Since(t0)
+
+	// Compute benchmark. mergeSort never modifies input, so every
+	// iteration sorts the same data. COMPUTE_TIME covers the sort and the
+	// benchlib.Checksum pass; the sort phase times the sort alone.
+	var sorted []int
+	stats := benchlib.Run("mergesort", opts, startup, func() int64 {
+		opts.Phases.Measure("sort", func() { sorted = mergeSort(input) })
+		return benchlib.Checksum(sorted)
+	})
+
+	// Validate result
+	if !isSorted(sorted) {
+		benchlib.Failf("mergesort output is not sorted")
+	}
+	benchlib.ValidateSeed(opts.Seed, stats.Result, expectedChecksum)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+func TestMergeSort(t *testing.T) {
+	tests := map[string][]int{
+		"empty":           {},
+		"single":          {42},
+		"duplicate heavy": {2, 2, 1, 2, 1, 1, 2, 0, 0, 2, 1, 2, 2, 2, 0, 1},
+		"all equal":       {9, 9, 9, 9, 9},
+		"reverse sorted":  {5, 4, 3, 2, 1},
+	}
+	for name, in := range tests {
+		t.Run(name, func(t *testing.T) {
+			orig := slices.Clone(in)
+			got := mergeSort(in)
+			want := slices.Clone(in)
+			slices.Sort(want)
+			if !slices.Equal(got, want) {
+				t.Errorf("mergeSort(%v) = %v, want %v", in, got, want)
+			}
+			if !slices.Equal(in, orig) {
+				t.Errorf("mergeSort modified its input: %v", in)
+			}
+		})
+	}
+}
+
+func TestMergeSortRandom(t *testing.T) {
+	in := benchlib.RandomInts(benchlib.NewRand(1), 5000)
+	want := slices.Clone(in)
+	slices.Sort(want)
+	if !slices.Equal(mergeSort(in), want) {
+		t.Error("mergeSort disagrees with slices.Sort")
+	}
+}
+
+func TestExpectedChecksum(t *testing.T) {
+	xs := benchlib.RandomInts(benchlib.NewRand(benchlib.DefaultSeed), n)
+	if got := benchlib.Checksum(mergeSort(xs)); got != expectedChecksum {
+		t.Errorf("checksum = %d, want %d", got, expectedChecksum)
+	}
+}
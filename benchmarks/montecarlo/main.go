@@ -0,0 +1,74 @@
+/*
+ * Monte Carlo π Estimation
+ *
+ * Draw N = 20,000,000 points (x, y) in the unit square, each coordinate a
+ * benchlib.RandomFloat from a generator seeded with benchlib.DefaultSeed
+ * (x first, then y), and count the points with x² + y² < 1. The count,
+ * not the estimate 4·count/N, is RESULT, so it is an exact integer for a
+ * given seed. Each run reseeds, so every run draws the same points. With
+ * another --seed there is no expected value, and RESULT is only checked to
+ * be a count of at most N.
+ * Expected result: 15711812 (π ≈ 3.142362)
+ *
+ * This benchmark tests:
+ * - Pseudo-random number generation throughput
+ * - Floating-point multiply-add and comparison
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	samples       = 20000000
+	expectedCount = 15711812
+)
+
+// countInside draws n points from a generator seeded with seed and returns
+// how many fall strictly inside the unit circle.
+func countInside(seed int64, n int) int64 {
+	r := benchlib.NewRand(seed)
+	var inside int64
+	for i := 0; i < n; i++ {
+		x := benchlib.RandomFloat(r)
+		y := benchlib.RandomFloat(r)
+		if x*x+y*y < 1 {
+			inside++
+		}
+	}
+	return inside
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "montecarlo", Category: benchlib.CategoryNumeric, Expected: expectedCount})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	t0 := time.Now()
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("montecarlo", opts, startup, func() int64 {
+		return countInside(opts.Seed, samples)
+	})
+
+	if opts.Format == benchlib.FormatText {
+		fmt.Printf("PI_ESTIMATE: %.6f\n", 4*float64(stats.Result)/samples)
+	}
+
+	// Validate result
+	if stats.Result < 0 || stats.Result > samples {
+		benchlib.Failf("%d of %d points inside the circle", stats.Result, samples)
+	}
+	benchlib.ValidateSeed(opts.Seed, stats.Result, expectedCount)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+func TestCountInsideReproducible(t *testing.T) {
+	a := countInside(benchlib.DefaultSeed, 100000)
+	b := countInside(benchlib.DefaultSeed, 100000)
+	if a != b {
+		t.Errorf("same seed gave %d then %d", a, b)
+	}
+	if c := countInside(benchlib.DefaultSeed+1, 100000); c == a {
+		t.Errorf("different seeds both gave %d", a)
+	}
+}
+
+func TestCountInsidePinned(t *testing.T) {
+	// Reference values from an independent SplitMix64 implementation.
+	for _, tt := range []struct {
+		n    int
+		want int64
+	}{
+		{0, 0},
+		{1000, 766},
+		{1000000, 785189},
+	} {
+		if got := countInside(benchlib.DefaultSeed, tt.n); got != tt.want {
+			t.Errorf("countInside(%d) = %d, want %d", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestEstimateNearPi(t *testing.T) {
+	const n = 1000000
+	if est := 4 * float64(countInside(benchlib.DefaultSeed, n)) / n; math.Abs(est-math.Pi) > 0.01 {
+		t.Errorf("π estimate %v is more than 0.01 from π", est)
+	}
+}
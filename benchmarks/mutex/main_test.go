@@ -0,0 +1,14 @@
+package main
+
+import "testing"
+
+// Run with -race: the counter is only safe because of the mutex.
+func TestContendNoLostUpdates(t *testing.T) {
+	for _, goroutines := range []int{1, 2, 3, 7, 16} {
+		for _, n := range []int{0, 1, 10, 1001} {
+			if got := contend(n, goroutines); got != int64(n) {
+				t.Errorf("contend(%d, %d) = %d, want %d", n, goroutines, got, n)
+			}
+		}
+	}
+}
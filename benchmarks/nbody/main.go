@@ -0,0 +1,168 @@
+/*
+ * N-Body Simulation
+ *
+ * The Computer Language Benchmarks Game n-body workload: model the orbits of
+ * the Jovian planets around the Sun with a simple symplectic integrator
+ * (dt = 0.01), after offsetting the Sun's momentum.
+ *
+ * The reference run is 50,000,000 steps; this benchmark uses 5,000,000 so a
+ * single iteration stays well under a second. The published reference
+ * energy for that step count is -0.169083134.
+ * Expected result: -169083134 (energy × 1e9, rounded)
+ *
+ * This benchmark tests:
+ * - Floating-point arithmetic including sqrt
+ * - Tight nested loops over a small, cache-resident array
+ */
+
+package main
+
+import (
+	"flag"
+	"math"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	steps = 5000000
+	dt    = 0.01
+
+	// referenceEnergy is the Benchmarks Game output for steps, printed to
+	// nine decimal places; energyTolerance allows for that rounding.
+	referenceEnergy = -0.169083134
+	energyTolerance = 1e-9
+
+	// energyScale converts the energy to the integer RESULT.
+	energyScale = 1e9
+
+	solarMass   = 4 * math.Pi * math.Pi
+	daysPerYear = 365.24
+)
+
+type body struct {
+	x, y, z, vx, vy, vz, mass float64
+}
+
+// newSystem returns the Sun and the four gas giants with the Sun's velocity
+// adjusted so the system's total momentum is zero.
+func newSystem() []body {
+	bodies := []body{
+		// Sun
+		{mass: solarMass},
+		// Jupiter
+		{
+			x: 4.84143144246472090e+00, y: -1.16032004402742839e+00, z: -1.03622044471123109e-01,
+			vx: 1.66007664274403694e-03 * daysPerYear, vy: 7.69901118419740425e-03 * daysPerYear, vz: -6.90460016972063023e-05 * daysPerYear,
+			mass: 9.54791938424326609e-04 * solarMass,
+		},
+		// Saturn
+		{
+			x: 8.34336671824457987e+00, y: 4.12479856412430479e+00, z: -4.03523417114321381e-01,
+			vx: -2.76742510726862411e-03 * daysPerYear, vy: 4.99852801234917238e-03 * daysPerYear, vz: 2.30417297573763929e-05 * daysPerYear,
+			mass: 2.85885980666130812e-04 * solarMass,
+		},
+		// Uranus
+		{
+			x: 1.28943695621391310e+01, y: -1.51111514016986312e+01, z: -2.23307578892655734e-01,
+			vx: 2.96460137564761618e-03 * daysPerYear, vy: 2.37847173959480950e-03 * daysPerYear, vz: -2.96589568540237556e-05 * daysPerYear,
+			mass: 4.36624404335156298e-05 * solarMass,
+		},
+		// Neptune
+		{
+			x: 1.53796971148509165e+01, y: -2.59193146099879641e+01, z: 1.79258772950371181e-01,
+			vx: 2.68067772490389322e-03 * daysPerYear, vy: 1.62824170038242295e-03 * daysPerYear, vz: -9.51592254519715870e-05 * daysPerYear,
+			mass: 5.15138902046611451e-05 * solarMass,
+		},
+	}
+
+	var px, py, pz float64
+	for _, b := range bodies {
+		px += b.vx * b.mass
+		py += b.vy * b.mass
+		pz += b.vz * b.mass
+	}
+	bodies[0].vx = -px / solarMass
+	bodies[0].vy = -py / solarMass
+	bodies[0].vz = -pz / solarMass
+	return bodies
+}
+
+// advance moves the system forward by one time step of dt.
+func advance(bodies []body, dt float64) {
+	for i := range bodies {
+		bi := &bodies[i]
+		for j := i + 1; j < len(bodies); j++ {
+			bj := &bodies[j]
+			dx := bi.x - bj.x
+			dy := bi.y - bj.y
+			dz := bi.z - bj.z
+			d2 := dx*dx + dy*dy + dz*dz
+			mag := dt / (d2 * math.Sqrt(d2))
+			bi.vx -= dx * bj.mass * mag
+			bi.vy -= dy * bj.mass * mag
+			bi.vz -= dz * bj.mass * mag
+			bj.vx += dx * bi.mass * mag
+			bj.vy += dy * bi.mass * mag
+			bj.vz += dz * bi.mass * mag
+		}
+	}
+	for i := range bodies {
+		b := &bodies[i]
+		b.x += dt * b.vx
+		b.y += dt * b.vy
+		b.z += dt * b.vz
+	}
+}
+
+// energy returns the total kinetic plus potential energy of the system.
+func energy(bodies []body) float64 {
+	var e float64
+	for i, bi := range bodies {
+		e += 0.5 * bi.mass * (bi.vx*bi.vx + bi.vy*bi.vy + bi.vz*bi.vz)
+		for _, bj := range bodies[i+1:] {
+			dx := bi.x - bj.x
+			dy := bi.y - bj.y
+			dz := bi.z - bj.z
+			e -= bi.mass * bj.mass / math.Sqrt(dx*dx+dy*dy+dz*dz)
+		}
+	}
+	return e
+}
+
+// simulate runs a fresh system for the given number of steps and returns
+// its final energy.
+func simulate(steps int) float64 {
+	bodies := newSystem()
+	for i := 0; i < steps; i++ {
+		advance(bodies, dt)
+	}
+	return energy(bodies)
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "nbody", Category: benchlib.CategoryNumeric, Expected: -169083134})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	t0 := time.Now()
+	startup := time.Since(t0)
+
+	// Compute benchmark. Only the scaled energy crosses the int64 RESULT
+	// boundary, so the unscaled value is kept for validation.
+	var e float64
+	stats := benchlib.Run("nbody", opts, startup, func() int64 {
+		e = simulate(steps)
+		return int64(math.Round(e * energyScale))
+	})
+
+	// Validate result
+	if math.Abs(e-referenceEnergy) > energyTolerance {
+		benchlib.Failf("expected energy %.9f got %.9f (RESULT %d)", referenceEnergy, e, stats.Result)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEnergyReferenceValues(t *testing.T) {
+	// Benchmarks Game reference output, printed to nine decimals.
+	tests := []struct {
+		steps int
+		want  float64
+	}{
+		{0, -0.169075164},
+		{1000, -0.169087605},
+	}
+	for _, tt := range tests {
+		if got := simulate(tt.steps); math.Abs(got-tt.want) > energyTolerance {
+			t.Errorf("energy after %d steps = %.9f, want %.9f", tt.steps, got, tt.want)
+		}
+	}
+}
+
+func TestAdvanceTwoBodiesOneStep(t *testing.T) {
+	// Two unit masses at rest, 2 apart. One step gives each a speed of
+	// dt/4 towards the other, moving each dt²/4 closer:
+	//   KE = 2 · ½ · (dt/4)²       = dt²/16
+	//   PE = −1 / (2 − dt²/2)
+	bodies := []body{{mass: 1}, {x: 2, mass: 1}}
+	advance(bodies, dt)
+
+	want := dt*dt/16 - 1/(2-dt*dt/2)
+	if got := energy(bodies); math.Abs(got-want) > 1e-15 {
+		t.Errorf("energy = %.15f, want %.15f", got, want)
+	}
+	if math.Abs(bodies[0].vx-dt/4) > 1e-18 || math.Abs(bodies[1].vx+dt/4) > 1e-18 {
+		t.Errorf("velocities = %v, %v, want ±%v", bodies[0].vx, bodies[1].vx, dt/4)
+	}
+}
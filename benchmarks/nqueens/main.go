@@ -0,0 +1,132 @@
+/*
+ * N-Queens
+ *
+ * Count every placement of N = 14 non-attacking queens on an N×N board
+ * (OEIS A000170) by backtracking row by row. The columns and both diagonal
+ * directions under attack are kept as bitmasks, so the free squares of a
+ * row are one AND-NOT away. --n selects another board size; its expected
+ * count comes from the OEIS table, or for sizes beyond the table from an
+ * independent, unoptimized solver.
+ * Expected result: 365596
+ *
+ * This benchmark tests:
+ * - Deep recursion with small frames
+ * - Bit manipulation (shifts, lowest set bit)
+ * - Unpredictable branches
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	defaultN = 14
+	maxN     = 32
+)
+
+// knownCounts is OEIS A000170, the number of solutions for n = 0, 1, 2, ...
+var knownCounts = []int64{
+	1, 1, 0, 0, 2, 10, 4, 40, 92, 352, 724, 2680, 14200, 73712, 365596,
+	2279184, 14772512, 95815104, 666090624,
+}
+
+// countSolutions returns the number of ways to place n non-attacking
+// queens on an n×n board, 0 <= n <= maxN.
+func countSolutions(n int) int64 {
+	if n == 0 {
+		return 1
+	}
+	all := uint32(1)<<n - 1
+	if n == maxN {
+		all = ^uint32(0)
+	}
+	return place(all, 0, 0, 0)
+}
+
+// place counts completions given the occupied columns and the squares of
+// the current row attacked along each diagonal direction. Moving to the
+// next row shifts the diagonal masks one square further.
+func place(all, cols, left, right uint32) int64 {
+	if cols == all {
+		return 1
+	}
+	var count int64
+	for free := all &^ (cols | left | right); free != 0; {
+		bit := free & -free
+		free ^= bit
+		count += place(all, cols|bit, (left|bit)<<1&all, (right|bit)>>1)
+	}
+	return count
+}
+
+// referenceCount counts solutions with a plain column array and explicit
+// attack checks. It is much slower than countSolutions but shares none of
+// its bit tricks, so it serves as the expected value for sizes not in
+// knownCounts.
+func referenceCount(n int) int64 {
+	queens := make([]int, n) // queens[row] = column
+	var solve func(row int) int64
+	solve = func(row int) int64 {
+		if row == n {
+			return 1
+		}
+		var count int64
+		for col := 0; col < n; col++ {
+			safe := true
+			for r := 0; r < row; r++ {
+				c := queens[r]
+				if c == col || c-col == r-row || c-col == row-r {
+					safe = false
+					break
+				}
+			}
+			if safe {
+				queens[row] = col
+				count += solve(row + 1)
+			}
+		}
+		return count
+	}
+	return solve(0)
+}
+
+// expectedCount returns the known solution count for n.
+func expectedCount(n int) int64 {
+	if n < len(knownCounts) {
+		return knownCounts[n]
+	}
+	return referenceCount(n)
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "nqueens", Category: benchlib.CategoryRecursion, Expected: knownCounts[defaultN]})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	n := flag.Int("n", defaultN, fmt.Sprintf("board size, 1 to %d", maxN))
+	flag.Parse()
+	if *n < 1 || *n > maxN {
+		fmt.Fprintf(os.Stderr, "nqueens: --n must be in [1, %d], got %d\n", maxN, *n)
+		os.Exit(2)
+	}
+
+	t0 := time.Now()
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("nqueens", opts, startup, func() int64 {
+		return countSolutions(*n)
+	})
+
+	// Validate result
+	benchlib.Validate(stats.Result, expectedCount(*n))
+}
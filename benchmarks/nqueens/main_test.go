@@ -0,0 +1,38 @@
+package main
+
+import "testing"
+
+func TestCountSolutionsOEIS(t *testing.T) {
+	// OEIS A000170 for n = 4..10.
+	want := map[int]int64{4: 2, 5: 10, 6: 4, 7: 40, 8: 92, 9: 352, 10: 724}
+	for n := 4; n <= 10; n++ {
+		if got := countSolutions(n); got != want[n] {
+			t.Errorf("countSolutions(%d) = %d, want %d", n, got, want[n])
+		}
+	}
+}
+
+func TestCountSolutionsSmallBoards(t *testing.T) {
+	for n, want := range []int64{1, 1, 0, 0} {
+		if got := countSolutions(n); got != want {
+			t.Errorf("countSolutions(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
+
+func TestReferenceCountAgrees(t *testing.T) {
+	for n := 1; n <= 9; n++ {
+		if got, want := referenceCount(n), knownCounts[n]; got != want {
+			t.Errorf("referenceCount(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
+
+func TestExpectedCount(t *testing.T) {
+	if got := expectedCount(defaultN); got != 365596 {
+		t.Errorf("expectedCount(%d) = %d, want 365596", defaultN, got)
+	}
+	if got := countSolutions(12); got != expectedCount(12) {
+		t.Errorf("countSolutions(12) = %d, want %d", got, expectedCount(12))
+	}
+}
@@ -0,0 +1,139 @@
+/*
+ * Pi Digits
+ *
+ * Generate the first 10,000 decimal digits of π, starting with the leading
+ * 3, using Gibbons' unbounded streaming spigot with math/big integers.
+ * RESULT is the sum of the digits.
+ * Expected result: 44889
+ *
+ * The spigot keeps π as a linear fractional transformation (numer, accum,
+ * denom) of the series π = Σ (k!)²·2^(k+1)/(2k+1)!, absorbing one term at a
+ * time. A digit is safe to emit once the transformation maps both 3 and 4
+ * to the same integer part; emitting it scales the state by ten. The
+ * integers grow to several thousand digits, so nearly all the time is in
+ * multi-word multiplication and division.
+ *
+ * The big.Int state is allocated inside the compute phase, fresh on every
+ * run, and the scratch values are reused across terms, so allocation is
+ * limited to the buffers growing with the numbers.
+ *
+ * This benchmark tests:
+ * - Arbitrary-precision multiply and divide by small and large operands
+ * - Memory bandwidth of ever-growing multi-word integers
+ */
+
+package main
+
+import (
+	"flag"
+	"math/big"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	numDigits = 10000
+
+	expectedSum = 44889
+)
+
+// spigot is the state of the streaming algorithm: the transformation
+// x ↦ (numer·x + accum)/denom, with tmp1 and tmp2 as scratch.
+type spigot struct {
+	numer, accum, denom *big.Int
+	tmp1, tmp2          *big.Int
+	k                   int64
+}
+
+func newSpigot() *spigot {
+	return &spigot{
+		numer: big.NewInt(1),
+		accum: big.NewInt(0),
+		denom: big.NewInt(1),
+		tmp1:  new(big.Int),
+		tmp2:  new(big.Int),
+	}
+}
+
+// nextTerm absorbs the next term of the series.
+func (s *spigot) nextTerm() {
+	s.k++
+	k2 := big.NewInt(2*s.k + 1)
+	s.accum.Add(s.accum, s.tmp1.Lsh(s.numer, 1))
+	s.accum.Mul(s.accum, k2)
+	s.denom.Mul(s.denom, k2)
+	s.numer.Mul(s.numer, big.NewInt(s.k))
+}
+
+// extract returns the integer part of the transformation applied to x.
+func (s *spigot) extract(x int64) int64 {
+	s.tmp1.Mul(s.numer, big.NewInt(x))
+	s.tmp1.Add(s.tmp1, s.accum)
+	s.tmp1.Quo(s.tmp1, s.denom)
+	return s.tmp1.Int64()
+}
+
+// eliminate removes the emitted digit d and shifts the state one decimal
+// place.
+func (s *spigot) eliminate(d int64) {
+	s.accum.Sub(s.accum, s.tmp2.Mul(s.denom, big.NewInt(d)))
+	s.accum.Mul(s.accum, big.NewInt(10))
+	s.numer.Mul(s.numer, big.NewInt(10))
+}
+
+// next returns the next digit of π.
+func (s *spigot) next() int64 {
+	for {
+		s.nextTerm()
+		if s.numer.Cmp(s.accum) > 0 {
+			continue
+		}
+		d := s.extract(3)
+		if d != s.extract(4) {
+			continue
+		}
+		s.eliminate(d)
+		return d
+	}
+}
+
+// piDigits returns the first n decimal digits of π, 3 first.
+func piDigits(n int) []byte {
+	s := newSpigot()
+	digits := make([]byte, n)
+	for i := range digits {
+		digits[i] = byte(s.next())
+	}
+	return digits
+}
+
+// digitSum returns the sum of the first n digits of π.
+func digitSum(n int) int64 {
+	var sum int64
+	for _, d := range piDigits(n) {
+		sum += int64(d)
+	}
+	return sum
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "pidigits", Category: benchlib.CategoryNumeric, Expected: expectedSum})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	t0 := time.Now()
+	startup := time.Since(t0)
+
+	// Compute benchmark. Each run builds its own spigot state.
+	stats := benchlib.Run("pidigits", opts, startup, func() int64 {
+		return digitSum(numDigits)
+	})
+
+	// Validate result
+	benchlib.Validate(stats.Result, expectedSum)
+}
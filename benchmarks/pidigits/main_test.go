@@ -0,0 +1,30 @@
+package main
+
+import "testing"
+
+func TestFirstFiftyDigits(t *testing.T) {
+	const want = "31415926535897932384626433832795028841971693993751"
+	got := piDigits(len(want))
+	for i := range got {
+		got[i] += '0'
+	}
+	if string(got) != want {
+		t.Errorf("piDigits(50) = %s, want %s", got, want)
+	}
+}
+
+func TestDigitSumSmall(t *testing.T) {
+	// 3+1+4+1+5+9+2+6+5+3
+	if got := digitSum(10); got != 39 {
+		t.Errorf("digitSum(10) = %d, want 39", got)
+	}
+}
+
+func TestExpectedSum(t *testing.T) {
+	if testing.Short() {
+		t.Skip("full-size run")
+	}
+	if got := digitSum(numDigits); got != expectedSum {
+		t.Errorf("digitSum(%d) = %d, want %d", numDigits, got, expectedSum)
+	}
+}
@@ -1,3 +1,8 @@
+//go:build ignore
+
+// The constraint above keeps the Go toolchain from treating this file as
+// cgo source for the Go benchmark in the same directory.
+
 /*
  * BENCH-008: Prime Sieve (Sieve of Eratosthenes)
  *
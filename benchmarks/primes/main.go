@@ -4,6 +4,10 @@
  * Find all prime numbers up to 100,000 using the Sieve of Eratosthenes algorithm.
  * Expected result: 9,592 primes
  *
+ * --bitset swaps the one-byte-per-entry []bool sieve for a []uint64 bit
+ * array, using an eighth of the memory. --segmented sieves in blocks of
+ * --segment numbers so memory stays bounded for n in the billions.
+ *
  * This benchmark tests:
  * - Array allocation and manipulation
  * - Bit/boolean array operations
@@ -15,8 +19,14 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"math"
+	"math/bits"
+	"os"
 	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
 )
 
 // sieveOfEratosthenes implements the Sieve of Eratosthenes algorithm
@@ -57,30 +67,220 @@ func sieveOfEratosthenes(n int) int {
 	return count
 }
 
-func main() {
-	// Measure startup time (initialization)
-	t0 := time.Now()
+// sieveBitset is sieveOfEratosthenes over a bit array: bit i of the
+// composite set is 1 once i is known not to be prime. Bits past n are never
+// set, so the prime count is the number of zero bits in 0..n.
+func sieveBitset(n int) int {
+	if n < 2 {
+		return 0
+	}
+
+	composite := make([]uint64, n/64+1)
+	composite[0] = 0b11 // 0 and 1
+
+	for p := 2; p*p <= n; p++ {
+		if composite[p/64]&(1<<(p%64)) != 0 {
+			continue
+		}
+		for i := p * p; i <= n; i += p {
+			composite[i/64] |= 1 << (i % 64)
+		}
+	}
+
+	marked := 0
+	for _, w := range composite {
+		marked += bits.OnesCount64(w)
+	}
+	return n + 1 - marked
+}
+
+// defaultSegment is the --segment default: 256 KiB of []bool, small enough
+// to stay in L2 on current x86 and ARM cores.
+const defaultSegment = 1 << 18
+
+// basePrimes returns the primes up to limit in increasing order.
+func basePrimes(limit int) []int {
+	if limit < 2 {
+		return nil
+	}
+	composite := make([]bool, limit+1)
+	var primes []int
+	for p := 2; p <= limit; p++ {
+		if composite[p] {
+			continue
+		}
+		primes = append(primes, p)
+		for i := p * p; i <= limit; i += p {
+			composite[i] = true
+		}
+	}
+	return primes
+}
+
+// sieveSegmented counts primes up to n by sieving [2, n] in windows of
+// segmentSize numbers, crossing off multiples of the base primes up to √n
+// in each window. Memory is O(√n + segmentSize) instead of O(n).
+func sieveSegmented(n, segmentSize int) int {
+	if n < 2 {
+		return 0
+	}
+	limit := int(math.Sqrt(float64(n)))
+	for limit*limit > n {
+		limit--
+	}
+	for (limit+1)*(limit+1) <= n {
+		limit++
+	}
+	base := basePrimes(limit)
+
+	count := 0
+	buf := make([]bool, min(segmentSize, n-1))
+	for lo := 2; lo <= n; lo += segmentSize {
+		hi := min(lo+segmentSize-1, n)
+		composite := buf[:hi-lo+1]
+		clear(composite)
+		for _, p := range base {
+			if p*p > hi {
+				break
+			}
+			// First multiple of p in the window, but never p itself.
+			start := max(p*p, (lo+p-1)/p*p)
+			for i := start; i <= hi; i += p {
+				composite[i-lo] = true
+			}
+		}
+		for _, c := range composite {
+			if !c {
+				count++
+			}
+		}
+	}
+	return count
+}
 
-	n := 100000
+const (
+	// defaultN is the canonical benchmark size, validated against
+	// expectedDefaultCount.
+	defaultN             = 100000
+	expectedDefaultCount = 9592
 
-	t1 := time.Now()
+	// maxReferenceN bounds the trial-division cross-check, which is
+	// O(n·√n) and would dominate the run for large n.
+	maxReferenceN = 1000000
+)
+
+// countPrimesTrialDivision counts primes up to n by testing each candidate
+// against odd divisors up to its square root. It shares no code with the
+// sieve, so it serves as an independent reference.
+func countPrimesTrialDivision(n int) int {
+	count := 0
+	for c := 2; c <= n; c++ {
+		if c%2 == 0 {
+			if c == 2 {
+				count++
+			}
+			continue
+		}
+		prime := true
+		for d := 3; d*d <= c; d += 2 {
+			if c%d == 0 {
+				prime = false
+				break
+			}
+		}
+		if prime {
+			count++
+		}
+	}
+	return count
+}
+
+// expectedCount returns the prime count that the sieve must produce for n.
+// The canonical size uses the hardcoded constant; other sizes up to
+// maxReferenceN are cross-checked by trial division. ok is false when n is
+// too large to verify.
+func expectedCount(n int) (want int, ok bool) {
+	if n == defaultN {
+		return expectedDefaultCount, true
+	}
+	if n > maxReferenceN {
+		return 0, false
+	}
+	return countPrimesTrialDivision(n), true
+}
 
-	// Compute benchmark
-	result := sieveOfEratosthenes(n)
+// config holds the primes-specific flags.
+type config struct {
+	n      int  // sieve upper bound (inclusive)
+	bitset bool // use sieveBitset
 
-	t2 := time.Now()
+	segmented bool // use sieveSegmented
+	segment   int  // numbers per window for sieveSegmented
+}
+
+// parseArgs parses the shared benchmark flags plus the primes flags.
+func parseArgs(args []string) (benchlib.Options, config, error) {
+	fs := flag.NewFlagSet("primes", flag.ContinueOnError)
+	var opts benchlib.Options
+	opts.RegisterFlags(fs)
+	var cfg config
+	fs.IntVar(&cfg.n, "n", defaultN, "sieve upper bound (inclusive)")
+	fs.BoolVar(&cfg.bitset, "bitset", false, "use a []uint64 bit array instead of []bool")
+	fs.BoolVar(&cfg.segmented, "segmented", false, "sieve in fixed-size windows (see --segment)")
+	fs.IntVar(&cfg.segment, "segment", defaultSegment, "numbers per window with --segmented")
+	if err := fs.Parse(args); err != nil {
+		return opts, config{}, err
+	}
+	if cfg.n < 0 {
+		return opts, config{}, fmt.Errorf("--n must be >= 0, got %d", cfg.n)
+	}
+	if cfg.segment < 1 {
+		return opts, config{}, fmt.Errorf("--segment must be >= 1, got %d", cfg.segment)
+	}
+	if cfg.bitset && cfg.segmented {
+		return opts, config{}, fmt.Errorf("--bitset and --segmented are mutually exclusive")
+	}
+	return opts, cfg, nil
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "primes", Category: benchlib.CategoryNumeric, Expected: 9592})
+}
+
+func main() {
+	opts, cfg, err := parseArgs(os.Args[1:])
+	if err != nil {
+		if err == flag.ErrHelp {
+			os.Exit(0)
+		}
+		fmt.Fprintf(os.Stderr, "primes: %v\n", err)
+		os.Exit(2)
+	}
+
+	n := cfg.n
+	sieve := sieveOfEratosthenes
+	switch {
+	case cfg.bitset:
+		sieve = sieveBitset
+	case cfg.segmented:
+		sieve = func(n int) int { return sieveSegmented(n, cfg.segment) }
+	}
+
+	// Measure startup time (initialization)
+	t0 := time.Now()
 
-	// Calculate times in microseconds
-	startupTimeUs := t1.Sub(t0).Microseconds()
-	computeTimeUs := t2.Sub(t1).Microseconds()
+	startup := time.Since(t0)
 
-	// Output standardized format
-	fmt.Printf("STARTUP_TIME_US: %d\n", startupTimeUs)
-	fmt.Printf("COMPUTE_TIME_US: %d\n", computeTimeUs)
-	fmt.Printf("RESULT: %d\n", result)
+	// Compute benchmark and output standardized format
+	stats := benchlib.Run("primes", opts, startup, func() int64 {
+		return int64(sieve(n))
+	})
 
 	// Validate result
-	if result != 9592 {
-		panic(fmt.Sprintf("Expected 9592 primes up to 100,000, got %d", result))
+	want, ok := expectedCount(n)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "primes: n=%d exceeds %d, skipping reference validation\n", n, maxReferenceN)
+		return
 	}
+	benchlib.Validate(stats.Result, int64(want))
 }
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    config
+		wantErr bool
+	}{
+		{nil, config{n: defaultN, segment: defaultSegment}, false},
+		{[]string{"--n=1000"}, config{n: 1000, segment: defaultSegment}, false},
+		{[]string{"--n", "0", "--iterations=3"}, config{n: 0, segment: defaultSegment}, false},
+		{[]string{"--bitset"}, config{n: defaultN, bitset: true, segment: defaultSegment}, false},
+		{[]string{"--segmented", "--segment=4096"}, config{n: defaultN, segmented: true, segment: 4096}, false},
+		{[]string{"--segment=0"}, config{}, true},
+		{[]string{"--bitset", "--segmented"}, config{}, true},
+		{[]string{"--n=-5"}, config{}, true},
+		{[]string{"--n=lots"}, config{}, true},
+	}
+	for _, tt := range tests {
+		_, cfg, err := parseArgs(tt.args)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseArgs(%v) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+			continue
+		}
+		if err == nil && cfg != tt.want {
+			t.Errorf("parseArgs(%v) = %+v, want %+v", tt.args, cfg, tt.want)
+		}
+	}
+}
+
+func TestExpectedCount(t *testing.T) {
+	tests := []struct {
+		n      int
+		want   int
+		wantOK bool
+	}{
+		{defaultN, expectedDefaultCount, true},
+		{0, 0, true},
+		{1, 0, true},
+		{2, 1, true},
+		{10, 4, true},
+		{1000, 168, true},
+		{maxReferenceN + 1, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := expectedCount(tt.n)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("expectedCount(%d) = (%d, %v), want (%d, %v)", tt.n, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestSieveMatchesTrialDivision(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 3, 30, 97, 1000, 65536, defaultN} {
+		if got, want := sieveOfEratosthenes(n), countPrimesTrialDivision(n); got != want {
+			t.Errorf("n=%d: sieve = %d, trial division = %d", n, got, want)
+		}
+	}
+}
+
+func TestSieveBitsetMatchesBool(t *testing.T) {
+	// Include sizes on either side of 64-bit word boundaries.
+	for _, n := range []int{0, 1, 2, 3, 63, 64, 65, 127, 128, 1000, 65536, defaultN, 1000003} {
+		if got, want := sieveBitset(n), sieveOfEratosthenes(n); got != want {
+			t.Errorf("n=%d: bitset = %d, bool = %d", n, got, want)
+		}
+	}
+}
+
+func TestSieveSegmentedMatchesSimple(t *testing.T) {
+	ns := []int{0, 1, 2, 3, 4, 97, 1000, 65536, defaultN, 999983, 3000000}
+	// Segment sizes that are prime, tiny, unaligned to any prime, equal to
+	// a power of two, and larger than every n.
+	segments := []int{1, 7, 1000, 1 << 16, 5000000}
+	for _, n := range ns {
+		want := sieveOfEratosthenes(n)
+		for _, seg := range segments {
+			if seg < 1000 && n > defaultN {
+				continue // tiny windows rescan the base primes each time; too slow at large n
+			}
+			if got := sieveSegmented(n, seg); got != want {
+				t.Errorf("n=%d segment=%d: segmented = %d, simple = %d", n, seg, got, want)
+			}
+		}
+	}
+}
+
+func TestBasePrimes(t *testing.T) {
+	got := basePrimes(30)
+	want := []int{2, 3, 5, 7, 11, 13, 17, 19, 23, 29}
+	if !slices.Equal(got, want) {
+		t.Errorf("basePrimes(30) = %v, want %v", got, want)
+	}
+	if basePrimes(1) != nil {
+		t.Errorf("basePrimes(1) = %v, want nil", basePrimes(1))
+	}
+}
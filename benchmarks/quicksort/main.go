@@ -0,0 +1,131 @@
+/*
+ * Quicksort
+ *
+ * Sort 1,000,000 pseudo-random ints (benchlib.RandomInts seeded with
+ * benchlib.DefaultSeed) using an in-place quicksort. RESULT is the
+ * benchlib.Checksum of the sorted slice; SORT_TIME is the sort alone.
+ * Expected result: -6106578116107860387
+ *
+ * This benchmark tests:
+ * - Branch prediction on data-dependent comparisons
+ * - Recursion
+ * - In-place memory access (swaps)
+ */
+
+package main
+
+import (
+	"flag"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	n                = 1000000
+	expectedChecksum = -6106578116107860387
+
+	// insertionCutoff is the partition size below which insertion sort
+	// takes over from partitioning.
+	insertionCutoff = 16
+)
+
+// quicksort sorts xs in place. Pivots are the median of the first, middle
+// and last elements, and the loop recurses into the smaller partition only,
+// so stack depth stays O(log n) even on adversarial input.
+func quicksort(xs []int) {
+	for len(xs) > insertionCutoff {
+		p := partition(xs)
+		if p < len(xs)-p {
+			quicksort(xs[:p])
+			xs = xs[p+1:]
+		} else {
+			quicksort(xs[p+1:])
+			xs = xs[:p]
+		}
+	}
+	insertionSort(xs)
+}
+
+// partition applies Lomuto partitioning around a median-of-three pivot and
+// returns the pivot's final index.
+func partition(xs []int) int {
+	last := len(xs) - 1
+	mid := last / 2
+	if xs[mid] < xs[0] {
+		xs[mid], xs[0] = xs[0], xs[mid]
+	}
+	if xs[last] < xs[0] {
+		xs[last], xs[0] = xs[0], xs[last]
+	}
+	if xs[mid] < xs[last] {
+		xs[mid], xs[last] = xs[last], xs[mid]
+	}
+	pivot := xs[last]
+
+	i := 0
+	for j := 0; j < last; j++ {
+		if xs[j] < pivot {
+			xs[i], xs[j] = xs[j], xs[i]
+			i++
+		}
+	}
+	xs[i], xs[last] = xs[last], xs[i]
+	return i
+}
+
+func insertionSort(xs []int) {
+	for i := 1; i < len(xs); i++ {
+		v := xs[i]
+		j := i - 1
+		for j >= 0 && xs[j] > v {
+			xs[j+1] = xs[j]
+			j--
+		}
+		xs[j+1] = v
+	}
+}
+
+func isSorted(xs []int) bool {
+	for i := 1; i < len(xs); i++ {
+		if xs[i-1] > xs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "quicksort", Category: benchlib.CategoryAlgorithm, Expected: expectedChecksum})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+	opts.Phases = new(benchlib.Phases)
+
+	t0 := time.Now()
+
+	// Startup phase: generate the input and a work buffer to sort
+	input := benchlib.RandomInts(benchlib.NewRand(opts.Seed), n)
+	work := make([]int, n)
+
+	startup := time.Since(t0)
+
+	// Compute benchmark. Each run restores the unsorted input with copy so
+	// repeated iterations sort the same data. COMPUTE_TIME covers the copy,
+	// the sort and the benchlib.Checksum pass; the sort phase times the sort
+	// alone.
+	stats := benchlib.Run("quicksort", opts, startup, func() int64 {
+		copy(work, input)
+		opts.Phases.Measure("sort", func() { quicksort(work) })
+		return benchlib.Checksum(work)
+	})
+
+	// Validate result
+	if !isSorted(work) {
+		benchlib.Failf("quicksort output is not sorted")
+	}
+	benchlib.ValidateSeed(opts.Seed, stats.Result, expectedChecksum)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+func TestQuicksortSmallSlices(t *testing.T) {
+	tests := map[string][]int{
+		"empty":          {},
+		"single":         {7},
+		"pair":           {2, 1},
+		"already sorted": {1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20},
+		"reverse sorted": {20, 19, 18, 17, 16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1},
+		"all equal":      {5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5},
+		"duplicates":     {3, 1, 3, 2, 1, 3, 2, 2, 1, 0, 3, 0, 1, 2, 3, 0, 0, 2, 1, 3},
+	}
+	for name, in := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := slices.Clone(in)
+			quicksort(got)
+			want := slices.Clone(in)
+			slices.Sort(want)
+			if !slices.Equal(got, want) {
+				t.Errorf("quicksort(%v) = %v, want %v", in, got, want)
+			}
+		})
+	}
+}
+
+func TestQuicksortRandom(t *testing.T) {
+	r := benchlib.NewRand(1)
+	for _, size := range []int{17, 100, 1000, 10000} {
+		got := benchlib.RandomInts(r, size)
+		want := slices.Clone(got)
+		quicksort(got)
+		slices.Sort(want)
+		if !slices.Equal(got, want) {
+			t.Errorf("size %d: quicksort disagrees with slices.Sort", size)
+		}
+	}
+}
+
+func TestExpectedChecksum(t *testing.T) {
+	xs := benchlib.RandomInts(benchlib.NewRand(benchlib.DefaultSeed), n)
+	slices.Sort(xs)
+	if got := benchlib.Checksum(xs); got != expectedChecksum {
+		t.Errorf("checksum of sorted input = %d, want %d", got, expectedChecksum)
+	}
+}
+
+func TestOtherSeed(t *testing.T) {
+	// What main computes under --seed=7: other inputs, so another RESULT,
+	// which ValidateSeed leaves unchecked; the sort invariant still holds.
+	xs := benchlib.RandomInts(benchlib.NewRand(7), n)
+	quicksort(xs)
+	if !isSorted(xs) {
+		t.Fatal("quicksort output is not sorted")
+	}
+	if got := benchlib.Checksum(xs); got == expectedChecksum {
+		t.Errorf("checksum with seed 7 = %d, the DefaultSeed result", got)
+	}
+}
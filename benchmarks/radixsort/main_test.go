@@ -0,0 +1,75 @@
+package main
+
+import (
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+func sorted(xs []uint32) []uint32 {
+	out := slices.Clone(xs)
+	radixSort(out, make([]uint32, len(out)))
+	return out
+}
+
+func TestRadixSort(t *testing.T) {
+	tests := map[string][]uint32{
+		"empty":           {},
+		"single":          {42},
+		"zeros":           {0, 0, 0},
+		"zero and max":    {0xffffffff, 0, 0xffffffff, 0, 1},
+		"duplicate heavy": {2, 2, 1, 2, 1, 1, 2, 0, 0, 2, 1, 2, 2, 2, 0, 1},
+		// Keys that differ only in the top or bottom digit.
+		"digit boundaries": {0x01000000, 0x00000001, 0x00ffffff, 0x01000001, 0x000000ff, 0x00000100},
+		"reverse sorted":   {5, 4, 3, 2, 1},
+	}
+	for name, in := range tests {
+		t.Run(name, func(t *testing.T) {
+			want := slices.Clone(in)
+			sort.Slice(want, func(i, j int) bool { return want[i] < want[j] })
+			if got := sorted(in); !slices.Equal(got, want) {
+				t.Errorf("radixSort(%v) = %v, want %v", in, got, want)
+			}
+		})
+	}
+}
+
+func TestRadixSortRandom(t *testing.T) {
+	r := benchlib.NewRand(1)
+	for trial := 0; trial < 200; trial++ {
+		size := int(r.Uint64() % 300)
+		in := randomUint32s(r, size)
+		// Narrow some inputs to a few distinct values so duplicates and
+		// zeros are common.
+		if mod := uint32(r.Uint64() % 4); mod != 0 {
+			for i := range in {
+				in[i] %= mod * 3
+			}
+		}
+		want := slices.Clone(in)
+		sort.Slice(want, func(i, j int) bool { return want[i] < want[j] })
+		if got := sorted(in); !slices.Equal(got, want) {
+			t.Fatalf("trial %d: radixSort disagrees with sort.Slice on %v", trial, in)
+		}
+	}
+}
+
+func TestRadixSortLongerScratch(t *testing.T) {
+	xs := []uint32{3, 1, 2}
+	radixSort(xs, make([]uint32, 10))
+	if !slices.Equal(xs, []uint32{1, 2, 3}) {
+		t.Errorf("radixSort with oversized scratch = %v", xs)
+	}
+}
+
+func TestExpectedChecksum(t *testing.T) {
+	xs := sorted(randomUint32s(benchlib.NewRand(benchlib.DefaultSeed), n))
+	if !isSorted(xs) {
+		t.Fatal("output not sorted")
+	}
+	if got := benchlib.Checksum32(xs); got != expectedChecksum {
+		t.Errorf("Checksum32 = %d, want %d", got, expectedChecksum)
+	}
+}
@@ -0,0 +1,219 @@
+/*
+ * Ray Tracer
+ *
+ * Render a fixed scene of five spheres (one of them a huge sphere serving
+ * as the floor) lit by a point light to a 512×512 image, with 4×4
+ * supersampling: 16 rays per pixel through the centers of a regular grid
+ * of sub-pixels, their colors averaged. Shading is ambient plus Lambertian diffuse
+ * with hard shadows; reflective spheres spawn a mirror ray, and reflection
+ * stops after maxDepth bounces, so the cap is fixed by the scene rather
+ * than by any tolerance. The camera uses a fixed viewport instead of a
+ * field-of-view angle and the shading uses no pow, so only +, −, ×, ÷ and
+ * sqrt appear, and any port that evaluates them in IEEE double precision
+ * without fused multiply-add reproduces the image exactly.
+ *
+ * Each pixel's luminance, 0.2126·R + 0.7152·G + 0.0722·B clamped to [0, 1]
+ * (the average color's, not the average of the samples' luminances),
+ * is quantized to 0..255. RESULT is Σ (i+1)·q[i] mod 1,000,000,007 over
+ * the pixels in row-major order, top row first.
+ * Expected result: 902920476
+ *
+ * This benchmark tests:
+ * - Floating-point vector arithmetic and square roots
+ * - Branchy intersection tests
+ * - Bounded recursion
+ */
+
+package main
+
+import (
+	"flag"
+	"math"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	width  = 512
+	height = 512
+
+	// samples is the number of sub-pixel rays along each axis of a pixel.
+	samples = 4
+
+	// maxDepth is the number of reflection bounces after which a ray is
+	// shaded without reflection.
+	maxDepth = 4
+
+	// epsilon offsets secondary rays off a surface so they do not hit it
+	// again, and is the nearest accepted intersection distance.
+	epsilon = 1e-6
+
+	// checksumModulus keeps the position-weighted checksum in range.
+	checksumModulus = 1000000007
+
+	expectedChecksum = 902920476
+)
+
+type vec struct{ x, y, z float64 }
+
+func (a vec) add(b vec) vec       { return vec{a.x + b.x, a.y + b.y, a.z + b.z} }
+func (a vec) sub(b vec) vec       { return vec{a.x - b.x, a.y - b.y, a.z - b.z} }
+func (a vec) scale(s float64) vec { return vec{a.x * s, a.y * s, a.z * s} }
+func (a vec) dot(b vec) float64   { return a.x*b.x + a.y*b.y + a.z*b.z }
+func (a vec) unit() vec           { return a.scale(1 / math.Sqrt(a.dot(a))) }
+
+type sphere struct {
+	center  vec
+	radius  float64
+	color   vec
+	reflect float64 // share of the color taken from the mirror ray, in [0, 1]
+}
+
+// intersect returns the nearest distance t > epsilon along the ray
+// origin + t·dir, with dir a unit vector, at which it meets s.
+func (s sphere) intersect(origin, dir vec) (float64, bool) {
+	oc := origin.sub(s.center)
+	b := oc.dot(dir)
+	c := oc.dot(oc) - s.radius*s.radius
+	disc := b*b - c
+	if disc < 0 {
+		return 0, false
+	}
+	sq := math.Sqrt(disc)
+	if t := -b - sq; t > epsilon {
+		return t, true
+	}
+	if t := -b + sq; t > epsilon {
+		return t, true
+	}
+	return 0, false
+}
+
+type scene struct {
+	spheres []sphere
+	light   vec
+	ambient float64
+}
+
+// defaultScene is the scene the benchmark renders.
+var defaultScene = scene{
+	spheres: []sphere{
+		{center: vec{0, -1001, -5}, radius: 1000, color: vec{0.8, 0.8, 0.8}, reflect: 0.2},
+		{center: vec{0, 0, -5}, radius: 1, color: vec{1, 0.2, 0.2}, reflect: 0.5},
+		{center: vec{-2.2, 0.2, -6}, radius: 1.2, color: vec{0.2, 1, 0.2}, reflect: 0.3},
+		{center: vec{2, -0.4, -4}, radius: 0.6, color: vec{0.2, 0.2, 1}, reflect: 0.7},
+		{center: vec{0.8, 1.6, -7}, radius: 0.8, color: vec{1, 1, 0.2}, reflect: 0},
+	},
+	light:   vec{5, 5, 0},
+	ambient: 0.1,
+}
+
+// hit returns the nearest sphere along the ray and the distance to it.
+func (sc *scene) hit(origin, dir vec) (*sphere, float64) {
+	var nearest *sphere
+	best := math.Inf(1)
+	for i := range sc.spheres {
+		if t, ok := sc.spheres[i].intersect(origin, dir); ok && t < best {
+			nearest, best = &sc.spheres[i], t
+		}
+	}
+	return nearest, best
+}
+
+// sky is the background color seen along dir: white at the horizon
+// blending to light blue straight up.
+func sky(dir vec) vec {
+	t := 0.5 * (dir.y + 1)
+	return vec{1, 1, 1}.scale(1 - t).add(vec{0.5, 0.7, 1}.scale(t))
+}
+
+// trace returns the color seen along the ray; depth is the number of
+// reflections already followed.
+func (sc *scene) trace(origin, dir vec, depth int) vec {
+	s, t := sc.hit(origin, dir)
+	if s == nil {
+		return sky(dir)
+	}
+	p := origin.add(dir.scale(t))
+	n := p.sub(s.center).scale(1 / s.radius)
+	p = p.add(n.scale(epsilon))
+
+	color := s.color.scale(sc.ambient)
+	toLight := sc.light.sub(p)
+	lightDist := math.Sqrt(toLight.dot(toLight))
+	l := toLight.scale(1 / lightDist)
+	if diffuse := n.dot(l); diffuse > 0 {
+		if blocker, bt := sc.hit(p, l); blocker == nil || bt > lightDist {
+			color = color.add(s.color.scale(diffuse))
+		}
+	}
+
+	if s.reflect > 0 && depth < maxDepth {
+		r := dir.sub(n.scale(2 * dir.dot(n)))
+		mirror := sc.trace(p, r, depth+1)
+		color = color.scale(1 - s.reflect).add(mirror.scale(s.reflect))
+	}
+	return color
+}
+
+// luminance returns the clamped Rec. 709 luminance of c.
+func luminance(c vec) float64 {
+	y := 0.2126*c.x + 0.7152*c.y + 0.0722*c.z
+	return min(max(y, 0), 1)
+}
+
+// render traces an n×n grid of rays through each pixel of a w×h image and
+// returns the quantized luminance of each pixel's average color, in
+// row-major order. The camera sits at the origin looking down −z at a
+// viewport two units tall, one unit away, with the image's aspect ratio.
+func render(sc *scene, w, h, n int) []uint8 {
+	pixels := make([]uint8, 0, w*h)
+	aspect := float64(w) / float64(h)
+	sub := float64(n)
+	for j := 0; j < h; j++ {
+		for i := 0; i < w; i++ {
+			var sum vec
+			for sj := 0; sj < n; sj++ {
+				y := 1 - 2*(float64(j)+(float64(sj)+0.5)/sub)/float64(h)
+				for si := 0; si < n; si++ {
+					x := (2*(float64(i)+(float64(si)+0.5)/sub)/float64(w) - 1) * aspect
+					sum = sum.add(sc.trace(vec{}, vec{x, y, -1}.unit(), 0))
+				}
+			}
+			l := luminance(sum.scale(1 / (sub * sub)))
+			pixels = append(pixels, uint8(math.Round(l*255)))
+		}
+	}
+	return pixels
+}
+
+// checksum returns Σ (i+1)·q[i] mod checksumModulus.
+func checksum(q []uint8) int64 {
+	var sum int64
+	for i, v := range q {
+		sum = (sum + int64(i+1)%checksumModulus*int64(v)) % checksumModulus
+	}
+	return sum
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "raytrace", Category: benchlib.CategoryNumeric, Expected: expectedChecksum})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	t0 := time.Now()
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("raytrace", opts, startup, func() int64 {
+		return checksum(render(&defaultScene, width, height, samples))
+	})
+
+	// Validate result
+	benchlib.Validate(stats.Result, expectedChecksum)
+}
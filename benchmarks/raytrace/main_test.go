@@ -0,0 +1,101 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// singleSphere is a matte sphere straight ahead of the camera, large
+// enough to cover the four central pixels of a 4×4 image.
+var singleSphere = scene{
+	spheres: []sphere{{center: vec{0, 0, -3}, radius: 1.5, color: vec{1, 0.5, 0.25}}},
+	light:   vec{5, 5, 0},
+	ambient: 0.1,
+}
+
+func TestRenderSingleSphere(t *testing.T) {
+	q := render(&singleSphere, 4, 4, 1)
+	if len(q) != 16 {
+		t.Fatalf("got %d pixels, want 16", len(q))
+	}
+	// The corner rays miss the sphere and see the sky.
+	for _, i := range []int{0, 3, 12, 15} {
+		x, y := -0.75, 0.75
+		if i%4 == 3 {
+			x = 0.75
+		}
+		if i >= 12 {
+			y = -0.75
+		}
+		want := uint8(math.Round(luminance(sky(vec{x, y, -1}.unit())) * 255))
+		if q[i] != want {
+			t.Errorf("corner pixel %d = %d, want sky %d", i, q[i], want)
+		}
+	}
+	// The central rays hit the sphere; the upper right one faces the light.
+	for _, i := range []int{5, 6, 9, 10} {
+		if q[i] < uint8(math.Round(luminance(singleSphere.spheres[0].color.scale(singleSphere.ambient))*255)) {
+			t.Errorf("central pixel %d = %d, darker than ambient", i, q[i])
+		}
+	}
+	if !(q[6] > q[9]) {
+		t.Errorf("lit pixel %d not brighter than pixel facing away %d", q[6], q[9])
+	}
+	if got, want := checksum(q), int64(24547); got != want {
+		t.Errorf("checksum = %d, want %d (pixels %v)", got, want, q)
+	}
+}
+
+func TestIntersect(t *testing.T) {
+	s := sphere{center: vec{0, 0, -5}, radius: 1}
+	tests := []struct {
+		name   string
+		origin vec
+		dir    vec
+		want   float64
+		hit    bool
+	}{
+		{"head on", vec{}, vec{0, 0, -1}, 4, true},
+		{"from inside", vec{0, 0, -5}, vec{0, 0, -1}, 1, true},
+		{"behind", vec{}, vec{0, 0, 1}, 0, false},
+		{"miss", vec{}, vec{0, 1, 0}, 0, false},
+		// Leaving the surface: the near root is within epsilon, so the
+		// far side of the sphere is the hit.
+		{"from surface", vec{0, 0, -4}, vec{0, 0, -1}, 2, true},
+	}
+	for _, tt := range tests {
+		got, ok := s.intersect(tt.origin, tt.dir)
+		if ok != tt.hit || math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("%s: intersect = %v, %v; want %v, %v", tt.name, got, ok, tt.want, tt.hit)
+		}
+	}
+}
+
+func TestReflectionDepthCap(t *testing.T) {
+	// A perfect mirror in front of the camera: the ray reflects straight
+	// back into the sky behind the camera, unless the cap is reached, in
+	// which case only the mirror's own shading is left.
+	mirror := scene{
+		spheres: []sphere{{center: vec{0, 0, -3}, radius: 1, color: vec{0.5, 0.5, 0.5}, reflect: 1}},
+		light:   vec{0, 0, 10},
+		ambient: 0.1,
+	}
+	dir := vec{0, 0, -1}
+	if got, want := mirror.trace(vec{}, dir, 0), sky(vec{0, 0, 1}); got != want {
+		t.Errorf("reflected color = %v, want sky %v", got, want)
+	}
+	// The light is behind the camera, so the mirror point is lit fully.
+	want := vec{0.5, 0.5, 0.5}.scale(0.1).add(vec{0.5, 0.5, 0.5})
+	if got := mirror.trace(vec{}, dir, maxDepth); got != want {
+		t.Errorf("color at the depth cap = %v, want %v", got, want)
+	}
+}
+
+func TestExpectedChecksum(t *testing.T) {
+	if testing.Short() {
+		t.Skip("full render in short mode")
+	}
+	if got := checksum(render(&defaultScene, width, height, samples)); got != expectedChecksum {
+		t.Errorf("checksum = %d, want %d", got, expectedChecksum)
+	}
+}
@@ -0,0 +1,329 @@
+/*
+ * Red-Black Tree
+ *
+ * Insert 1,000,000 keys into a red-black tree, then look up 1,000,000
+ * more; RESULT is the number of lookups that find their key. Keys are
+ * r.Uint64() % 2,000,000 from benchlib.NewRand(benchlib.DefaultSeed),
+ * inserted keys first. Duplicate inserts leave the tree unchanged, so it
+ * ends up holding the 787,294 distinct keys. The tree is rebuilt from
+ * empty on every run.
+ * Expected result: 393598
+ *
+ * The tree follows CLRS (Introduction to Algorithms, ch. 13): nodes carry
+ * parent links, every leaf is one shared black sentinel, and insertion
+ * restores the invariants by recoloring and at most two rotations.
+ * Deletion is implemented, and tested, but not timed.
+ *
+ * This benchmark tests:
+ * - Pointer-heavy code with unpredictable branches
+ * - Tree rotations rewriting parent and child links
+ * - Allocation of a million small nodes
+ */
+
+package main
+
+import (
+	"flag"
+	"math/rand"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	inserts  = 1000000
+	lookups  = 1000000
+	keySpace = 2000000
+
+	expectedHits = 393598
+)
+
+// node is a tree node. Leaves are the tree's sentinel rather than nil.
+type node struct {
+	key                 uint32
+	red                 bool
+	left, right, parent *node
+}
+
+// tree is a red-black tree of distinct keys. Its invariants are:
+//   - every node is red or black, and the root and the leaves are black;
+//   - a red node has no red child;
+//   - every path from a node down to a leaf crosses the same number of
+//     black nodes.
+//
+// Together they keep the height below 2·log2(n+1).
+type tree struct {
+	root *node
+	// leaf is the black sentinel that stands for every nil child and the
+	// root's parent.
+	leaf *node
+	size int
+}
+
+func newTree() *tree {
+	leaf := &node{}
+	leaf.left, leaf.right, leaf.parent = leaf, leaf, leaf
+	return &tree{root: leaf, leaf: leaf}
+}
+
+// contains reports whether key is in t.
+func (t *tree) contains(key uint32) bool {
+	return t.find(key) != t.leaf
+}
+
+// find returns the node holding key, or t.leaf.
+func (t *tree) find(key uint32) *node {
+	x := t.root
+	for x != t.leaf && x.key != key {
+		if key < x.key {
+			x = x.left
+		} else {
+			x = x.right
+		}
+	}
+	return x
+}
+
+// rotateLeft turns x's right child y into x's parent.
+func (t *tree) rotateLeft(x *node) {
+	y := x.right
+	x.right = y.left
+	if y.left != t.leaf {
+		y.left.parent = x
+	}
+	t.replaceChild(x, y)
+	y.left = x
+	x.parent = y
+}
+
+// rotateRight turns x's left child y into x's parent.
+func (t *tree) rotateRight(x *node) {
+	y := x.left
+	x.left = y.right
+	if y.right != t.leaf {
+		y.right.parent = x
+	}
+	t.replaceChild(x, y)
+	y.right = x
+	x.parent = y
+}
+
+// replaceChild puts v where u hangs from u's parent (CLRS's transplant).
+func (t *tree) replaceChild(u, v *node) {
+	switch {
+	case u.parent == t.leaf:
+		t.root = v
+	case u == u.parent.left:
+		u.parent.left = v
+	default:
+		u.parent.right = v
+	}
+	v.parent = u.parent
+}
+
+// insert adds key to t and reports whether it was absent.
+func (t *tree) insert(key uint32) bool {
+	parent, x := t.leaf, t.root
+	for x != t.leaf {
+		parent = x
+		switch {
+		case key < x.key:
+			x = x.left
+		case key > x.key:
+			x = x.right
+		default:
+			return false
+		}
+	}
+	z := &node{key: key, red: true, left: t.leaf, right: t.leaf, parent: parent}
+	switch {
+	case parent == t.leaf:
+		t.root = z
+	case key < parent.key:
+		parent.left = z
+	default:
+		parent.right = z
+	}
+	t.size++
+	t.insertFixup(z)
+	return true
+}
+
+// insertFixup restores the invariants after the red node z was added.
+func (t *tree) insertFixup(z *node) {
+	for z.parent.red {
+		gp := z.parent.parent
+		if z.parent == gp.left {
+			if uncle := gp.right; uncle.red {
+				z.parent.red, uncle.red, gp.red = false, false, true
+				z = gp
+				continue
+			}
+			if z == z.parent.right {
+				z = z.parent
+				t.rotateLeft(z)
+			}
+			z.parent.red, gp.red = false, true
+			t.rotateRight(gp)
+		} else {
+			if uncle := gp.left; uncle.red {
+				z.parent.red, uncle.red, gp.red = false, false, true
+				z = gp
+				continue
+			}
+			if z == z.parent.left {
+				z = z.parent
+				t.rotateRight(z)
+			}
+			z.parent.red, gp.red = false, true
+			t.rotateLeft(gp)
+		}
+	}
+	t.root.red = false
+}
+
+// delete removes key from t and reports whether it was present.
+func (t *tree) delete(key uint32) bool {
+	z := t.find(key)
+	if z == t.leaf {
+		return false
+	}
+	t.size--
+
+	// y is the node actually unlinked: z itself, or z's successor when z
+	// has two children. x takes y's place.
+	y, yWasRed := z, z.red
+	var x *node
+	switch {
+	case z.left == t.leaf:
+		x = z.right
+		t.replaceChild(z, x)
+	case z.right == t.leaf:
+		x = z.left
+		t.replaceChild(z, x)
+	default:
+		y = z.right
+		for y.left != t.leaf {
+			y = y.left
+		}
+		yWasRed = y.red
+		x = y.right
+		if y.parent == z {
+			x.parent = y // x may be the sentinel
+		} else {
+			t.replaceChild(y, x)
+			y.right = z.right
+			y.right.parent = y
+		}
+		t.replaceChild(z, y)
+		y.left = z.left
+		y.left.parent = y
+		y.red = z.red
+	}
+	if !yWasRed {
+		t.deleteFixup(x)
+	}
+	return true
+}
+
+// deleteFixup restores the invariants after a black node was unlinked
+// above x, which carries an extra black until the loop pushes it to a red
+// node or the root.
+func (t *tree) deleteFixup(x *node) {
+	for x != t.root && !x.red {
+		if x == x.parent.left {
+			w := x.parent.right
+			if w.red {
+				w.red, x.parent.red = false, true
+				t.rotateLeft(x.parent)
+				w = x.parent.right
+			}
+			if !w.left.red && !w.right.red {
+				w.red = true
+				x = x.parent
+				continue
+			}
+			if !w.right.red {
+				w.left.red, w.red = false, true
+				t.rotateRight(w)
+				w = x.parent.right
+			}
+			w.red, x.parent.red, w.right.red = x.parent.red, false, false
+			t.rotateLeft(x.parent)
+			x = t.root
+		} else {
+			w := x.parent.left
+			if w.red {
+				w.red, x.parent.red = false, true
+				t.rotateRight(x.parent)
+				w = x.parent.left
+			}
+			if !w.right.red && !w.left.red {
+				w.red = true
+				x = x.parent
+				continue
+			}
+			if !w.left.red {
+				w.right.red, w.red = false, true
+				t.rotateLeft(w)
+				w = x.parent.left
+			}
+			w.red, x.parent.red, w.left.red = x.parent.red, false, false
+			t.rotateRight(x.parent)
+			x = t.root
+		}
+	}
+	x.red = false
+}
+
+// randomKeys returns n keys in [0, keySpace).
+func randomKeys(r *rand.Rand, n int) []uint32 {
+	keys := make([]uint32, n)
+	for i := range keys {
+		keys[i] = uint32(r.Uint64() % keySpace)
+	}
+	return keys
+}
+
+// countHits builds a tree of insertKeys and returns how many of
+// lookupKeys it contains.
+func countHits(insertKeys, lookupKeys []uint32) int64 {
+	t := newTree()
+	for _, k := range insertKeys {
+		t.insert(k)
+	}
+	var hits int64
+	for _, k := range lookupKeys {
+		if t.contains(k) {
+			hits++
+		}
+	}
+	return hits
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "rbtree", Category: benchlib.CategoryMemory, Expected: expectedHits})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	t0 := time.Now()
+
+	// Startup phase: draw the keys to insert and to look up
+	r := benchlib.NewRand(opts.Seed)
+	insertKeys := randomKeys(r, inserts)
+	lookupKeys := randomKeys(r, lookups)
+
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("rbtree", opts, startup, func() int64 {
+		return countHits(insertKeys, lookupKeys)
+	})
+
+	// Validate result
+	benchlib.ValidateSeed(opts.Seed, stats.Result, expectedHits)
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+// check verifies the red-black invariants, key order and parent links of
+// t, and that it holds exactly want.
+func check(t *testing.T, tr *tree, want map[uint32]bool) {
+	t.Helper()
+	if tr.root.red {
+		t.Fatal("root is red")
+	}
+	if tr.leaf.red {
+		t.Fatal("sentinel is red")
+	}
+	var keys []uint32
+	var walk func(n *node) (blackHeight int, err error)
+	walk = func(n *node) (int, error) {
+		if n == tr.leaf {
+			return 1, nil
+		}
+		for _, c := range []*node{n.left, n.right} {
+			if c == tr.leaf {
+				continue
+			}
+			if c.parent != n {
+				return 0, fmt.Errorf("node %d: child %d has the wrong parent", n.key, c.key)
+			}
+			if n.red && c.red {
+				return 0, fmt.Errorf("red node %d has red child %d", n.key, c.key)
+			}
+		}
+		lh, err := walk(n.left)
+		if err != nil {
+			return 0, err
+		}
+		keys = append(keys, n.key)
+		rh, err := walk(n.right)
+		if err != nil {
+			return 0, err
+		}
+		if lh != rh {
+			return 0, fmt.Errorf("node %d: black heights %d left, %d right", n.key, lh, rh)
+		}
+		if !n.red {
+			lh++
+		}
+		return lh, nil
+	}
+	if _, err := walk(tr.root); err != nil {
+		t.Fatal(err)
+	}
+	if tr.root != tr.leaf && tr.root.parent != tr.leaf {
+		t.Fatal("root has a parent")
+	}
+
+	if !slices.IsSorted(keys) {
+		t.Fatalf("in-order keys not sorted: %v", keys)
+	}
+	if len(keys) != len(want) || tr.size != len(want) {
+		t.Fatalf("tree has %d keys (size %d), want %d", len(keys), tr.size, len(want))
+	}
+	for _, k := range keys {
+		if !want[k] {
+			t.Fatalf("tree holds %d, which it should not", k)
+		}
+	}
+}
+
+func TestInsertKeepsInvariants(t *testing.T) {
+	tr := newTree()
+	want := make(map[uint32]bool)
+	// Ascending keys force the most rotations.
+	for k := uint32(0); k < 1000; k++ {
+		if !tr.insert(k) {
+			t.Fatalf("insert(%d) reported a duplicate", k)
+		}
+		want[k] = true
+	}
+	check(t, tr, want)
+	if tr.insert(500) {
+		t.Error("insert of a present key reported it absent")
+	}
+}
+
+func TestRandomInsertsAndDeletes(t *testing.T) {
+	r := benchlib.NewRand(1)
+	tr := newTree()
+	want := make(map[uint32]bool)
+	for i := 0; i < 20000; i++ {
+		k := uint32(r.Uint64() % 500)
+		if r.Uint64()%3 == 0 {
+			if got := tr.delete(k); got != want[k] {
+				t.Fatalf("step %d: delete(%d) = %v, want %v", i, k, got, want[k])
+			}
+			delete(want, k)
+		} else {
+			if got := tr.insert(k); got == want[k] {
+				t.Fatalf("step %d: insert(%d) = %v, want %v", i, k, got, !want[k])
+			}
+			want[k] = true
+		}
+		if i%97 == 0 {
+			check(t, tr, want)
+		}
+	}
+	check(t, tr, want)
+
+	for k := uint32(0); k < 500; k++ {
+		if tr.contains(k) != want[k] {
+			t.Errorf("contains(%d) = %v, want %v", k, !want[k], want[k])
+		}
+	}
+	// Draining the tree leaves it empty and valid at every step.
+	for k := uint32(0); k < 500; k++ {
+		tr.delete(k)
+		delete(want, k)
+		check(t, tr, want)
+	}
+	if tr.root != tr.leaf {
+		t.Error("empty tree has a root")
+	}
+}
+
+func TestCountHits(t *testing.T) {
+	if got := countHits([]uint32{5, 1, 9, 1}, []uint32{1, 2, 9, 9, 10}); got != 3 {
+		t.Errorf("countHits = %d, want 3", got)
+	}
+}
@@ -0,0 +1,92 @@
+/*
+ * Regex Matching
+ *
+ * Count the non-overlapping matches of
+ *
+ *	\b[aeiou][a-z]*[st]\b
+ *
+ * (a whole word that starts with a vowel and ends in s or t) in a text of
+ * 1,000,000 lowercase words separated by single spaces. Each word is 1-8
+ * letters: one draw from benchlib.NewRand(benchlib.DefaultSeed) for the
+ * length, then one per letter. The pattern is compiled with Go's regexp
+ * package in the startup phase; RESULT is the number of matches found by
+ * FindAllIndex.
+ * Expected result: 12947
+ *
+ * The greedy [a-z]* runs to the end of every vowel-initial word and must
+ * give back letters to find the final [st]; an RE2-style engine such as
+ * Go's does this without backtracking, a backtracking engine by retrying.
+ * With 26 letters about one word in 77 matches.
+ *
+ * This benchmark tests:
+ * - Regex engine throughput on a large text
+ * - Character-class and word-boundary evaluation
+ */
+
+package main
+
+import (
+	"flag"
+	"math/rand"
+	"regexp"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	pattern  = `\b[aeiou][a-z]*[st]\b`
+	numWords = 1000000
+	// maxWordLen bounds word lengths to [1, maxWordLen].
+	maxWordLen = 8
+
+	expectedMatches = 12947
+)
+
+// randomText returns n words of random lowercase letters separated by
+// single spaces.
+func randomText(r *rand.Rand, n int) []byte {
+	text := make([]byte, 0, n*(maxWordLen/2+2))
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			text = append(text, ' ')
+		}
+		length := 1 + int(r.Uint64()%maxWordLen)
+		for j := 0; j < length; j++ {
+			text = append(text, 'a'+byte(r.Uint64()%26))
+		}
+	}
+	return text
+}
+
+// countMatches returns the number of non-overlapping matches of re in
+// text.
+func countMatches(re *regexp.Regexp, text []byte) int {
+	return len(re.FindAllIndex(text, -1))
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "regex", Category: benchlib.CategoryText, Expected: expectedMatches})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	t0 := time.Now()
+
+	// Startup phase: compile the pattern and generate the text
+	re := regexp.MustCompile(pattern)
+	text := randomText(benchlib.NewRand(opts.Seed), numWords)
+
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("regex", opts, startup, func() int64 {
+		return int64(countMatches(re, text))
+	})
+
+	// Validate result
+	benchlib.ValidateSeed(opts.Seed, stats.Result, expectedMatches)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+func TestCountMatchesSmallText(t *testing.T) {
+	re := regexp.MustCompile(pattern)
+	text := "it is an east wind at sunset so ants eat oats ask us"
+	// "an" and "ask" start with a vowel but end in neither s nor t;
+	// "sunset" and "so" end right but start with a consonant.
+	want := []string{"it", "is", "east", "at", "ants", "eat", "oats", "us"}
+	if got := re.FindAllString(text, -1); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("FindAllString = %q, want %q", got, want)
+	}
+	if got := countMatches(re, []byte(text)); got != len(want) {
+		t.Errorf("countMatches = %d, want %d", got, len(want))
+	}
+}
+
+func TestCountMatchesAgreesWithFindAllString(t *testing.T) {
+	re := regexp.MustCompile(pattern)
+	text := randomText(benchlib.NewRand(1), 5000)
+	if got, want := countMatches(re, text), len(re.FindAllString(string(text), -1)); got != want {
+		t.Errorf("countMatches = %d, FindAllString found %d", got, want)
+	}
+
+	// The same count from the definition: whole words of two or more
+	// letters that start with a vowel and end in s or t.
+	var byWord int
+	for _, w := range strings.Fields(string(text)) {
+		if len(w) >= 2 && strings.ContainsRune("aeiou", rune(w[0])) && strings.ContainsRune("st", rune(w[len(w)-1])) {
+			byWord++
+		}
+	}
+	if got := countMatches(re, text); got != byWord {
+		t.Errorf("countMatches = %d, want %d matching words", got, byWord)
+	}
+}
+
+func TestRandomText(t *testing.T) {
+	text := string(randomText(benchlib.NewRand(benchlib.DefaultSeed), 1000))
+	words := strings.Split(text, " ")
+	if len(words) != 1000 {
+		t.Fatalf("got %d words, want 1000", len(words))
+	}
+	for _, w := range words {
+		if len(w) < 1 || len(w) > maxWordLen || strings.Trim(w, "abcdefghijklmnopqrstuvwxyz") != "" {
+			t.Fatalf("bad word %q", w)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+/*
+ * SHA-256 Throughput
+ *
+ * Hash 64 MiB with a pure-Go SHA-256: a 1 MiB deterministic buffer
+ * (benchlib.RandomBytes seeded with benchlib.DefaultSeed) is streamed into
+ * one hash state 64 times. The implementation is portable scalar code, not
+ * crypto/sha256, whose assembly would not be a fair cross-language baseline.
+ * With another --seed there is no expected value, and RESULT is checked
+ * against crypto/sha256 instead.
+ * Expected result: low 8 bytes of the digest, 0xa98128c542f337ac
+ * (printed as the signed int64 -6232655581607151700)
+ *
+ * This benchmark tests:
+ * - 32-bit rotates, shifts and xors
+ * - Sequential streaming over a buffer larger than L2
+ */
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"flag"
+	"math/bits"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	bufferSize = 1 << 20
+	rounds     = 64
+
+	// expectedLow64 is the low half of
+	// 06c4d13518d14fc1bda7548913eecbb4c89814f78a341dd5a98128c542f337ac,
+	// the crypto/sha256 digest of the same 64 MiB.
+	expectedLow64 uint64 = 0xa98128c542f337ac
+)
+
+const (
+	blockSize = 64
+	digestLen = 32
+)
+
+var k = [64]uint32{
+	0x428a2f98, 0x71374491, 0xb5c0fbcf, 0xe9b5dba5, 0x3956c25b, 0x59f111f1, 0x923f82a4, 0xab1c5ed5,
+	0xd807aa98, 0x12835b01, 0x243185be, 0x550c7dc3, 0x72be5d74, 0x80deb1fe, 0x9bdc06a7, 0xc19bf174,
+	0xe49b69c1, 0xefbe4786, 0x0fc19dc6, 0x240ca1cc, 0x2de92c6f, 0x4a7484aa, 0x5cb0a9dc, 0x76f988da,
+	0x983e5152, 0xa831c66d, 0xb00327c8, 0xbf597fc7, 0xc6e00bf3, 0xd5a79147, 0x06ca6351, 0x14292967,
+	0x27b70a85, 0x2e1b2138, 0x4d2c6dfc, 0x53380d13, 0x650a7354, 0x766a0abb, 0x81c2c92e, 0x92722c85,
+	0xa2bfe8a1, 0xa81a664b, 0xc24b8b70, 0xc76c51a3, 0xd192e819, 0xd6990624, 0xf40e3585, 0x106aa070,
+	0x19a4c116, 0x1e376c08, 0x2748774c, 0x34b0bcb5, 0x391c0cb3, 0x4ed8aa4a, 0x5b9cca4f, 0x682e6ff3,
+	0x748f82ee, 0x78a5636f, 0x84c87814, 0x8cc70208, 0x90befffa, 0xa4506ceb, 0xbef9a3f7, 0xc67178f2,
+}
+
+// digest is a streaming SHA-256 state (FIPS 180-4).
+type digest struct {
+	h   [8]uint32
+	buf [blockSize]byte
+	nx  int
+	len uint64
+}
+
+func newDigest() *digest {
+	return &digest{h: [8]uint32{
+		0x6a09e667, 0xbb67ae85, 0x3c6ef372, 0xa54ff53a,
+		0x510e527f, 0x9b05688c, 0x1f83d9ab, 0x5be0cd19,
+	}}
+}
+
+// Write absorbs p into the hash state.
+func (d *digest) Write(p []byte) {
+	d.len += uint64(len(p))
+	if d.nx > 0 {
+		n := copy(d.buf[d.nx:], p)
+		d.nx += n
+		p = p[n:]
+		if d.nx < blockSize {
+			return
+		}
+		d.block(d.buf[:])
+		d.nx = 0
+	}
+	for len(p) >= blockSize {
+		d.block(p[:blockSize])
+		p = p[blockSize:]
+	}
+	d.nx = copy(d.buf[:], p)
+}
+
+// Sum pads the message and returns the final digest. d must not be used
+// afterwards.
+func (d *digest) Sum() [digestLen]byte {
+	bitLen := d.len * 8
+	// 0x80, then zeros until the length is 56 mod 64, then the bit length.
+	var pad [blockSize + 8]byte
+	pad[0] = 0x80
+	padLen := 1 + (55-d.nx+blockSize)%blockSize
+	binary.BigEndian.PutUint64(pad[padLen:], bitLen)
+	d.Write(pad[:padLen+8])
+
+	var out [digestLen]byte
+	for i, v := range d.h {
+		binary.BigEndian.PutUint32(out[i*4:], v)
+	}
+	return out
+}
+
+// block runs the compression function over one 64-byte block.
+func (d *digest) block(p []byte) {
+	var w [64]uint32
+	for i := 0; i < 16; i++ {
+		w[i] = binary.BigEndian.Uint32(p[i*4:])
+	}
+	for i := 16; i < 64; i++ {
+		s0 := bits.RotateLeft32(w[i-15], -7) ^ bits.RotateLeft32(w[i-15], -18) ^ (w[i-15] >> 3)
+		s1 := bits.RotateLeft32(w[i-2], -17) ^ bits.RotateLeft32(w[i-2], -19) ^ (w[i-2] >> 10)
+		w[i] = w[i-16] + s0 + w[i-7] + s1
+	}
+
+	a, b, c, dd, e, f, g, h := d.h[0], d.h[1], d.h[2], d.h[3], d.h[4], d.h[5], d.h[6], d.h[7]
+	for i := 0; i < 64; i++ {
+		s1 := bits.RotateLeft32(e, -6) ^ bits.RotateLeft32(e, -11) ^ bits.RotateLeft32(e, -25)
+		ch := (e & f) ^ (^e & g)
+		t1 := h + s1 + ch + k[i] + w[i]
+		s0 := bits.RotateLeft32(a, -2) ^ bits.RotateLeft32(a, -13) ^ bits.RotateLeft32(a, -22)
+		maj := (a & b) ^ (a & c) ^ (b & c)
+		t2 := s0 + maj
+		h, g, f, e, dd, c, b, a = g, f, e, dd+t1, c, b, a, t1+t2
+	}
+	d.h[0] += a
+	d.h[1] += b
+	d.h[2] += c
+	d.h[3] += dd
+	d.h[4] += e
+	d.h[5] += f
+	d.h[6] += g
+	d.h[7] += h
+}
+
+// hashRepeated returns the SHA-256 of buf concatenated with itself n times.
+func hashRepeated(buf []byte, n int) [digestLen]byte {
+	d := newDigest()
+	for i := 0; i < n; i++ {
+		d.Write(buf)
+	}
+	return d.Sum()
+}
+
+// low64 interprets the last eight digest bytes as a big-endian integer.
+func low64(sum [digestLen]byte) int64 {
+	return int64(binary.BigEndian.Uint64(sum[digestLen-8:]))
+}
+
+// stdlibLow64 returns low64 of the SHA-256 of buf concatenated with
+// itself n times as computed by crypto/sha256, the reference for
+// hashRepeated.
+func stdlibLow64(buf []byte, n int) int64 {
+	h := sha256.New()
+	for i := 0; i < n; i++ {
+		h.Write(buf)
+	}
+	return low64([digestLen]byte(h.Sum(nil)))
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "sha256", Category: benchlib.CategoryNumeric, Expected: -6232655581607151700})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	t0 := time.Now()
+
+	// Startup phase: build the input buffer so compute only times hashing
+	buf := benchlib.RandomBytes(benchlib.NewRand(opts.Seed), bufferSize)
+
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("sha256", opts, startup, func() int64 {
+		return low64(hashRepeated(buf, rounds))
+	})
+
+	// Validate result
+	if opts.Seed != benchlib.DefaultSeed {
+		if want := stdlibLow64(buf, rounds); stats.Result != want {
+			benchlib.Failf("crypto/sha256 gives %d, got %d", want, stats.Result)
+		}
+	}
+	// RESULT is signed; compare against the constant's bit pattern.
+	want := expectedLow64
+	benchlib.ValidateSeed(opts.Seed, stats.Result, int64(want))
+}
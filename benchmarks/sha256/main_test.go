@@ -0,0 +1,52 @@
+package main
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+func TestDigestMatchesStdlib(t *testing.T) {
+	// Lengths straddle the 55/56-byte padding boundary and block edges.
+	data := benchlib.RandomBytes(benchlib.NewRand(1), 1000)
+	for _, n := range []int{0, 1, 3, 55, 56, 57, 63, 64, 65, 119, 120, 128, 1000} {
+		d := newDigest()
+		d.Write(data[:n])
+		if got, want := d.Sum(), sha256.Sum256(data[:n]); got != want {
+			t.Errorf("len %d: digest = %x, want %x", n, got, want)
+		}
+	}
+}
+
+func TestDigestKnownVector(t *testing.T) {
+	d := newDigest()
+	d.Write([]byte("abc"))
+	got := d.Sum()
+	want := sha256.Sum256([]byte("abc"))
+	if got != want {
+		t.Errorf(`digest("abc") = %x, want %x`, got, want)
+	}
+}
+
+func TestHashRepeatedMatchesStdlib(t *testing.T) {
+	// Odd-length chunks force writes that straddle block boundaries.
+	buf := benchlib.RandomBytes(benchlib.NewRand(2), 4099)
+	h := sha256.New()
+	for i := 0; i < 5; i++ {
+		h.Write(buf)
+	}
+	var want [digestLen]byte
+	copy(want[:], h.Sum(nil))
+	if got := hashRepeated(buf, 5); got != want {
+		t.Errorf("hashRepeated = %x, want %x", got, want)
+	}
+}
+
+func TestStdlibLow64(t *testing.T) {
+	// The check main makes under --seed=7, on a smaller buffer.
+	buf := benchlib.RandomBytes(benchlib.NewRand(7), 4099)
+	if got, want := low64(hashRepeated(buf, 5)), stdlibLow64(buf, 5); got != want {
+		t.Errorf("low64 = %d, crypto/sha256 gives %d", got, want)
+	}
+}
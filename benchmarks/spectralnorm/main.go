@@ -0,0 +1,119 @@
+/*
+ * Spectral Norm
+ *
+ * The Computer Language Benchmarks Game spectral-norm workload: estimate
+ * the spectral norm (largest singular value) of the infinite matrix
+ * A(i,j) = 1/((i+j)(i+j+1)/2 + i+1), truncated to N×N with N = 5500, by
+ * ten rounds of power iteration on AᵀA starting from the all-ones vector.
+ * The published reference output for N = 5500 is 1.274224153.
+ * Expected result: 1274224153 (norm × 1e9, rounded)
+ *
+ * This benchmark tests:
+ * - Floating-point multiply, divide and accumulate
+ * - Streaming over vectors that fit in cache
+ */
+
+package main
+
+import (
+	"flag"
+	"math"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	n = 5500
+
+	// powerSteps is the number of u → AᵀAu → AᵀA(AᵀAu) rounds.
+	powerSteps = 10
+
+	// referenceNorm is the Benchmarks Game output for n, printed to nine
+	// decimal places; normTolerance allows for that rounding.
+	referenceNorm = 1.274224153
+	normTolerance = 1e-9
+
+	// normScale converts the norm to the integer RESULT.
+	normScale = 1e9
+)
+
+// a returns the (i, j) entry of the matrix, with indices from zero.
+func a(i, j int) float64 {
+	return 1 / float64((i+j)*(i+j+1)/2+i+1)
+}
+
+// multiplyAv sets out = A·v.
+func multiplyAv(v, out []float64) {
+	for i := range out {
+		var sum float64
+		for j, x := range v {
+			sum += a(i, j) * x
+		}
+		out[i] = sum
+	}
+}
+
+// multiplyAtv sets out = Aᵀ·v.
+func multiplyAtv(v, out []float64) {
+	for i := range out {
+		var sum float64
+		for j, x := range v {
+			sum += a(j, i) * x
+		}
+		out[i] = sum
+	}
+}
+
+// multiplyAtAv sets out = AᵀA·v, using tmp as scratch.
+func multiplyAtAv(v, out, tmp []float64) {
+	multiplyAv(v, tmp)
+	multiplyAtv(tmp, out)
+}
+
+// spectralNorm returns the power-iteration estimate of A's spectral norm
+// for the size×size truncation: sqrt(u·v / v·v) after the final round.
+func spectralNorm(size int) float64 {
+	u := make([]float64, size)
+	v := make([]float64, size)
+	tmp := make([]float64, size)
+	for i := range u {
+		u[i] = 1
+	}
+	for i := 0; i < powerSteps; i++ {
+		multiplyAtAv(u, v, tmp)
+		multiplyAtAv(v, u, tmp)
+	}
+	var uv, vv float64
+	for i := range u {
+		uv += u[i] * v[i]
+		vv += v[i] * v[i]
+	}
+	return math.Sqrt(uv / vv)
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "spectralnorm", Category: benchlib.CategoryNumeric, Expected: 1274224153})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	t0 := time.Now()
+	startup := time.Since(t0)
+
+	// Compute benchmark. Only the scaled norm crosses the int64 RESULT
+	// boundary, so the unscaled value is kept for validation.
+	var norm float64
+	stats := benchlib.Run("spectralnorm", opts, startup, func() int64 {
+		norm = spectralNorm(n)
+		return int64(math.Round(norm * normScale))
+	})
+
+	// Validate result
+	if math.Abs(norm-referenceNorm) > normTolerance {
+		benchlib.Failf("expected norm %.9f got %.9f (RESULT %d)", referenceNorm, norm, stats.Result)
+	}
+}
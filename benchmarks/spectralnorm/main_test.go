@@ -0,0 +1,56 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSpectralNormReferenceValues(t *testing.T) {
+	tests := []struct {
+		size int
+		want float64
+	}{
+		// A 1×1 truncation is the matrix [1].
+		{1, 1},
+		// Benchmarks Game reference output for N = 100.
+		{100, 1.274219991},
+	}
+	for _, tt := range tests {
+		if got := spectralNorm(tt.size); math.Abs(got-tt.want) > normTolerance {
+			t.Errorf("spectralNorm(%d) = %.9f, want %.9f", tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestMatrixEntries(t *testing.T) {
+	// The first row and column of A: 1, 1/2, 1/4, ... and 1, 1/3, 1/6, ...
+	for _, tt := range []struct {
+		i, j int
+		want float64
+	}{
+		{0, 0, 1}, {0, 1, 1.0 / 2}, {1, 0, 1.0 / 3}, {0, 2, 1.0 / 4}, {2, 0, 1.0 / 6}, {1, 1, 1.0 / 5},
+	} {
+		if got := a(tt.i, tt.j); got != tt.want {
+			t.Errorf("a(%d, %d) = %v, want %v", tt.i, tt.j, got, tt.want)
+		}
+	}
+}
+
+func TestMultiplyAtvIsTranspose(t *testing.T) {
+	// eⱼ picks out a column of A and, through Aᵀ, a row.
+	const size = 4
+	for j := 0; j < size; j++ {
+		e := make([]float64, size)
+		e[j] = 1
+		col := make([]float64, size)
+		row := make([]float64, size)
+		multiplyAv(e, col)
+		multiplyAtv(e, row)
+		for i := 0; i < size; i++ {
+			if col[i] != a(i, j) || row[i] != a(j, i) {
+				t.Errorf("column %d: A·e[%d] = %v, Aᵀ·e[%d] = %v; want %v, %v",
+					j, i, col[i], i, row[i], a(i, j), a(j, i))
+			}
+		}
+	}
+}
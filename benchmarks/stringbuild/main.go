@@ -0,0 +1,135 @@
+/*
+ * String Building
+ *
+ * Assemble one string from N = 1,000,000 pieces, the decimal numbers 0 to
+ * N−1 each followed by ';' ("0;1;2;...;999999;"), either with strings.Builder
+ * (--mode=builder, the default) or by repeated s += piece (--mode=concat).
+ * The pieces are formatted in the startup phase, so compute times only the
+ * assembly. RESULT is the length of the final string, 6,888,890 bytes.
+ * Expected result: 6888890
+ *
+ * Builder appends into a buffer that grows geometrically, so assembly is
+ * linear. Concatenation copies the whole string so far on every piece:
+ * about L²/(2·6.9) bytes for a final length L, some 3.4 TB at the default
+ * size. That is the point of the mode, but it does not finish in useful
+ * time; use --pieces (e.g. 20000, then 40000) to watch the time grow at
+ * least fourfold as N doubles. RESULT for other piece counts is checked
+ * against the closed form.
+ *
+ * This benchmark tests:
+ * - Amortized buffer growth and byte copying
+ * - Allocation rate and GC pressure (concat mode)
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	defaultPieces = 1000000
+	separator     = ";"
+
+	expectedLength = 6888890
+)
+
+// Assembly modes accepted by --mode.
+const (
+	modeBuilder = "builder"
+	modeConcat  = "concat"
+)
+
+// makePieces returns the n pieces "0;", "1;", ..., "n−1;".
+func makePieces(n int) []string {
+	pieces := make([]string, n)
+	for i := range pieces {
+		pieces[i] = strconv.Itoa(i) + separator
+	}
+	return pieces
+}
+
+// build joins pieces with a strings.Builder.
+func build(pieces []string) string {
+	var b strings.Builder
+	for _, p := range pieces {
+		b.WriteString(p)
+	}
+	return b.String()
+}
+
+// concat joins pieces by repeated concatenation.
+func concat(pieces []string) string {
+	s := ""
+	for _, p := range pieces {
+		s += p
+	}
+	return s
+}
+
+// totalLength returns the length of the assembled string for n pieces: the
+// number of decimal digits in 0..n−1 plus one separator per piece.
+func totalLength(n int) int64 {
+	total := int64(n) * int64(len(separator))
+	if n > 0 {
+		total++ // the single digit of 0
+	}
+	// Numbers in [lo, hi) have d digits.
+	for d, lo := int64(1), int64(1); lo < int64(n); d, lo = d+1, lo*10 {
+		hi := min(lo*10, int64(n))
+		total += (hi - lo) * d
+	}
+	return total
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "stringbuild", Category: benchlib.CategoryText, Expected: expectedLength})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	mode := flag.String("mode", modeBuilder, "assembly method: builder or concat")
+	n := flag.Int("pieces", defaultPieces, "number of pieces to assemble")
+	flag.Parse()
+	var assemble func([]string) string
+	switch *mode {
+	case modeBuilder:
+		assemble = build
+	case modeConcat:
+		assemble = concat
+	default:
+		fmt.Fprintf(os.Stderr, "stringbuild: --mode must be builder or concat, got %q\n", *mode)
+		os.Exit(2)
+	}
+	if *n < 1 {
+		fmt.Fprintf(os.Stderr, "stringbuild: --pieces must be >= 1, got %d\n", *n)
+		os.Exit(2)
+	}
+
+	t0 := time.Now()
+
+	// Startup phase: format the pieces
+	pieces := makePieces(*n)
+
+	startup := time.Since(t0)
+
+	if opts.Format == benchlib.FormatText {
+		fmt.Printf("MODE: %s\n", *mode)
+	}
+
+	// Compute benchmark
+	stats := benchlib.Run("stringbuild", opts, startup, func() int64 {
+		return int64(len(assemble(pieces)))
+	})
+
+	// Validate result
+	benchlib.Validate(stats.Result, totalLength(*n))
+}
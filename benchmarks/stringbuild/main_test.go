@@ -0,0 +1,37 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestModesAgree(t *testing.T) {
+	for _, n := range []int{1, 2, 10, 11, 101, 2500} {
+		pieces := makePieces(n)
+		b, c := build(pieces), concat(pieces)
+		if b != c {
+			t.Fatalf("n=%d: builder and concat differ", n)
+		}
+		if want := strings.Join(pieces, ""); b != want {
+			t.Fatalf("n=%d: assembled %q, want %q", n, b, want)
+		}
+		if got := totalLength(n); got != int64(len(b)) {
+			t.Errorf("totalLength(%d) = %d, want %d", n, got, len(b))
+		}
+	}
+	if got := build(makePieces(12)); got != "0;1;2;3;4;5;6;7;8;9;10;11;" {
+		t.Errorf("build(12 pieces) = %q", got)
+	}
+}
+
+func TestTotalLengthDefault(t *testing.T) {
+	if got := totalLength(defaultPieces); got != expectedLength {
+		t.Errorf("totalLength(%d) = %d, want %d", defaultPieces, got, expectedLength)
+	}
+	// Powers of ten are the digit-count boundaries.
+	for _, n := range []int{9, 10, 99, 100, 1000, 1001} {
+		if got, want := totalLength(n), int64(len(build(makePieces(n)))); got != want {
+			t.Errorf("totalLength(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+/*
+ * Sudoku Solver
+ *
+ * Solve Arto Inkala's "world's hardest Sudoku" --solves times (default 200)
+ * with depth-first backtracking. Candidates for each cell are kept as
+ * row, column and box bitmasks, and the search always branches on the
+ * empty cell with the fewest candidates. Every solution is checked against
+ * the Sudoku rules and the puzzle's givens. RESULT is the solved first row
+ * read as a nine-digit number.
+ * Expected result: 812753649
+ *
+ * This benchmark tests:
+ * - Recursive backtracking with undo
+ * - Bit manipulation (masks, popcount, lowest set bit)
+ * - Data-dependent branching
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/bits"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	// puzzle is read row by row; '.' is an empty cell.
+	puzzle = "8........" +
+		"..36....." +
+		".7..9.2.." +
+		".5...7..." +
+		"....457.." +
+		"...1...3." +
+		"..1....68" +
+		"..85...1." +
+		".9....4.."
+
+	expectedFirstRow = 812753649
+
+	// allDigits has bits 1..9 set; bit d stands for digit d.
+	allDigits = 0x3fe
+)
+
+// grid holds the digits 1-9 in row-major order, 0 for an empty cell.
+type grid [81]uint8
+
+// parseGrid reads 81 cells, digits or '.'/'0' for empty.
+func parseGrid(s string) (grid, error) {
+	var g grid
+	if len(s) != 81 {
+		return g, fmt.Errorf("want 81 cells, got %d", len(s))
+	}
+	for i := 0; i < 81; i++ {
+		switch c := s[i]; {
+		case c == '.' || c == '0':
+		case c >= '1' && c <= '9':
+			g[i] = c - '0'
+		default:
+			return g, fmt.Errorf("cell %d: invalid character %q", i, c)
+		}
+	}
+	return g, nil
+}
+
+func (g grid) String() string {
+	var b strings.Builder
+	for _, d := range g {
+		b.WriteByte('0' + d)
+	}
+	return b.String()
+}
+
+func boxOf(i int) int { return i/27*3 + i%9/3 }
+
+// solver is the search state: the grid plus the digits already used in each
+// row, column and 3×3 box.
+type solver struct {
+	g                grid
+	rows, cols, boxs [9]uint16
+}
+
+// newSolver loads g, reporting false if its givens already conflict.
+func newSolver(g grid) (*solver, bool) {
+	s := &solver{}
+	for i, d := range g {
+		if d == 0 {
+			continue
+		}
+		bit := uint16(1) << d
+		r, c, b := i/9, i%9, boxOf(i)
+		if (s.rows[r]|s.cols[c]|s.boxs[b])&bit != 0 {
+			return nil, false
+		}
+		s.place(i, d)
+	}
+	return s, true
+}
+
+func (s *solver) place(i int, d uint8) {
+	bit := uint16(1) << d
+	s.g[i] = d
+	s.rows[i/9] |= bit
+	s.cols[i%9] |= bit
+	s.boxs[boxOf(i)] |= bit
+}
+
+func (s *solver) clear(i int) {
+	bit := uint16(1) << s.g[i]
+	s.g[i] = 0
+	s.rows[i/9] &^= bit
+	s.cols[i%9] &^= bit
+	s.boxs[boxOf(i)] &^= bit
+}
+
+func (s *solver) candidates(i int) uint16 {
+	return allDigits &^ (s.rows[i/9] | s.cols[i%9] | s.boxs[boxOf(i)])
+}
+
+// search counts solutions, stopping once limit are found, and returns the
+// number found. When it stops at limit the grid holds the last solution
+// found, so search(1) leaves the first solution in place.
+func (s *solver) search(limit int) int {
+	// Branch on the empty cell with the fewest candidates.
+	best, bestCount := -1, 10
+	var bestMask uint16
+	for i, d := range s.g {
+		if d != 0 {
+			continue
+		}
+		m := s.candidates(i)
+		if n := bits.OnesCount16(m); n < bestCount {
+			best, bestCount, bestMask = i, n, m
+			if n <= 1 {
+				break
+			}
+		}
+	}
+	if best < 0 {
+		return 1 // no empty cell left: solved
+	}
+
+	found := 0
+	for m := bestMask; m != 0; m &= m - 1 {
+		s.place(best, uint8(bits.TrailingZeros16(m)))
+		found += s.search(limit - found)
+		if found >= limit {
+			return found
+		}
+		s.clear(best)
+	}
+	return found
+}
+
+// solve returns the first solution of g and whether one exists.
+func solve(g grid) (grid, bool) {
+	s, ok := newSolver(g)
+	if !ok || s.search(1) == 0 {
+		return grid{}, false
+	}
+	return s.g, true
+}
+
+// countSolutions returns the number of solutions of g, counting at most
+// limit of them.
+func countSolutions(g grid, limit int) int {
+	s, ok := newSolver(g)
+	if !ok {
+		return 0
+	}
+	return s.search(limit)
+}
+
+// isValidSolution reports whether sol is completely filled, obeys the
+// Sudoku rules, and agrees with every given of puzzle.
+func isValidSolution(puzzle, sol grid) bool {
+	var rows, cols, boxs [9]uint16
+	for i, d := range sol {
+		if d < 1 || d > 9 || (puzzle[i] != 0 && puzzle[i] != d) {
+			return false
+		}
+		bit := uint16(1) << d
+		rows[i/9] |= bit
+		cols[i%9] |= bit
+		boxs[boxOf(i)] |= bit
+	}
+	for k := 0; k < 9; k++ {
+		if rows[k] != allDigits || cols[k] != allDigits || boxs[k] != allDigits {
+			return false
+		}
+	}
+	return true
+}
+
+// firstRow reads the first row of g as a nine-digit number.
+func firstRow(g grid) int64 {
+	var n int64
+	for _, d := range g[:9] {
+		n = n*10 + int64(d)
+	}
+	return n
+}
+
+// solveRepeatedly solves p times times, checking every solution, and
+// returns the first-row checksum. It fails the benchmark on an invalid or
+// missing solution.
+func solveRepeatedly(p grid, times int) int64 {
+	var checksum int64
+	for i := 0; i < times; i++ {
+		sol, ok := solve(p)
+		if !ok {
+			benchlib.Failf("sudoku: puzzle has no solution")
+		}
+		if !isValidSolution(p, sol) {
+			benchlib.Failf("sudoku: invalid solution %s", sol)
+		}
+		checksum = firstRow(sol)
+	}
+	return checksum
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "sudoku", Category: benchlib.CategoryRecursion, Expected: expectedFirstRow})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	solves := flag.Int("solves", 200, "number of times the puzzle is solved per run")
+	flag.Parse()
+	if *solves < 1 {
+		fmt.Fprintf(os.Stderr, "sudoku: --solves must be >= 1, got %d\n", *solves)
+		os.Exit(2)
+	}
+
+	t0 := time.Now()
+
+	// Startup phase: parse the puzzle
+	p := benchlib.Must(parseGrid(puzzle))
+
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("sudoku", opts, startup, func() int64 {
+		return solveRepeatedly(p, *solves)
+	})
+
+	// Validate result
+	benchlib.Validate(stats.Result, expectedFirstRow)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func mustParse(t *testing.T, s string) grid {
+	t.Helper()
+	g, err := parseGrid(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestSolveKnownPuzzles(t *testing.T) {
+	tests := []struct {
+		name, puzzle, solution string
+	}{
+		{
+			name:     "Inkala hardest",
+			puzzle:   puzzle,
+			solution: "812753649943682175675491283154237896369845721287169534521974368438526917796318452",
+		},
+		{
+			name:     "easy",
+			puzzle:   "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79",
+			solution: "534678912672195348198342567859761423426853791713924856961537284287419635345286179",
+		},
+		{
+			// Norvig's hard1: 17 givens, unique solution, deep search.
+			name:     "17 givens",
+			puzzle:   "4.....8.5.3..........7......2.....6.....8.4......1.......6.3.7.5..2.....1.4......",
+			solution: "417369825632158947958724316825437169791586432346912758289643571573291684164875293",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := mustParse(t, tt.puzzle)
+			sol, ok := solve(p)
+			if !ok {
+				t.Fatal("no solution found")
+			}
+			if sol.String() != tt.solution {
+				t.Errorf("solution = %s, want %s", sol, tt.solution)
+			}
+			if !isValidSolution(p, sol) {
+				t.Error("solution fails validation")
+			}
+			if n := countSolutions(p, 2); n != 1 {
+				t.Errorf("countSolutions = %d, want a unique solution", n)
+			}
+		})
+	}
+}
+
+func TestCountSolutions(t *testing.T) {
+	// An empty grid has billions of solutions; counting stops at limit.
+	empty := mustParse(t, strings.Repeat(".", 81))
+	if n := countSolutions(empty, 5); n != 5 {
+		t.Errorf("countSolutions of an empty grid = %d, want the limit 5", n)
+	}
+	// Two 1s in the first row: no solution at all.
+	conflict := mustParse(t, "11"+strings.Repeat(".", 79))
+	if n := countSolutions(conflict, 2); n != 0 {
+		t.Errorf("countSolutions of a conflicting puzzle = %d, want 0", n)
+	}
+	if _, ok := solve(conflict); ok {
+		t.Error("solve succeeded on a conflicting puzzle")
+	}
+}
+
+func TestIsValidSolution(t *testing.T) {
+	p := mustParse(t, puzzle)
+	sol, _ := solve(p)
+	if !isValidSolution(p, sol) {
+		t.Fatal("reference solution rejected")
+	}
+
+	swapped := sol
+	swapped[0], swapped[1] = swapped[1], swapped[0] // breaks columns and a given
+	if isValidSolution(p, swapped) {
+		t.Error("solution with swapped cells accepted")
+	}
+	incomplete := sol
+	incomplete[40] = 0
+	if isValidSolution(p, incomplete) {
+		t.Error("incomplete solution accepted")
+	}
+	// A valid grid that ignores the givens is still wrong.
+	other := mustParse(t, "534678912672195348198342567859761423426853791713924856961537284287419635345286179")
+	if isValidSolution(p, other) {
+		t.Error("solution to a different puzzle accepted")
+	}
+}
+
+func TestParseGrid(t *testing.T) {
+	if _, err := parseGrid("123"); err == nil {
+		t.Error("short grid accepted")
+	}
+	if _, err := parseGrid("x" + puzzle[1:]); err == nil {
+		t.Error("invalid character accepted")
+	}
+	g := mustParse(t, "0"+puzzle[1:])
+	if g[0] != 0 {
+		t.Error("'0' not parsed as empty")
+	}
+}
+
+func TestExpectedFirstRow(t *testing.T) {
+	if got := solveRepeatedly(mustParse(t, puzzle), 3); got != expectedFirstRow {
+		t.Errorf("first row = %d, want %d", got, expectedFirstRow)
+	}
+}
@@ -0,0 +1,176 @@
+/*
+ * Trie Operations
+ *
+ * Insert 500,000 generated lowercase words into a prefix tree, then answer
+ * 500,000 prefix-count queries ("how many distinct words start with
+ * p?"). Words are 2-7 letters and query prefixes 1-4 letters, all drawn
+ * from benchlib.NewRand(benchlib.DefaultSeed): for each string one draw for
+ * the length, then one per letter, words first. Duplicate words are stored
+ * once, leaving 344,236 distinct words. RESULT is the sum of the query
+ * answers.
+ *
+ * Each node links to its first child and next sibling, with siblings kept
+ * in letter order, and stores how many words pass through it, so a query
+ * costs one walk down the prefix.
+ * Expected result: 1723329481
+ *
+ * This benchmark tests:
+ * - Allocation of many small nodes
+ * - Pointer chasing along sibling lists
+ * - Short, data-dependent loops
+ */
+
+package main
+
+import (
+	"flag"
+	"math/rand"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	words         = 500000
+	queries       = 500000
+	expectedTotal = 1723329481
+)
+
+// node is a trie node for the letter it is reached by.
+type node struct {
+	letter byte
+	// end marks that a word ends here.
+	end bool
+	// count is the number of distinct words in this subtree, including one
+	// ending here.
+	count       int32
+	child, next *node
+}
+
+// trie is a set of strings supporting prefix counts. The zero value is an
+// empty trie.
+type trie struct {
+	root node
+	size int
+}
+
+// find returns the child of n for letter, or nil.
+func (n *node) find(letter byte) *node {
+	for c := n.child; c != nil && c.letter <= letter; c = c.next {
+		if c.letter == letter {
+			return c
+		}
+	}
+	return nil
+}
+
+// findOrAdd returns the child of n for letter, inserting it in letter order
+// if absent.
+func (n *node) findOrAdd(letter byte) *node {
+	link := &n.child
+	for *link != nil && (*link).letter < letter {
+		link = &(*link).next
+	}
+	if c := *link; c != nil && c.letter == letter {
+		return c
+	}
+	c := &node{letter: letter, next: *link}
+	*link = c
+	return c
+}
+
+// insert adds word and reports whether it was not already present.
+func (t *trie) insert(word string) bool {
+	if t.contains(word) {
+		return false
+	}
+	n := &t.root
+	n.count++
+	for i := 0; i < len(word); i++ {
+		n = n.findOrAdd(word[i])
+		n.count++
+	}
+	n.end = true
+	t.size++
+	return true
+}
+
+// walk returns the node reached by s, or nil.
+func (t *trie) walk(s string) *node {
+	n := &t.root
+	for i := 0; i < len(s) && n != nil; i++ {
+		n = n.find(s[i])
+	}
+	return n
+}
+
+// contains reports whether word was inserted.
+func (t *trie) contains(word string) bool {
+	n := t.walk(word)
+	return n != nil && n.end
+}
+
+// countPrefix returns the number of distinct words that start with prefix,
+// including prefix itself if it is a word.
+func (t *trie) countPrefix(prefix string) int {
+	n := t.walk(prefix)
+	if n == nil {
+		return 0
+	}
+	return int(n.count)
+}
+
+// randomWords returns n lowercase strings of minLen to maxLen letters.
+func randomWords(r *rand.Rand, n, minLen, maxLen int) []string {
+	out := make([]string, n)
+	buf := make([]byte, maxLen)
+	for i := range out {
+		length := minLen + int(r.Uint64()%uint64(maxLen-minLen+1))
+		for j := 0; j < length; j++ {
+			buf[j] = 'a' + byte(r.Uint64()%26)
+		}
+		out[i] = string(buf[:length])
+	}
+	return out
+}
+
+// prefixTotal builds a trie of dict and returns the sum of the prefix
+// counts of prefixes.
+func prefixTotal(dict, prefixes []string) int64 {
+	var t trie
+	for _, w := range dict {
+		t.insert(w)
+	}
+	var total int64
+	for _, p := range prefixes {
+		total += int64(t.countPrefix(p))
+	}
+	return total
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "trie", Category: benchlib.CategoryMemory, Expected: expectedTotal})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	t0 := time.Now()
+
+	// Startup phase: generate the dictionary and the queries
+	r := benchlib.NewRand(opts.Seed)
+	dict := randomWords(r, words, 2, 7)
+	prefixes := randomWords(r, queries, 1, 4)
+
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("trie", opts, startup, func() int64 {
+		return prefixTotal(dict, prefixes)
+	})
+
+	// Validate result
+	benchlib.ValidateSeed(opts.Seed, stats.Result, expectedTotal)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+func newTrie(words ...string) *trie {
+	t := new(trie)
+	for _, w := range words {
+		t.insert(w)
+	}
+	return t
+}
+
+func TestInsert(t *testing.T) {
+	tr := new(trie)
+	if !tr.insert("cart") {
+		t.Error("first insert of cart reported a duplicate")
+	}
+	if tr.insert("cart") {
+		t.Error("second insert of cart reported a new word")
+	}
+	if !tr.insert("car") || !tr.insert("cat") {
+		t.Error("insert of a new word reported a duplicate")
+	}
+	if tr.size != 3 {
+		t.Errorf("size = %d, want 3", tr.size)
+	}
+}
+
+func TestSiblingsStayOrdered(t *testing.T) {
+	tr := newTrie("m", "z", "a", "q", "b")
+	var letters []byte
+	for c := tr.root.child; c != nil; c = c.next {
+		letters = append(letters, c.letter)
+	}
+	if string(letters) != "abmqz" {
+		t.Errorf("root children = %q, want \"abmqz\"", letters)
+	}
+}
+
+func TestContains(t *testing.T) {
+	tr := newTrie("car", "cart", "cat", "dog")
+	for _, tt := range []struct {
+		word string
+		want bool
+	}{
+		{"car", true},
+		{"cart", true},
+		{"cat", true},
+		{"dog", true},
+		{"ca", false},    // prefix of words, not a word
+		{"carts", false}, // extends a word
+		{"cow", false},
+		{"", false},
+	} {
+		if got := tr.contains(tt.word); got != tt.want {
+			t.Errorf("contains(%q) = %v, want %v", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestCountPrefix(t *testing.T) {
+	tr := newTrie("car", "cart", "carton", "cat", "dog", "cart")
+	for _, tt := range []struct {
+		prefix string
+		want   int
+	}{
+		{"", 5},
+		{"c", 4},
+		{"ca", 4},
+		{"car", 3}, // car itself counts, and is a prefix of cart and carton
+		{"cart", 2},
+		{"carto", 1},
+		{"carton", 1},
+		{"cartons", 0},
+		{"d", 1},
+		{"e", 0},
+	} {
+		if got := tr.countPrefix(tt.prefix); got != tt.want {
+			t.Errorf("countPrefix(%q) = %d, want %d", tt.prefix, got, tt.want)
+		}
+	}
+}
+
+// naiveTotal is a reference for prefixTotal using a sorted, deduplicated
+// word list and linear prefix scans.
+func naiveTotal(dict, prefixes []string) int64 {
+	d := slices.Clone(dict)
+	slices.Sort(d)
+	d = slices.Compact(d)
+	var total int64
+	for _, p := range prefixes {
+		for _, w := range d {
+			if strings.HasPrefix(w, p) {
+				total++
+			}
+		}
+	}
+	return total
+}
+
+func TestPrefixTotalMatchesNaive(t *testing.T) {
+	r := benchlib.NewRand(benchlib.DefaultSeed)
+	dict := randomWords(r, 2000, 1, 4)
+	prefixes := randomWords(r, 300, 1, 3)
+	if got, want := prefixTotal(dict, prefixes), naiveTotal(dict, prefixes); got != want {
+		t.Errorf("prefixTotal = %d, naive = %d", got, want)
+	}
+}
+
+func TestExpectedTotal(t *testing.T) {
+	r := benchlib.NewRand(benchlib.DefaultSeed)
+	dict := randomWords(r, words, 2, 7)
+	prefixes := randomWords(r, queries, 1, 4)
+	if got := prefixTotal(dict, prefixes); got != expectedTotal {
+		t.Errorf("total = %d, want %d", got, expectedTotal)
+	}
+}
@@ -0,0 +1,128 @@
+/*
+ * Word Count
+ *
+ * Split a generated 12,000,000-word document into words, count each
+ * word's occurrences in a map[string]int, and find the most common word;
+ * RESULT is its count. Tokenizing treats every run of ASCII letters as a
+ * word and folds it to lower case, so "The", "the," and "the" are one
+ * word. A tie for the highest count goes to the lexicographically
+ * smallest word, so the answer does not depend on map iteration order.
+ *
+ * Word i of the document takes draws from
+ * benchlib.NewRand(benchlib.DefaultSeed) in this order: a length of
+ * 1 + r.Uint64() % 5 letters, then each letter 'a' + r.Uint64() % 8, then
+ * a separator chosen by r.Uint64() % 8: 0-4 a space, 5 ", ", 6 ". " and 7
+ * a newline. A word after ". " is capitalized. The 37,448 possible words
+ * include only 8 one-letter ones, each expected to be 2.5% of the
+ * document, so the top word is one of those, ahead of the runner-up by
+ * only 63 occurrences. The document is generated during startup, and the
+ * map is rebuilt from empty on every run.
+ * Expected result: 300684 (the word "h")
+ *
+ * This benchmark tests:
+ * - String-keyed map insertion and increment
+ * - Byte scanning and case folding
+ */
+
+package main
+
+import (
+	"flag"
+	"math/rand"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+const (
+	numWords      = 12000000
+	maxWordLen    = 5
+	alphabetSize  = 8
+	expectedCount = 300684
+)
+
+// separators are the strings that can follow a word, indexed by draw.
+var separators = [8]string{" ", " ", " ", " ", " ", ", ", ". ", "\n"}
+
+// makeDocument returns n generated words with their separators.
+func makeDocument(r *rand.Rand, n int) []byte {
+	doc := make([]byte, 0, n*(maxWordLen/2+3))
+	capitalize := false
+	for i := 0; i < n; i++ {
+		length := 1 + int(r.Uint64()%maxWordLen)
+		for j := 0; j < length; j++ {
+			c := 'a' + byte(r.Uint64()%alphabetSize)
+			if j == 0 && capitalize {
+				c -= 'a' - 'A'
+			}
+			doc = append(doc, c)
+		}
+		sep := separators[r.Uint64()%uint64(len(separators))]
+		doc = append(doc, sep...)
+		capitalize = sep == ". "
+	}
+	return doc
+}
+
+// countWords returns how many times each lower-cased run of ASCII letters
+// occurs in doc.
+func countWords(doc []byte) map[string]int {
+	counts := make(map[string]int)
+	var word []byte
+	for i := 0; i <= len(doc); i++ {
+		var c byte
+		if i < len(doc) {
+			c = doc[i]
+		}
+		switch {
+		case 'a' <= c && c <= 'z':
+			word = append(word, c)
+		case 'A' <= c && c <= 'Z':
+			word = append(word, c+('a'-'A'))
+		case len(word) > 0:
+			// The conversion in the index expression does not allocate;
+			// only a new key is copied.
+			counts[string(word)]++
+			word = word[:0]
+		}
+	}
+	return counts
+}
+
+// topWord returns the word with the highest count, the lexicographically
+// smallest among equals, and its count; for no words it returns "", 0.
+func topWord(counts map[string]int) (string, int) {
+	best, bestCount := "", 0
+	for w, n := range counts {
+		if n > bestCount || n == bestCount && w < best {
+			best, bestCount = w, n
+		}
+	}
+	return best, bestCount
+}
+
+func init() {
+	benchlib.Register(benchlib.Info{Name: "wordcount", Category: benchlib.CategoryText, Expected: expectedCount})
+}
+
+func main() {
+	var opts benchlib.Options
+	opts.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	t0 := time.Now()
+
+	// Startup phase: generate the document
+	doc := makeDocument(benchlib.NewRand(opts.Seed), numWords)
+
+	startup := time.Since(t0)
+
+	// Compute benchmark
+	stats := benchlib.Run("wordcount", opts, startup, func() int64 {
+		_, n := topWord(countWords(doc))
+		return int64(n)
+	})
+
+	// Validate result
+	benchlib.ValidateSeed(opts.Seed, stats.Result, expectedCount)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+func TestCountWords(t *testing.T) {
+	got := countWords([]byte("The cat, the hat.\nThe END... end"))
+	want := map[string]int{"the": 3, "cat": 1, "hat": 1, "end": 2}
+	if !maps.Equal(got, want) {
+		t.Errorf("countWords = %v, want %v", got, want)
+	}
+	if got := countWords([]byte(" 42, !\n")); len(got) != 0 {
+		t.Errorf("countWords of no letters = %v, want empty", got)
+	}
+}
+
+func TestTopWordSmallDocument(t *testing.T) {
+	doc := "It was the best of times, it was the worst of times. It was the age of wisdom"
+	// "it", "the", "was" and "of" all occur 3 times.
+	if word, n := topWord(countWords([]byte(doc))); word != "it" || n != 3 {
+		t.Errorf("topWord = %q, %d; want \"it\", 3", word, n)
+	}
+}
+
+func TestTopWordTieBreak(t *testing.T) {
+	counts := map[string]int{"pear": 4, "apple": 4, "fig": 3, "zebra": 4}
+	// Map iteration order varies from call to call; the answer must not.
+	for range 50 {
+		if word, n := topWord(counts); word != "apple" || n != 4 {
+			t.Fatalf("topWord = %q, %d; want apple, 4", word, n)
+		}
+	}
+	if word, n := topWord(map[string]int{}); word != "" || n != 0 {
+		t.Errorf("topWord of no words = %q, %d", word, n)
+	}
+}
+
+func TestMakeDocument(t *testing.T) {
+	doc := string(makeDocument(benchlib.NewRand(benchlib.DefaultSeed), 1000))
+	var words int
+	for _, f := range strings.FieldsFunc(doc, func(r rune) bool { return r < 'A' || r > 'Z' && r < 'a' || r > 'z' }) {
+		words++
+		if len(f) > maxWordLen {
+			t.Errorf("word %q longer than %d letters", f, maxWordLen)
+		}
+	}
+	if words != 1000 {
+		t.Errorf("document has %d words, want 1000", words)
+	}
+	// Each ". " is followed by a capital.
+	for _, sentence := range strings.Split(doc, ". ")[1:] {
+		if c := sentence[0]; c < 'A' || c > 'Z' {
+			t.Errorf("sentence %q does not start with a capital", sentence)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// Command baseline stores and inspects benchmark baselines.
+//
+// A baseline is a versioned JSON file holding every benchmark's startup
+// time, compute time, and result, together with a timestamp and a
+// description of the host it was measured on. compare accepts baseline
+// files directly.
+//
+// Usage:
+//
+//	baseline save [--runall="go run ./cmd/runall"] [--output=baseline.json]
+//	baseline show baseline.json
+//
+// save runs the --runall command with --format=json appended, and refuses
+// to write a baseline if any benchmark failed. show prints a stored
+// baseline as a table.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+	"github.com/paiml/ruchy-docker/result"
+)
+
+// now is time.Now, replaceable in tests.
+var now = time.Now
+
+// collect runs the runall command line and decodes its combined JSON.
+// runall's stderr, which carries the benchmarks' own diagnostics, is
+// forwarded to stderr.
+func collect(runall []string, stderr io.Writer) (result.Combined, error) {
+	args := append(slices.Clone(runall[1:]), "--format=json")
+	var stdout bytes.Buffer
+	cmd := exec.Command(runall[0], args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = stderr
+	runErr := cmd.Run()
+	c, err := result.DecodeCombined(&stdout)
+	if err != nil {
+		if runErr != nil {
+			return result.Combined{}, fmt.Errorf("%s: %w", runall[0], runErr)
+		}
+		return result.Combined{}, err
+	}
+	// A non-zero exit with parseable output means some benchmark failed;
+	// NewBaseline reports which one.
+	return c, nil
+}
+
+// save implements `baseline save`.
+func save(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("baseline save", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	runall := fs.String("runall", "go run ./cmd/runall", "command that runs every benchmark")
+	output := fs.String("output", "baseline.json", "file to write the baseline to")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintf(stderr, "baseline save: unexpected argument %q\n", fs.Arg(0))
+		return 2
+	}
+	cmdline := strings.Fields(*runall)
+	if len(cmdline) == 0 {
+		fmt.Fprintln(stderr, "baseline save: --runall must not be empty")
+		return 2
+	}
+
+	c, err := collect(cmdline, stderr)
+	if err != nil {
+		fmt.Fprintf(stderr, "baseline save: %v\n", err)
+		return 1
+	}
+	b, err := result.NewBaseline(c, benchlib.HostInfo(), now())
+	if err != nil {
+		fmt.Fprintf(stderr, "baseline save: %v\n", err)
+		return 1
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		fmt.Fprintf(stderr, "baseline save: %v\n", err)
+		return 1
+	}
+	err = result.WriteBaseline(f, b)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "baseline save: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "saved %d benchmarks to %s\n", len(b.Benchmarks), *output)
+	return 0
+}
+
+// printBaseline writes b as a header describing the run followed by a
+// table sorted by benchmark name.
+func printBaseline(w io.Writer, b result.Baseline) error {
+	h := b.Host
+	fmt.Fprintf(w, "schema:  %d\n", b.SchemaVersion)
+	fmt.Fprintf(w, "saved:   %s\n", b.Timestamp.Format(time.RFC3339))
+	fmt.Fprintf(w, "host:    %s, %d CPUs, %s/%s, %s\n\n", h.CPUModel, h.NumCPU, h.GOOS, h.GOARCH, h.GoVersion)
+
+	names := make([]string, 0, len(b.Benchmarks))
+	for name := range b.Benchmarks {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "BENCHMARK\tSTARTUP_US\tCOMPUTE_US\tRESULT")
+	for _, name := range names {
+		e := b.Benchmarks[name]
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\n", name, e.StartupUS, e.ComputeUS, e.Result)
+	}
+	return tw.Flush()
+}
+
+// show implements `baseline show`.
+func show(args []string, stdout, stderr io.Writer) int {
+	if len(args) != 1 {
+		fmt.Fprintln(stderr, "usage: baseline show baseline.json")
+		return 2
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		fmt.Fprintf(stderr, "baseline show: %v\n", err)
+		return 2
+	}
+	defer f.Close()
+	b, err := result.ReadBaseline(f)
+	if err != nil {
+		fmt.Fprintf(stderr, "baseline show: %s: %v\n", args[0], err)
+		return 2
+	}
+	if err := printBaseline(stdout, b); err != nil {
+		fmt.Fprintf(stderr, "baseline show: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+const usage = "usage: baseline save [--runall=CMD] [--output=FILE] | baseline show FILE"
+
+// run is main with injectable arguments and output; it returns the exit
+// status.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, usage)
+		return 2
+	}
+	switch args[0] {
+	case "save":
+		return save(args[1:], stdout, stderr)
+	case "show":
+		return show(args[1:], stdout, stderr)
+	default:
+		fmt.Fprintf(stderr, "baseline: unknown subcommand %q\n%s\n", args[0], usage)
+		return 2
+	}
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
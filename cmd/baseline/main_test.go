@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/paiml/ruchy-docker/result"
+)
+
+// TestMain lets the test binary double as a stub runall: when
+// BASELINE_STUB is set it prints canned combined JSON and exits.
+func TestMain(m *testing.M) {
+	switch os.Getenv("BASELINE_STUB") {
+	case "":
+		os.Exit(m.Run())
+	case "ok":
+		if os.Args[len(os.Args)-1] != "--format=json" {
+			fmt.Fprintln(os.Stderr, "stub: missing --format=json")
+			os.Exit(2)
+		}
+		fmt.Println(`{"benchmarks":[{"benchmark":"primes","startup_us":12,"compute_us":340,"result":9592},` +
+			`{"benchmark":"fibonacci","startup_us":5,"compute_us":51861,"result":9227465}]}`)
+	case "failed":
+		fmt.Println(`{"benchmarks":[{"benchmark":"mutex","startup_us":0,"compute_us":0,"result":0,"error":"exit status 1"}]}`)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// stubRunall returns a --runall command that re-executes the test binary
+// in mode.
+func stubRunall(t *testing.T, mode string) string {
+	t.Helper()
+	script := filepath.Join(t.TempDir(), "runall")
+	body := fmt.Sprintf("#!/bin/sh\nBASELINE_STUB=%s exec %q \"$@\"\n", mode, os.Args[0])
+	if err := os.WriteFile(script, []byte(body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return script
+}
+
+func TestSaveAndShow(t *testing.T) {
+	now = func() time.Time { return time.Date(2026, 10, 14, 7, 30, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	out := filepath.Join(t.TempDir(), "baseline.json")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"save", "--runall=" + stubRunall(t, "ok"), "--output=" + out}, &stdout, &stderr); code != 0 {
+		t.Fatalf("save = %d, stderr: %s", code, stderr.String())
+	}
+
+	f, err := os.Open(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := result.ReadBaseline(f)
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Benchmarks["fibonacci"].ComputeUS != 51861 || b.Host.NumCPU < 1 || !b.Timestamp.Equal(now()) {
+		t.Errorf("saved baseline = %+v", b)
+	}
+
+	stdout.Reset()
+	if code := run([]string{"show", out}, &stdout, &stderr); code != 0 {
+		t.Fatalf("show = %d, stderr: %s", code, stderr.String())
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if lines[1] != "saved:   2026-10-14T07:30:00Z" {
+		t.Errorf("saved line = %q", lines[1])
+	}
+	// Rows are sorted by name: fibonacci before primes.
+	tail := lines[len(lines)-2:]
+	if !strings.HasPrefix(tail[0], "fibonacci ") || !strings.HasPrefix(tail[1], "primes ") {
+		t.Errorf("table rows = %q", tail)
+	}
+}
+
+func TestSaveRejectsFailedRun(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "baseline.json")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"save", "--runall=" + stubRunall(t, "failed"), "--output=" + out}, &stdout, &stderr); code != 1 {
+		t.Errorf("save = %d, want 1", code)
+	}
+	if !strings.Contains(stderr.String(), "mutex failed") {
+		t.Errorf("stderr = %q, want the failed benchmark named", stderr.String())
+	}
+	if _, err := os.Stat(out); err == nil {
+		t.Error("baseline written despite a failed benchmark")
+	}
+}
+
+func TestShowRejectsUnknownSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "future.json")
+	if err := os.WriteFile(path, []byte(`{"schema_version": 2, "benchmarks": {}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"show", path}, &stdout, &stderr); code != 2 {
+		t.Errorf("show = %d, want 2", code)
+	}
+	if !strings.Contains(stderr.String(), "schema_version 2") {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+}
+
+func TestRunUsage(t *testing.T) {
+	for _, args := range [][]string{nil, {"frobnicate"}, {"show"}, {"save", "extra"}} {
+		var stdout, stderr bytes.Buffer
+		if code := run(args, &stdout, &stderr); code != 2 {
+			t.Errorf("run(%q) = %d, want 2", args, code)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// Command compare checks current benchmark results against a baseline and
+// fails if any benchmark regressed.
+//
+// Each input is either a baseline file written by `baseline save`, or a
+// JSON object mapping benchmark name to compute time in microseconds:
+//
+//	{"fibonacci": 51861, "primes": 225}
+//
+// Usage:
+//
+//	compare [--threshold=10] baseline.json current.json
+//
+// compare prints a table of per-benchmark deltas and speedups. It exits 1
+// if any benchmark is slower than its baseline by more than threshold
+// percent, and 2 on usage errors, unreadable input, or benchmarks present
+// in only one of the two files.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/paiml/ruchy-docker/result"
+)
+
+// row is the comparison of one benchmark.
+type row struct {
+	Name      string
+	Baseline  int64
+	Current   int64
+	DeltaPct  float64 // (current − baseline) / baseline × 100
+	Speedup   float64 // baseline / current
+	Regressed bool
+}
+
+// compare matches current against baseline and flags every benchmark whose
+// compute time grew by more than thresholdPct percent. Rows are sorted by
+// name. A benchmark present in only one input is an error.
+func compare(baseline, current map[string]int64, thresholdPct float64) ([]row, error) {
+	for name := range current {
+		if _, ok := baseline[name]; !ok {
+			return nil, fmt.Errorf("benchmark %q missing from baseline", name)
+		}
+	}
+
+	names := make([]string, 0, len(baseline))
+	for name := range baseline {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	rows := make([]row, 0, len(names))
+	for _, name := range names {
+		base := baseline[name]
+		cur, ok := current[name]
+		if !ok {
+			return nil, fmt.Errorf("benchmark %q missing from current results", name)
+		}
+		if base <= 0 || cur <= 0 {
+			return nil, fmt.Errorf("benchmark %q: compute times must be positive (baseline %d, current %d)", name, base, cur)
+		}
+		delta := float64(cur-base) / float64(base) * 100
+		rows = append(rows, row{
+			Name:      name,
+			Baseline:  base,
+			Current:   cur,
+			DeltaPct:  delta,
+			Speedup:   float64(base) / float64(cur),
+			Regressed: delta > thresholdPct,
+		})
+	}
+	return rows, nil
+}
+
+// printTable writes rows as an aligned table.
+func printTable(w io.Writer, rows []row) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "BENCHMARK\tBASELINE_US\tCURRENT_US\tDELTA\tSPEEDUP\tSTATUS")
+	for _, r := range rows {
+		status := "ok"
+		if r.Regressed {
+			status = "REGRESSION"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%+.2f%%\t%.2fx\t%s\n", r.Name, r.Baseline, r.Current, r.DeltaPct, r.Speedup, status)
+	}
+	return tw.Flush()
+}
+
+// loadResults reads benchmark-name → compute_us from path, which holds
+// either a versioned baseline file or a bare name → compute_us object. A
+// top-level schema_version key marks the former.
+func loadResults(path string) (map[string]int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if _, ok := probe["schema_version"]; ok {
+		b, err := result.ReadBaseline(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return b.ComputeUS(), nil
+	}
+	var results map[string]int64
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return results, nil
+}
+
+// run is main with injectable arguments and output; it returns the exit
+// status.
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("compare", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	threshold := fs.Float64("threshold", 10, "maximum allowed slowdown in percent")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: compare [--threshold=PCT] baseline.json current.json")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+
+	baseline, err := loadResults(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "compare: %v\n", err)
+		return 2
+	}
+	current, err := loadResults(fs.Arg(1))
+	if err != nil {
+		fmt.Fprintf(stderr, "compare: %v\n", err)
+		return 2
+	}
+
+	rows, err := compare(baseline, current, *threshold)
+	if err != nil {
+		fmt.Fprintf(stderr, "compare: %v\n", err)
+		return 2
+	}
+	if err := printTable(stdout, rows); err != nil {
+		fmt.Fprintf(stderr, "compare: %v\n", err)
+		return 2
+	}
+
+	regressions := 0
+	for _, r := range rows {
+		if r.Regressed {
+			regressions++
+		}
+	}
+	if regressions > 0 {
+		fmt.Fprintf(stderr, "compare: %d benchmark(s) regressed by more than %g%%\n", regressions, *threshold)
+		return 1
+	}
+	return 0
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	baseline := map[string]int64{"fibonacci": 1000, "primes": 200, "matrix-multiply": 3000}
+
+	tests := []struct {
+		name          string
+		current       map[string]int64
+		wantRegressed []string
+	}{
+		{
+			name:    "improvement",
+			current: map[string]int64{"fibonacci": 500, "primes": 150, "matrix-multiply": 2900},
+		},
+		{
+			name:          "regression",
+			current:       map[string]int64{"fibonacci": 1101, "primes": 200, "matrix-multiply": 3000},
+			wantRegressed: []string{"fibonacci"},
+		},
+		{
+			// Exactly +10% is still within the threshold.
+			name:    "within threshold",
+			current: map[string]int64{"fibonacci": 1100, "primes": 210, "matrix-multiply": 3299},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := compare(baseline, tt.current, 10)
+			if err != nil {
+				t.Fatalf("compare: %v", err)
+			}
+			var regressed []string
+			for _, r := range rows {
+				if r.Regressed {
+					regressed = append(regressed, r.Name)
+				}
+			}
+			if strings.Join(regressed, ",") != strings.Join(tt.wantRegressed, ",") {
+				t.Errorf("regressed = %v, want %v", regressed, tt.wantRegressed)
+			}
+		})
+	}
+}
+
+func TestCompareDeltaAndSpeedup(t *testing.T) {
+	rows, err := compare(map[string]int64{"a": 200}, map[string]int64{"a": 100}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := rows[0]; r.DeltaPct != -50 || r.Speedup != 2 {
+		t.Errorf("row = %+v, want delta -50%% and speedup 2x", r)
+	}
+}
+
+func TestCompareMissingBenchmarks(t *testing.T) {
+	if _, err := compare(map[string]int64{"a": 1, "b": 1}, map[string]int64{"a": 1}, 10); err == nil {
+		t.Error("no error when current is missing a benchmark")
+	}
+	if _, err := compare(map[string]int64{"a": 1}, map[string]int64{"a": 1, "b": 1}, 10); err == nil {
+		t.Error("no error when baseline is missing a benchmark")
+	}
+}
+
+func writeJSON(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	base := writeJSON(t, dir, "base.json", `{"primes": 200, "fibonacci": 1000}`)
+	fast := writeJSON(t, dir, "fast.json", `{"primes": 190, "fibonacci": 1000}`)
+	slow := writeJSON(t, dir, "slow.json", `{"primes": 300, "fibonacci": 1000}`)
+	short := writeJSON(t, dir, "short.json", `{"primes": 200}`)
+
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{[]string{base, fast}, 0},
+		{[]string{base, slow}, 1},
+		{[]string{"--threshold=60", base, slow}, 0},
+		{[]string{base, short}, 2},
+		{[]string{base}, 2},
+		{[]string{base, filepath.Join(dir, "absent.json")}, 2},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if got := run(tt.args, &stdout, &stderr); got != tt.want {
+			t.Errorf("run(%v) = %d, want %d\nstderr: %s", tt.args, got, tt.want, stderr.String())
+		}
+	}
+}
+
+func TestRunPrintsTable(t *testing.T) {
+	dir := t.TempDir()
+	base := writeJSON(t, dir, "base.json", `{"primes": 200}`)
+	cur := writeJSON(t, dir, "cur.json", `{"primes": 250}`)
+
+	var stdout, stderr bytes.Buffer
+	run([]string{base, cur}, &stdout, &stderr)
+
+	out := stdout.String()
+	for _, want := range []string{"BENCHMARK", "primes", "200", "250", "+25.00%", "0.80x", "REGRESSION"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("table missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestLoadResultsBaselineFile(t *testing.T) {
+	dir := t.TempDir()
+	path := writeJSON(t, dir, "baseline.json", `{
+  "schema_version": 1,
+  "timestamp": "2026-10-14T07:30:00Z",
+  "host": {"cpu_model": "test cpu", "num_cpu": 8, "goarch": "amd64", "goos": "linux", "go_version": "go1.23.0"},
+  "benchmarks": {"primes": {"startup_us": 8, "compute_us": 225, "result": 9592}}
+}`)
+	got, err := loadResults(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got["primes"] != 225 {
+		t.Errorf("loadResults = %v, want map[primes:225]", got)
+	}
+
+	future := writeJSON(t, dir, "future.json", `{"schema_version": 99, "benchmarks": {}}`)
+	if _, err := loadResults(future); err == nil {
+		t.Error("loadResults accepted an unknown schema version")
+	}
+}
@@ -0,0 +1,156 @@
+// Command dockerrun builds one Go benchmark's container image and runs it
+// under Docker resource limits.
+//
+// The image is built from docker/go/<name>.Dockerfile with the module root
+// as build context and tagged go:<name>, matching the Makefile. The
+// container's stdout is parsed with the result package and printed as a
+// one-row table.
+//
+// Usage:
+//
+//	dockerrun [--cpus=N] [--memory=SIZE] [--dockerfiles=docker/go] benchmarks/<name> [-- benchmark flags]
+//
+// --cpus and --memory are passed to `docker run` unchanged; left empty, the
+// container is unlimited. Arguments after the benchmark directory are
+// passed to the benchmark itself. Run it from the module root. dockerrun
+// exits 2 on usage errors or when the docker CLI is not installed, and 1
+// when the build or run fails or the output does not parse.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/paiml/ruchy-docker/result"
+)
+
+// lookPath and runCommand reach the docker CLI; tests replace them.
+var (
+	lookPath   = exec.LookPath
+	runCommand = func(name string, args []string, stdout, stderr io.Writer) error {
+		cmd := exec.Command(name, args...)
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		return cmd.Run()
+	}
+)
+
+var errNoDocker = errors.New("docker CLI not found in PATH; install Docker (https://docs.docker.com/get-docker/) to use dockerrun")
+
+// limits are the container resource limits; empty fields are not passed.
+type limits struct {
+	CPUs   string
+	Memory string
+}
+
+// imageTag returns the tag the Makefile uses for the Go image of name.
+func imageTag(name string) string { return "go:" + name }
+
+// buildArgs returns the `docker build` arguments for the benchmark image.
+func buildArgs(dockerfile, tag string) []string {
+	return []string{"build", "-f", dockerfile, "-t", tag, "."}
+}
+
+// runArgs returns the `docker run` arguments that run tag once under lim,
+// passing benchArgs to the benchmark.
+func runArgs(tag string, lim limits, benchArgs []string) []string {
+	args := []string{"run", "--rm"}
+	if lim.CPUs != "" {
+		args = append(args, "--cpus", lim.CPUs)
+	}
+	if lim.Memory != "" {
+		args = append(args, "--memory", lim.Memory)
+	}
+	args = append(args, tag)
+	return append(args, benchArgs...)
+}
+
+// dockerRun builds and runs the benchmark in dir and parses its output.
+// Build output and the container's stderr go to stderr.
+func dockerRun(docker, dir, dockerfiles string, lim limits, benchArgs []string, stderr io.Writer) (result.Result, error) {
+	name := filepath.Base(filepath.Clean(dir))
+	dockerfile := filepath.Join(dockerfiles, name+".Dockerfile")
+	if _, err := os.Stat(dockerfile); err != nil {
+		return result.Result{}, fmt.Errorf("no Dockerfile for %s: %w", name, err)
+	}
+	tag := imageTag(name)
+
+	if err := runCommand(docker, buildArgs(dockerfile, tag), stderr, stderr); err != nil {
+		return result.Result{}, fmt.Errorf("docker build %s: %w", tag, err)
+	}
+	var stdout bytes.Buffer
+	if err := runCommand(docker, runArgs(tag, lim, benchArgs), &stdout, stderr); err != nil {
+		return result.Result{}, fmt.Errorf("docker run %s: %w", tag, err)
+	}
+	res, err := result.Parse(&stdout)
+	if err != nil {
+		return result.Result{}, fmt.Errorf("%s: %w", tag, err)
+	}
+	return res, nil
+}
+
+// run is main with injectable arguments and output; it returns the exit
+// status.
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("dockerrun", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var lim limits
+	fs.StringVar(&lim.CPUs, "cpus", "", "CPU limit passed to docker run --cpus (empty = unlimited)")
+	fs.StringVar(&lim.Memory, "memory", "", "memory limit passed to docker run --memory, e.g. 512m (empty = unlimited)")
+	dockerfiles := fs.String("dockerfiles", filepath.Join("docker", "go"), "directory holding <name>.Dockerfile for each benchmark")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: dockerrun [--cpus=N] [--memory=SIZE] benchmarks/<name> [-- benchmark flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return 2
+	}
+	benchArgs := fs.Args()[1:]
+	if len(benchArgs) > 0 && benchArgs[0] == "--" {
+		benchArgs = benchArgs[1:]
+	}
+	if lim.CPUs != "" {
+		if n, err := strconv.ParseFloat(lim.CPUs, 64); err != nil || n <= 0 {
+			fmt.Fprintf(stderr, "dockerrun: --cpus must be a positive number, got %q\n", lim.CPUs)
+			return 2
+		}
+	}
+
+	docker, err := lookPath("docker")
+	if err != nil {
+		fmt.Fprintf(stderr, "dockerrun: %v\n", errNoDocker)
+		return 2
+	}
+
+	dir := fs.Arg(0)
+	res, err := dockerRun(docker, dir, *dockerfiles, lim, benchArgs, stderr)
+	if err != nil {
+		fmt.Fprintf(stderr, "dockerrun: %v\n", err)
+		return 1
+	}
+
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "BENCHMARK\tSTARTUP_US\tCOMPUTE_US\tRESULT")
+	fmt.Fprintf(tw, "%s\t%d\t%d\t%d\n", filepath.Base(filepath.Clean(dir)), res.StartupUS, res.ComputeUS, res.Result)
+	if err := tw.Flush(); err != nil {
+		fmt.Fprintf(stderr, "dockerrun: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
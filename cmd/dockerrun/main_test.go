@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// call is one recorded docker invocation.
+type call struct {
+	name string
+	args []string
+}
+
+// fakeDocker replaces the docker CLI for the test. Every invocation is
+// recorded; `run` invocations print output, and the invocation whose
+// first argument matches failOn returns an error.
+func fakeDocker(t *testing.T, output, failOn string) *[]call {
+	t.Helper()
+	var calls []call
+	origLook, origRun := lookPath, runCommand
+	t.Cleanup(func() { lookPath, runCommand = origLook, origRun })
+	lookPath = func(string) (string, error) { return "/usr/bin/docker", nil }
+	runCommand = func(name string, args []string, stdout, stderr io.Writer) error {
+		calls = append(calls, call{name, slices.Clone(args)})
+		if args[0] == failOn {
+			return errors.New("exit status 1")
+		}
+		if args[0] == "run" {
+			io.WriteString(stdout, output)
+		}
+		return nil
+	}
+	return &calls
+}
+
+// dockerfiles returns a directory holding an empty Dockerfile for each name.
+func dockerfiles(t *testing.T, names ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name+".Dockerfile"), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+const primesOutput = "STARTUP_TIME_US: 12\nCOMPUTE_TIME_US: 340\nRESULT: 9592\n"
+
+func TestRunArgs(t *testing.T) {
+	tests := []struct {
+		lim       limits
+		benchArgs []string
+		want      []string
+	}{
+		{limits{}, nil, []string{"run", "--rm", "go:primes"}},
+		{limits{CPUs: "2"}, nil, []string{"run", "--rm", "--cpus", "2", "go:primes"}},
+		{
+			limits{CPUs: "1.5", Memory: "512m"}, []string{"--iterations=5", "--format=json"},
+			[]string{"run", "--rm", "--cpus", "1.5", "--memory", "512m", "go:primes", "--iterations=5", "--format=json"},
+		},
+	}
+	for _, tt := range tests {
+		if got := runArgs("go:primes", tt.lim, tt.benchArgs); !slices.Equal(got, tt.want) {
+			t.Errorf("runArgs(%+v, %v) = %q, want %q", tt.lim, tt.benchArgs, got, tt.want)
+		}
+	}
+}
+
+func TestRunBuildsThenRuns(t *testing.T) {
+	calls := fakeDocker(t, primesOutput, "")
+	dir := dockerfiles(t, "primes")
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"--cpus=2", "--memory=256m", "--dockerfiles=" + dir, "benchmarks/primes/", "--", "--iterations=3"}
+	if code := run(args, &stdout, &stderr); code != 0 {
+		t.Fatalf("run = %d, stderr: %s", code, stderr.String())
+	}
+
+	want := []call{
+		{"/usr/bin/docker", []string{"build", "-f", filepath.Join(dir, "primes.Dockerfile"), "-t", "go:primes", "."}},
+		{"/usr/bin/docker", []string{"run", "--rm", "--cpus", "2", "--memory", "256m", "go:primes", "--iterations=3"}},
+	}
+	if len(*calls) != len(want) {
+		t.Fatalf("docker invoked %d times, want %d: %v", len(*calls), len(want), *calls)
+	}
+	for i, c := range *calls {
+		if c.name != want[i].name || !slices.Equal(c.args, want[i].args) {
+			t.Errorf("call %d = %s %q, want %s %q", i, c.name, c.args, want[i].name, want[i].args)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 || strings.Join(strings.Fields(lines[1]), " ") != "primes 12 340 9592" {
+		t.Errorf("output = %q", stdout.String())
+	}
+}
+
+func TestRunFailures(t *testing.T) {
+	dir := dockerfiles(t, "primes")
+	tests := []struct {
+		name           string
+		output, failOn string
+		args           []string
+		wantCode       int
+		wantStderr     string
+	}{
+		{"build fails", primesOutput, "build", []string{"benchmarks/primes"}, 1, "docker build go:primes"},
+		{"run fails", primesOutput, "run", []string{"benchmarks/primes"}, 1, "docker run go:primes"},
+		{"garbled output", "RESULT: 1\n", "", []string{"benchmarks/primes"}, 1, "missing STARTUP_TIME_US"},
+		{"no Dockerfile", primesOutput, "", []string{"benchmarks/nbody"}, 1, "no Dockerfile for nbody"},
+		{"bad cpus", primesOutput, "", []string{"--cpus=0", "benchmarks/primes"}, 2, "--cpus must be a positive number"},
+		{"no benchmark", primesOutput, "", nil, 2, "usage:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeDocker(t, tt.output, tt.failOn)
+			var stdout, stderr bytes.Buffer
+			args := append([]string{"--dockerfiles=" + dir}, tt.args...)
+			if code := run(args, &stdout, &stderr); code != tt.wantCode {
+				t.Errorf("run = %d, want %d", code, tt.wantCode)
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
+
+func TestRunWithoutDocker(t *testing.T) {
+	calls := fakeDocker(t, primesOutput, "")
+	lookPath = func(string) (string, error) {
+		return "", errors.New(`exec: "docker": executable file not found in $PATH`)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"--dockerfiles=" + dockerfiles(t, "primes"), "benchmarks/primes"}, &stdout, &stderr); code != 2 {
+		t.Errorf("run = %d, want 2", code)
+	}
+	if !strings.Contains(stderr.String(), "docker CLI not found") {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+	if len(*calls) != 0 {
+		t.Errorf("docker invoked without a docker CLI: %v", *calls)
+	}
+}
@@ -0,0 +1,233 @@
+// Command report renders combined benchmark results as a self-contained
+// HTML page.
+//
+// The input is the JSON written by `runall --format=json`. The page holds a
+// table, sortable by clicking a column header, with a bar per benchmark
+// whose width is proportional to its compute time.
+//
+// Usage:
+//
+//	report [--baseline=old.json] [--threshold=10] results.json > report.html
+//
+// With --baseline, each benchmark is compared to the same benchmark in a
+// prior runall JSON: rows more than threshold percent slower are colored
+// red, rows more than threshold percent faster green.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+
+	"github.com/paiml/ruchy-docker/result"
+)
+
+// Row status classes, used as CSS classes in the page.
+const (
+	statusRegression  = "regression"
+	statusImprovement = "improvement"
+	statusFailed      = "failed"
+)
+
+// row is one table row of the page.
+type row struct {
+	result.Entry
+	// BarPct is the compute time as a percentage of the slowest benchmark.
+	BarPct float64
+	// Delta describes the change against the baseline, e.g. "+12.3%"; it is
+	// empty without a baseline and "new" for a benchmark the baseline lacks.
+	Delta string
+	// DeltaPct is the numeric change used to sort the delta column.
+	DeltaPct float64
+	Status   string
+}
+
+// page is the data passed to pageTemplate.
+type page struct {
+	Rows        []row
+	HasBaseline bool
+}
+
+// buildPage turns the current results, and optionally a baseline, into
+// table rows in input order.
+func buildPage(current result.Combined, baseline *result.Combined, thresholdPct float64) page {
+	var maxUS int64
+	for _, e := range current.Benchmarks {
+		if e.Error == "" {
+			maxUS = max(maxUS, e.ComputeUS)
+		}
+	}
+
+	prior := make(map[string]result.Entry)
+	if baseline != nil {
+		for _, e := range baseline.Benchmarks {
+			if e.Error == "" {
+				prior[e.Benchmark] = e
+			}
+		}
+	}
+
+	p := page{HasBaseline: baseline != nil}
+	for _, e := range current.Benchmarks {
+		r := row{Entry: e}
+		switch {
+		case e.Error != "":
+			r.Status = statusFailed
+		case maxUS > 0:
+			r.BarPct = float64(e.ComputeUS) / float64(maxUS) * 100
+		}
+		if baseline != nil && e.Error == "" {
+			if b, ok := prior[e.Benchmark]; !ok || b.ComputeUS <= 0 {
+				r.Delta = "new"
+			} else {
+				delta := float64(e.ComputeUS-b.ComputeUS) / float64(b.ComputeUS) * 100
+				r.Delta = fmt.Sprintf("%+.1f%%", delta)
+				r.DeltaPct = delta
+				switch {
+				case delta > thresholdPct:
+					r.Status = statusRegression
+				case delta < -thresholdPct:
+					r.Status = statusImprovement
+				}
+			}
+		}
+		p.Rows = append(p.Rows, r)
+	}
+	return p
+}
+
+var pageTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Benchmark report</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { padding: 0.3em 0.8em; text-align: right; }
+th { cursor: pointer; border-bottom: 2px solid #444; }
+td:first-child, th:first-child { text-align: left; }
+td.bar { width: 30em; text-align: left; }
+td.bar div { background: #6a8cc7; height: 1em; }
+tr.regression { background: #f8d0d0; }
+tr.improvement { background: #d0f0d0; }
+tr.failed { color: #999; }
+</style>
+</head>
+<body>
+<h1>Benchmark report</h1>
+<table id="results">
+<thead>
+<tr>
+<th data-type="text">Benchmark</th>
+<th data-type="num">Startup (µs)</th>
+<th data-type="num">Compute (µs)</th>
+<th data-type="num">Result</th>
+{{- if .HasBaseline}}
+<th data-type="num">Δ vs baseline</th>
+{{- end}}
+<th data-type="num">Relative compute</th>
+</tr>
+</thead>
+<tbody>
+{{- range .Rows}}
+<tr class="{{.Status}}">
+<td data-value="{{.Benchmark}}">{{.Benchmark}}</td>
+{{- if .Error}}
+<td data-value="-1">-</td>
+<td data-value="-1">-</td>
+<td data-value="-1" title="{{.Error}}">FAILED</td>
+{{- else}}
+<td data-value="{{.StartupUS}}">{{.StartupUS}}</td>
+<td data-value="{{.ComputeUS}}">{{.ComputeUS}}</td>
+<td data-value="{{.Result}}">{{.Result}}</td>
+{{- end}}
+{{- if $.HasBaseline}}
+<td data-value="{{.DeltaPct}}">{{.Delta}}</td>
+{{- end}}
+<td class="bar" data-value="{{.BarPct}}"><div style="width: {{printf "%.1f" .BarPct}}%"></div></td>
+</tr>
+{{- end}}
+</tbody>
+</table>
+<script>
+document.querySelectorAll("#results th").forEach(function (th, col) {
+  th.addEventListener("click", function () {
+    var tbody = document.querySelector("#results tbody");
+    var rows = Array.from(tbody.rows);
+    var asc = th.dataset.dir !== "asc";
+    th.dataset.dir = asc ? "asc" : "desc";
+    var num = th.dataset.type === "num";
+    rows.sort(function (a, b) {
+      var x = a.cells[col].dataset.value, y = b.cells[col].dataset.value;
+      var c = num ? parseFloat(x) - parseFloat(y) : x.localeCompare(y);
+      return asc ? c : -c;
+    });
+    rows.forEach(function (r) { tbody.appendChild(r); });
+  });
+});
+</script>
+</body>
+</html>
+`))
+
+// readCombined opens and decodes a runall JSON file.
+func readCombined(path string) (result.Combined, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return result.Combined{}, err
+	}
+	defer f.Close()
+	c, err := result.DecodeCombined(f)
+	if err != nil {
+		return result.Combined{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// run is main with injectable arguments and output; it returns the exit
+// status.
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	baselinePath := fs.String("baseline", "", "prior runall JSON to compare against")
+	threshold := fs.Float64("threshold", 10, "percent change beyond which a row is colored")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: report [--baseline=old.json] [--threshold=PCT] results.json")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	current, err := readCombined(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "report: %v\n", err)
+		return 2
+	}
+	var baseline *result.Combined
+	if *baselinePath != "" {
+		b, err := readCombined(*baselinePath)
+		if err != nil {
+			fmt.Fprintf(stderr, "report: %v\n", err)
+			return 2
+		}
+		baseline = &b
+	}
+
+	if err := pageTemplate.Execute(stdout, buildPage(current, baseline, *threshold)); err != nil {
+		fmt.Fprintf(stderr, "report: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/result"
+)
+
+func render(t *testing.T, p page) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := pageTemplate.Execute(&buf, p); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	return buf.String()
+}
+
+var current = result.Combined{Benchmarks: []result.Entry{
+	{Benchmark: "fibonacci", StartupUS: 37, ComputeUS: 50000, Result: 9227465},
+	{Benchmark: "primes", StartupUS: 8, ComputeUS: 250, Result: 9592},
+	{Benchmark: "nbody", StartupUS: 0, ComputeUS: 100000, Result: -169083134},
+	{Benchmark: "mutex", Error: "exit status 1"},
+}}
+
+func TestPageRows(t *testing.T) {
+	p := buildPage(current, nil, 10)
+	html := render(t, p)
+
+	for _, want := range []string{
+		`<td data-value="fibonacci">fibonacci</td>`,
+		`<td data-value="9227465">9227465</td>`,
+		`<td data-value="-169083134">-169083134</td>`,
+		// nbody is the slowest and gets the full-width bar; fibonacci half.
+		`style="width: 100.0%"`,
+		`style="width: 50.0%"`,
+		`<tr class="failed">`,
+		`title="exit status 1">FAILED</td>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("page missing %q", want)
+		}
+	}
+	if strings.Contains(html, "baseline</th>") {
+		t.Error("delta column present without --baseline")
+	}
+	if got := strings.Count(html, "<tr class="); got != len(current.Benchmarks) {
+		t.Errorf("page has %d body rows, want %d", got, len(current.Benchmarks))
+	}
+}
+
+func TestPageEscapesNames(t *testing.T) {
+	c := result.Combined{Benchmarks: []result.Entry{
+		{Benchmark: `<script>alert("x")</script>`, ComputeUS: 1},
+	}}
+	html := render(t, buildPage(c, nil, 10))
+
+	if strings.Contains(html, `<script>alert`) {
+		t.Fatal("benchmark name was not escaped")
+	}
+	if !strings.Contains(html, `&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;`) {
+		t.Errorf("escaped name not found in page:\n%s", html)
+	}
+}
+
+func TestPageBaselineColors(t *testing.T) {
+	baseline := result.Combined{Benchmarks: []result.Entry{
+		{Benchmark: "fibonacci", ComputeUS: 100000}, // now 50000: improvement
+		{Benchmark: "primes", ComputeUS: 200},       // now 250: regression
+		{Benchmark: "nbody", ComputeUS: 95000},      // now 100000: within 10%
+	}}
+	p := buildPage(current, &baseline, 10)
+
+	want := map[string]struct{ status, delta string }{
+		"fibonacci": {statusImprovement, "-50.0%"},
+		"primes":    {statusRegression, "+25.0%"},
+		"nbody":     {"", "+5.3%"},
+		"mutex":     {statusFailed, ""},
+	}
+	for _, r := range p.Rows {
+		w := want[r.Benchmark]
+		if r.Status != w.status || r.Delta != w.delta {
+			t.Errorf("%s: status %q delta %q, want %q %q", r.Benchmark, r.Status, r.Delta, w.status, w.delta)
+		}
+	}
+
+	html := render(t, p)
+	for _, want := range []string{`<tr class="regression">`, `<tr class="improvement">`, "baseline</th>", ">&#43;25.0%</td>"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("page missing %q", want)
+		}
+	}
+}
+
+func TestPageNewBenchmark(t *testing.T) {
+	baseline := result.Combined{Benchmarks: []result.Entry{{Benchmark: "primes", ComputeUS: 250}}}
+	p := buildPage(current, &baseline, 10)
+	if p.Rows[0].Delta != "new" || p.Rows[0].Status != "" {
+		t.Errorf("fibonacci row = %+v, want delta \"new\" and no status", p.Rows[0])
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "results.json")
+	data := `{"benchmarks":[{"benchmark":"primes","startup_us":8,"compute_us":250,"result":9592}]}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"--baseline=" + path, path}, &stdout, &stderr); code != 0 {
+		t.Fatalf("run = %d, stderr: %s", code, stderr.String())
+	}
+	if !strings.HasPrefix(stdout.String(), "<!DOCTYPE html>") || !strings.Contains(stdout.String(), ">&#43;0.0%</td>") {
+		t.Errorf("unexpected page:\n%s", stdout.String())
+	}
+
+	if code := run([]string{filepath.Join(dir, "missing.json")}, &stdout, &stderr); code != 2 {
+		t.Errorf("run on a missing file = %d, want 2", code)
+	}
+}
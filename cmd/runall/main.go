@@ -0,0 +1,473 @@
+// Command runall builds and runs every Go benchmark and prints a combined
+// summary.
+//
+// A benchmark is any directory under --dir (default "benchmarks") that
+// contains a main.go. Each one is built with `go build`, run (once by
+// default), and its output parsed with the result package.
+//
+// Usage:
+//
+//	runall [--dir=benchmarks] [--format=text|json|csv|markdown] [--category=NAME]
+//	runall --format=markdown --baseline=old.json
+//	runall --verify-determinism=5
+//	runall --list [--dir=benchmarks]
+//
+// The markdown format is a GitHub-flavored table for pasting into PR
+// comments. With --baseline, a prior runall JSON or a `baseline save`
+// file, it gains a delta column giving each compute time's change against
+// the baseline.
+//
+// Every benchmark registers its name, category and expected result with
+// benchlib.Register and prints them when run with --describe. --list shows
+// that metadata grouped by category instead of running anything, and
+// --category runs only the benchmarks in one category.
+//
+// With --verify-determinism=K every benchmark is run K times and fails,
+// naming the differing run, unless all K print the same RESULT. This
+// catches races in concurrent benchmarks; the summary shows the first
+// run's timings.
+//
+// The text, json and markdown summaries end with the geometric mean of the
+// successful benchmarks' compute times, a GEOMEAN row or the
+// geomean_compute_us field: unlike the arithmetic mean it is not dominated
+// by the slowest benchmarks, and the ratio of two suites' geomeans is the
+// geomean of the per-benchmark speedups. The csv format keeps to one row
+// per benchmark.
+//
+// The benchmarks inherit runall's environment, so BENCH_ITERATIONS and
+// BENCH_WARMUP apply to them, but not BENCH_FORMAT: runall always reads
+// their text output.
+//
+// Run it from the module root. runall exits 1 if any benchmark fails to
+// build, exits non-zero (for example on a validation FAILURE), or produces
+// output that does not parse; the remaining benchmarks still run.
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+	"github.com/paiml/ruchy-docker/internal/benchexec"
+	"github.com/paiml/ruchy-docker/result"
+)
+
+// benchmark is one runnable benchmark program.
+type benchmark struct {
+	Name string
+	Cmd  []string // program and arguments
+	Err  error    // set if the benchmark could not be built or described
+	// Info is the benchmark's registered metadata, filled in by describe.
+	Info benchlib.Info
+}
+
+// outcome is the result of running one benchmark. Err is set when the
+// benchmark failed, in which case the other fields are zero.
+type outcome struct {
+	Name string
+	result.Result
+	Err error
+}
+
+// describe returns b's registered metadata; see benchexec.Describe.
+func describe(b benchmark) (benchlib.Info, error) {
+	if b.Err != nil {
+		return benchlib.Info{}, b.Err
+	}
+	return benchexec.Describe(b.Name, b.Cmd)
+}
+
+// describeAll fills in the Info of every benchmark, recording failures in
+// Err.
+func describeAll(benches []benchmark) {
+	for i := range benches {
+		info, err := describe(benches[i])
+		benches[i].Info, benches[i].Err = info, err
+	}
+}
+
+// selectCategory returns the benchmarks registered in category. Benchmarks
+// whose metadata is unknown because of an error are kept, so the error is
+// still reported.
+func selectCategory(benches []benchmark, category string) []benchmark {
+	var out []benchmark
+	for _, b := range benches {
+		if b.Err != nil || b.Info.Category == category {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// printList writes the described benchmarks grouped by category, in the
+// order of benchlib.Categories, and reports those that failed on stderr.
+// It returns whether every benchmark could be described.
+func printList(stdout, stderr io.Writer, benches []benchmark) (bool, error) {
+	ok := true
+	var infos []benchlib.Info
+	for _, b := range benches {
+		if b.Err != nil {
+			fmt.Fprintf(stderr, "runall: %s: %v\n", b.Name, b.Err)
+			ok = false
+			continue
+		}
+		infos = append(infos, b.Info)
+	}
+	slices.SortFunc(infos, func(a, b benchlib.Info) int { return strings.Compare(a.Name, b.Name) })
+
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CATEGORY\tBENCHMARK\tEXPECTED")
+	for _, category := range benchlib.Categories {
+		for i, info := range benchlib.FilterCategory(infos, category) {
+			label := category
+			if i > 0 {
+				label = ""
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%d\n", label, info.Name, info.Expected)
+		}
+	}
+	return ok, tw.Flush()
+}
+
+// execute runs b and parses its output. stderr receives the benchmark's
+// own stderr so validation failures are visible. BENCH_FORMAT is removed
+// from b's environment so it prints the text format result.Parse reads.
+func execute(b benchmark, stderr io.Writer) outcome {
+	if b.Err != nil {
+		return outcome{Name: b.Name, Err: b.Err}
+	}
+	var stdout bytes.Buffer
+	cmd := benchexec.Command(b.Cmd, benchlib.EnvFormat)
+	cmd.Stdout = &stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return outcome{Name: b.Name, Err: err}
+	}
+	res, err := result.Parse(&stdout)
+	if err != nil {
+		return outcome{Name: b.Name, Err: err}
+	}
+	return outcome{Name: b.Name, Result: res}
+}
+
+// geomean returns the geometric mean of the compute times of the
+// successful outcomes, or 0 if there are none. It is computed as the
+// exponential of the mean logarithm, so many large times cannot overflow.
+// A time that is not positive has no logarithm and is an error.
+func geomean(outcomes []outcome) (float64, error) {
+	var sum float64
+	n := 0
+	for _, o := range outcomes {
+		if o.Err != nil {
+			continue
+		}
+		if o.ComputeUS <= 0 {
+			return 0, fmt.Errorf("geometric mean needs positive compute times, %s has %dus", o.Name, o.ComputeUS)
+		}
+		sum += math.Log(float64(o.ComputeUS))
+		n++
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	return math.Exp(sum / float64(n)), nil
+}
+
+// printTable writes outcomes as an aligned table, ending with a GEOMEAN row
+// unless gm is 0.
+func printTable(w io.Writer, outcomes []outcome, gm float64) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "BENCHMARK\tSTARTUP_US\tCOMPUTE_US\tRESULT")
+	for _, o := range outcomes {
+		if o.Err != nil {
+			fmt.Fprintf(tw, "%s\t-\t-\tFAILED\n", o.Name)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\n", o.Name, o.StartupUS, o.ComputeUS, o.Result.Result)
+	}
+	if gm > 0 {
+		fmt.Fprintf(tw, "GEOMEAN\t-\t%.1f\t-\n", gm)
+	}
+	return tw.Flush()
+}
+
+// printJSON writes outcomes and their geometric mean gm as one
+// result.Combined JSON object.
+func printJSON(w io.Writer, outcomes []outcome, gm float64) error {
+	rep := result.Combined{Benchmarks: make([]result.Entry, 0, len(outcomes)), GeomeanComputeUS: gm}
+	for _, o := range outcomes {
+		j := result.Entry{
+			Benchmark: o.Name,
+			StartupUS: o.StartupUS,
+			ComputeUS: o.ComputeUS,
+			Result:    o.Result.Result,
+		}
+		if o.Err != nil {
+			j.Error = o.Err.Error()
+		}
+		rep.Benchmarks = append(rep.Benchmarks, j)
+	}
+	return json.NewEncoder(w).Encode(rep)
+}
+
+// printCSV writes the benchlib CSV header once followed by one row per
+// successful benchmark. Failed benchmarks are left out; their errors are on
+// stderr.
+func printCSV(w io.Writer, outcomes []outcome) error {
+	cw := csv.NewWriter(w)
+	cw.Write(benchlib.CSVHeader)
+	for _, o := range outcomes {
+		if o.Err == nil {
+			cw.Write(benchlib.CSVRecord(o.Name, o.StartupUS, o.ComputeUS, o.Result.Result))
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// markdownDelta returns the delta column cell for o: the percent change
+// of its compute time against baseline, "new" if the baseline lacks it,
+// and "-" if it failed.
+func markdownDelta(o outcome, baseline map[string]int64) string {
+	base, ok := baseline[o.Name]
+	switch {
+	case o.Err != nil:
+		return "-"
+	case !ok || base <= 0:
+		return "new"
+	}
+	return fmt.Sprintf("%+.1f%%", float64(o.ComputeUS-base)/float64(base)*100)
+}
+
+// printMarkdown writes outcomes as a Markdown table with one row per
+// benchmark; failed ones show FAILED. A GEOMEAN row follows unless gm is
+// 0. A non-nil baseline adds the delta column.
+func printMarkdown(w io.Writer, outcomes []outcome, gm float64, baseline map[string]int64) error {
+	header := slices.Clone(benchlib.CSVHeader)
+	if baseline != nil {
+		header = append(header, "delta")
+	}
+	rows := make([][]string, 0, len(outcomes))
+	for _, o := range outcomes {
+		row := []string{o.Name, "-", "-", "FAILED"}
+		if o.Err == nil {
+			row = benchlib.CSVRecord(o.Name, o.StartupUS, o.ComputeUS, o.Result.Result)
+		}
+		if baseline != nil {
+			row = append(row, markdownDelta(o, baseline))
+		}
+		rows = append(rows, row)
+	}
+	if gm > 0 {
+		row := []string{"GEOMEAN", "-", fmt.Sprintf("%.1f", gm), "-"}
+		if baseline != nil {
+			row = append(row, "-")
+		}
+		rows = append(rows, row)
+	}
+	return benchlib.WriteMarkdownTable(w, header, rows)
+}
+
+// loadBaseline reads benchmark name → compute_us from path, which holds
+// either runall JSON or a versioned baseline file; a top-level
+// schema_version key marks the latter. Failed benchmarks are left out.
+func loadBaseline(path string) (map[string]int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if _, ok := probe["schema_version"]; ok {
+		b, err := result.ReadBaseline(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return b.ComputeUS(), nil
+	}
+	c, err := result.DecodeCombined(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	us := make(map[string]int64, len(c.Benchmarks))
+	for _, e := range c.Benchmarks {
+		if e.Error == "" {
+			us[e.Benchmark] = e.ComputeUS
+		}
+	}
+	return us, nil
+}
+
+// executeRuns runs b runs times, or once if runs < 2, and returns the
+// first outcome. Every run must succeed and print the same RESULT;
+// otherwise the outcome is an error naming the first run that differed.
+func executeRuns(b benchmark, runs int, stderr io.Writer) outcome {
+	first := execute(b, stderr)
+	for i := 2; i <= runs && first.Err == nil; i++ {
+		o := execute(b, stderr)
+		switch {
+		case o.Err != nil:
+			return outcome{Name: b.Name, Err: fmt.Errorf("run %d: %w", i, o.Err)}
+		case o.Result.Result != first.Result.Result:
+			return outcome{Name: b.Name, Err: fmt.Errorf("nondeterministic: RESULT %d on run 1, %d on run %d", first.Result.Result, o.Result.Result, i)}
+		}
+	}
+	return first
+}
+
+// config controls how runAll runs and reports the benchmarks.
+type config struct {
+	// Format is the summary format: text, json, csv or markdown.
+	Format string
+	// Baseline, used only by the markdown format, maps benchmark names to
+	// prior compute times; it may be nil.
+	Baseline map[string]int64
+	// Runs is how many times each benchmark is run to check that its
+	// RESULT is deterministic; below 2 each runs once.
+	Runs int
+}
+
+// runAll executes each benchmark in turn and writes the summary as cfg
+// says. It returns the outcomes and whether every benchmark succeeded.
+func runAll(benches []benchmark, cfg config, stdout, stderr io.Writer) ([]outcome, bool, error) {
+	outcomes := make([]outcome, 0, len(benches))
+	ok := true
+	for _, b := range benches {
+		o := executeRuns(b, cfg.Runs, stderr)
+		if o.Err != nil {
+			fmt.Fprintf(stderr, "runall: %s: %v\n", b.Name, o.Err)
+			ok = false
+		}
+		outcomes = append(outcomes, o)
+	}
+	gm, err := geomean(outcomes)
+	if err != nil {
+		fmt.Fprintf(stderr, "runall: %v\n", err)
+		ok = false
+	}
+	switch cfg.Format {
+	case "json":
+		err = printJSON(stdout, outcomes, gm)
+	case "csv":
+		err = printCSV(stdout, outcomes)
+	case "markdown":
+		err = printMarkdown(stdout, outcomes, gm, cfg.Baseline)
+	default:
+		err = printTable(stdout, outcomes, gm)
+	}
+	return outcomes, ok, err
+}
+
+// run is main with injectable arguments and output; it returns the exit
+// status.
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("runall", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dir := fs.String("dir", "benchmarks", "directory containing one subdirectory per benchmark")
+	format := fs.String("format", "text", "output format: text, json, csv, markdown")
+	baselinePath := fs.String("baseline", "", "prior runall JSON or baseline file to show a delta column against (markdown only)")
+	list := fs.Bool("list", false, "list the benchmarks grouped by category instead of running them")
+	verify := fs.Int("verify-determinism", 1, "run each benchmark `K` times and fail any whose RESULT differs between runs")
+	category := fs.String("category", "", "run only the benchmarks in this category: "+strings.Join(benchlib.Categories, ", "))
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if !slices.Contains([]string{"text", "json", "csv", "markdown"}, *format) {
+		fmt.Fprintf(stderr, "runall: unknown format %q (want text, json, csv, markdown)\n", *format)
+		return 2
+	}
+	if *verify < 1 {
+		fmt.Fprintf(stderr, "runall: --verify-determinism must be >= 1, got %d\n", *verify)
+		return 2
+	}
+	if *baselinePath != "" && *format != "markdown" {
+		fmt.Fprintln(stderr, "runall: --baseline needs --format=markdown")
+		return 2
+	}
+	if *category != "" && !slices.Contains(benchlib.Categories, *category) {
+		fmt.Fprintf(stderr, "runall: unknown category %q (want one of %s)\n", *category, strings.Join(benchlib.Categories, ", "))
+		return 2
+	}
+
+	var baseline map[string]int64
+	if *baselinePath != "" {
+		b, err := loadBaseline(*baselinePath)
+		if err != nil {
+			fmt.Fprintf(stderr, "runall: %v\n", err)
+			return 2
+		}
+		baseline = b
+	}
+
+	names, err := benchexec.Discover(*dir)
+	if err != nil {
+		fmt.Fprintf(stderr, "runall: %v\n", err)
+		return 2
+	}
+	if len(names) == 0 {
+		fmt.Fprintf(stderr, "runall: no benchmarks found under %s\n", *dir)
+		return 2
+	}
+
+	binDir, err := os.MkdirTemp("", "runall-")
+	if err != nil {
+		fmt.Fprintf(stderr, "runall: %v\n", err)
+		return 2
+	}
+	defer os.RemoveAll(binDir)
+
+	benches := make([]benchmark, 0, len(names))
+	for _, name := range names {
+		bin := filepath.Join(binDir, name)
+		err := benchexec.Build(filepath.Join(*dir, name), bin)
+		benches = append(benches, benchmark{Name: name, Cmd: []string{bin}, Err: err})
+	}
+
+	if *list || *category != "" {
+		describeAll(benches)
+	}
+	if *list {
+		ok, err := printList(stdout, stderr, benches)
+		if err != nil {
+			fmt.Fprintf(stderr, "runall: %v\n", err)
+			return 1
+		}
+		if !ok {
+			return 1
+		}
+		return 0
+	}
+	if *category != "" {
+		benches = selectCategory(benches, *category)
+		if len(benches) == 0 {
+			fmt.Fprintf(stderr, "runall: no benchmarks in category %s\n", *category)
+			return 2
+		}
+	}
+
+	_, ok, err := runAll(benches, config{Format: *format, Baseline: baseline, Runs: *verify}, stdout, stderr)
+	if err != nil {
+		fmt.Fprintf(stderr, "runall: %v\n", err)
+		return 1
+	}
+	if !ok {
+		return 1
+	}
+	return 0
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
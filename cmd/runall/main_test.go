@@ -0,0 +1,460 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+	"github.com/paiml/ruchy-docker/result"
+)
+
+// stubCategories is the category each stub mode registers under; modes
+// not listed fail --describe.
+var stubCategories = map[string]string{
+	"fast":  benchlib.CategoryNumeric,
+	"slow":  benchlib.CategoryRecursion,
+	"panic": benchlib.CategoryRecursion,
+	"env":   benchlib.CategoryNumeric,
+}
+
+// TestMain lets the test binary double as a stub benchmark: when
+// RUNALL_STUB is set it prints canned output for that mode and exits. With
+// --describe it prints the metadata of a benchmark named RUNALL_STUB_NAME,
+// or of a wrongly named one in mode "misnamed".
+func TestMain(m *testing.M) {
+	mode := os.Getenv("RUNALL_STUB")
+	if mode != "" && slices.Contains(os.Args[1:], "--describe") {
+		name := os.Getenv("RUNALL_STUB_NAME")
+		category, ok := stubCategories[mode]
+		switch {
+		case mode == "misnamed":
+			name, category, ok = "other", benchlib.CategoryNumeric, true
+		case !ok:
+			fmt.Fprintln(os.Stderr, "flag provided but not defined: -describe")
+			os.Exit(2)
+		}
+		json.NewEncoder(os.Stdout).Encode(benchlib.Info{Name: name, Category: category, Expected: 1})
+		os.Exit(0)
+	}
+	switch mode {
+	case "":
+		os.Exit(m.Run())
+	case "fast":
+		fmt.Println("STARTUP_TIME_US: 12")
+		fmt.Println("COMPUTE_TIME_US: 340")
+		fmt.Println("RESULT: 9592")
+	case "slow":
+		fmt.Println("some log noise")
+		fmt.Println("STARTUP_TIME_US: 5")
+		fmt.Println("COMPUTE_TIME_US: 51861")
+		fmt.Println("RESULT: 9227465")
+	case "panic":
+		fmt.Println("STARTUP_TIME_US: 5")
+		fmt.Fprintln(os.Stderr, "panic: Expected 9227465, got 1")
+		os.Exit(2)
+	case "garbled":
+		fmt.Println("RESULT: 1")
+	case "instant":
+		// Faster than the microsecond resolution.
+		fmt.Println("STARTUP_TIME_US: 5")
+		fmt.Println("COMPUTE_TIME_US: 0")
+		fmt.Println("RESULT: 1")
+	case "racy":
+		// A different RESULT in every process.
+		fmt.Println("STARTUP_TIME_US: 5")
+		fmt.Println("COMPUTE_TIME_US: 100")
+		fmt.Printf("RESULT: %d\n", os.Getpid())
+	case "env":
+		// Honors BENCH_FORMAT the way benchlib.RegisterFlags does.
+		if os.Getenv(benchlib.EnvFormat) == benchlib.FormatJSON {
+			fmt.Println(`{"benchmark":"primes","startup_us":12,"compute_us":340,"result":9592}`)
+			break
+		}
+		fmt.Println("STARTUP_TIME_US: 12")
+		fmt.Println("COMPUTE_TIME_US: 340")
+		fmt.Println("RESULT: 9592")
+	}
+	os.Exit(0)
+}
+
+// stub returns a benchmark that re-executes the test binary in mode.
+func stub(t *testing.T, name, mode string) benchmark {
+	t.Helper()
+	script := filepath.Join(t.TempDir(), name)
+	body := fmt.Sprintf("#!/bin/sh\nRUNALL_STUB=%s RUNALL_STUB_NAME=%s exec %q \"$@\"\n", mode, name, os.Args[0])
+	if err := os.WriteFile(script, []byte(body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return benchmark{Name: name, Cmd: []string{script}}
+}
+
+func TestRunAllText(t *testing.T) {
+	benches := []benchmark{stub(t, "primes", "fast"), stub(t, "fibonacci", "slow")}
+
+	var stdout, stderr bytes.Buffer
+	outcomes, ok, err := runAll(benches, config{Format: "text"}, &stdout, &stderr)
+	if err != nil || !ok {
+		t.Fatalf("runAll = ok %v, err %v; stderr: %s", ok, err, stderr.String())
+	}
+	if outcomes[1].ComputeUS != 51861 || outcomes[1].Result.Result != 9227465 {
+		t.Errorf("fibonacci outcome = %+v", outcomes[1])
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d table lines, want header + 2 + GEOMEAN:\n%s", len(lines), stdout.String())
+	}
+	for i, want := range [][]string{
+		{"BENCHMARK", "STARTUP_US", "COMPUTE_US", "RESULT"},
+		{"primes", "12", "340", "9592"},
+		{"fibonacci", "5", "51861", "9227465"},
+		{"GEOMEAN", "-", "4199.1", "-"},
+	} {
+		if got := strings.Fields(lines[i]); !slices.Equal(got, want) {
+			t.Errorf("line %d = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestRunAllIgnoresBenchFormat(t *testing.T) {
+	t.Setenv(benchlib.EnvFormat, benchlib.FormatJSON)
+	var stdout, stderr bytes.Buffer
+	outcomes, ok, err := runAll([]benchmark{stub(t, "primes", "env")}, config{Format: "text"}, &stdout, &stderr)
+	if err != nil || !ok {
+		t.Fatalf("runAll = ok %v, err %v; stderr: %s", ok, err, stderr.String())
+	}
+	if o := outcomes[0]; o.ComputeUS != 340 || o.Result.Result != 9592 {
+		t.Errorf("outcome = %+v, want the text output parsed", o)
+	}
+}
+
+func TestRunAllJSON(t *testing.T) {
+	benches := []benchmark{stub(t, "primes", "fast"), stub(t, "fibonacci", "slow")}
+
+	var stdout, stderr bytes.Buffer
+	if _, ok, err := runAll(benches, config{Format: "json"}, &stdout, &stderr); err != nil || !ok {
+		t.Fatalf("runAll = ok %v, err %v", ok, err)
+	}
+	if n := strings.Count(stdout.String(), "\n"); n != 1 {
+		t.Errorf("JSON output spans %d lines, want 1", n)
+	}
+	rep, err := result.DecodeCombined(bytes.NewReader(stdout.Bytes()))
+	if err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, stdout.String())
+	}
+	want := []result.Entry{
+		{Benchmark: "primes", StartupUS: 12, ComputeUS: 340, Result: 9592},
+		{Benchmark: "fibonacci", StartupUS: 5, ComputeUS: 51861, Result: 9227465},
+	}
+	if !slices.Equal(rep.Benchmarks, want) {
+		t.Errorf("benchmarks = %+v, want %+v", rep.Benchmarks, want)
+	}
+	// √(340 · 51861)
+	if got := rep.GeomeanComputeUS; math.Abs(got-4199.1356) > 1e-3 {
+		t.Errorf("geomean_compute_us = %v, want 4199.1356", got)
+	}
+}
+
+func TestRunAllCSV(t *testing.T) {
+	benches := []benchmark{stub(t, "primes", "fast"), stub(t, "fibonacci", "slow"), stub(t, "broken", "panic")}
+
+	var stdout, stderr bytes.Buffer
+	if _, ok, err := runAll(benches, config{Format: "csv"}, &stdout, &stderr); err != nil || ok {
+		t.Fatalf("runAll = ok %v, err %v; want failure reported for broken", ok, err)
+	}
+	records, err := csv.NewReader(&stdout).ReadAll()
+	if err != nil {
+		t.Fatalf("csv.ReadAll: %v", err)
+	}
+	want := [][]string{
+		{"benchmark", "startup_us", "compute_us", "result"},
+		{"primes", "12", "340", "9592"},
+		{"fibonacci", "5", "51861", "9227465"},
+	}
+	if !slices.EqualFunc(records, want, slices.Equal) {
+		t.Errorf("records = %q, want %q", records, want)
+	}
+}
+
+func TestRunAllMarkdown(t *testing.T) {
+	benches := []benchmark{stub(t, "primes", "fast"), stub(t, "fibonacci", "slow")}
+
+	var stdout, stderr bytes.Buffer
+	if _, ok, err := runAll(benches, config{Format: "markdown"}, &stdout, &stderr); err != nil || !ok {
+		t.Fatalf("runAll = ok %v, err %v", ok, err)
+	}
+	want := "| benchmark | startup_us | compute_us | result |\n" +
+		"|---|---:|---:|---:|\n" +
+		"| primes | 12 | 340 | 9592 |\n" +
+		"| fibonacci | 5 | 51861 | 9227465 |\n" +
+		"| GEOMEAN | - | 4199.1 | - |\n"
+	if got := stdout.String(); got != want {
+		t.Errorf("markdown table:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRunAllMarkdownBaseline(t *testing.T) {
+	benches := []benchmark{stub(t, "primes", "fast"), stub(t, "fibonacci", "slow"), stub(t, "broken", "panic"), stub(t, "nbody", "fast")}
+	baseline := map[string]int64{"primes": 400, "fibonacci": 50000, "broken": 100}
+
+	var stdout, stderr bytes.Buffer
+	if _, _, err := runAll(benches, config{Format: "markdown", Baseline: baseline}, &stdout, &stderr); err != nil {
+		t.Fatalf("runAll: %v", err)
+	}
+	want := "| benchmark | startup_us | compute_us | result | delta |\n" +
+		"|---|---:|---:|---:|---:|\n" +
+		"| primes | 12 | 340 | 9592 | -15.0% |\n" +
+		"| fibonacci | 5 | 51861 | 9227465 | +3.7% |\n" +
+		"| broken | - | - | FAILED | - |\n" +
+		"| nbody | 12 | 340 | 9592 | new |\n" +
+		"| GEOMEAN | - | 1816.6 | - | - |\n"
+	if got := stdout.String(); got != want {
+		t.Errorf("markdown table:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestLoadBaseline(t *testing.T) {
+	dir := t.TempDir()
+	combined := filepath.Join(dir, "runall.json")
+	data := `{"benchmarks":[{"benchmark":"primes","startup_us":8,"compute_us":250,"result":9592},{"benchmark":"mutex","error":"exit status 1"}]}`
+	if err := os.WriteFile(combined, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := loadBaseline(combined)
+	if err != nil || len(got) != 1 || got["primes"] != 250 {
+		t.Errorf("loadBaseline(runall JSON) = %v, %v; want primes 250 only", got, err)
+	}
+
+	versioned := filepath.Join(dir, "baseline.json")
+	var buf bytes.Buffer
+	b, err := result.NewBaseline(result.Combined{Benchmarks: []result.Entry{{Benchmark: "primes", ComputeUS: 300, Result: 9592}}}, benchlib.Host{}, time.Unix(0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := result.WriteBaseline(&buf, b); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(versioned, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := loadBaseline(versioned); err != nil || got["primes"] != 300 {
+		t.Errorf("loadBaseline(baseline file) = %v, %v; want primes 300", got, err)
+	}
+}
+
+func TestRunBaselineNeedsMarkdown(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if got := run([]string{"--baseline=old.json"}, &stdout, &stderr); got != 2 {
+		t.Errorf("run --baseline without --format=markdown = %d, want 2", got)
+	}
+}
+
+func TestRunAllFailures(t *testing.T) {
+	benches := []benchmark{
+		stub(t, "primes", "fast"),
+		stub(t, "fibonacci", "panic"),
+		stub(t, "garbled", "garbled"),
+		{Name: "broken", Err: fmt.Errorf("go build: exit status 1")},
+	}
+
+	var stdout, stderr bytes.Buffer
+	outcomes, ok, err := runAll(benches, config{Format: "text"}, &stdout, &stderr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Error("runAll reported success despite failing benchmarks")
+	}
+	for _, o := range outcomes {
+		if (o.Err != nil) != (o.Name != "primes") {
+			t.Errorf("%s: err = %v", o.Name, o.Err)
+		}
+	}
+	if !strings.Contains(stderr.String(), "panic: Expected 9227465") {
+		t.Errorf("benchmark stderr not forwarded:\n%s", stderr.String())
+	}
+	if got := strings.Count(stdout.String(), "FAILED"); got != 3 {
+		t.Errorf("table has %d FAILED rows, want 3:\n%s", got, stdout.String())
+	}
+}
+
+func TestGeomean(t *testing.T) {
+	failed := outcome{Name: "broken", Err: fmt.Errorf("exit status 1")}
+	tests := []struct {
+		computeUS []int64
+		want      float64
+	}{
+		{[]int64{2, 8}, 4},
+		{[]int64{100, 1000, 10000}, 1000},
+		{[]int64{340, 51861, 340}, 1816.6},
+		{[]int64{7}, 7},
+		{nil, 0},
+	}
+	for _, tt := range tests {
+		outcomes := []outcome{failed}
+		for _, us := range tt.computeUS {
+			outcomes = append(outcomes, outcome{Name: "b", Result: result.Result{ComputeUS: us}})
+		}
+		got, err := geomean(outcomes)
+		if err != nil || math.Abs(got-tt.want) > 0.05 {
+			t.Errorf("geomean(%v) = %v, %v; want %v", tt.computeUS, got, err, tt.want)
+		}
+	}
+
+	for _, us := range []int64{0, -3} {
+		outcomes := []outcome{{Name: "primes", Result: result.Result{ComputeUS: 340}}, {Name: "instant", Result: result.Result{ComputeUS: us}}}
+		if _, err := geomean(outcomes); err == nil || !strings.Contains(err.Error(), "instant") {
+			t.Errorf("geomean with a %dus time = %v, want an error naming the benchmark", us, err)
+		}
+	}
+}
+
+func TestRunAllGeomeanNeedsPositiveTimes(t *testing.T) {
+	benches := []benchmark{stub(t, "primes", "fast"), stub(t, "ackermann", "instant")}
+	var stdout, stderr bytes.Buffer
+	if _, ok, err := runAll(benches, config{Format: "text"}, &stdout, &stderr); err != nil || ok {
+		t.Fatalf("runAll = ok %v, err %v; want a failure", ok, err)
+	}
+	if !strings.Contains(stderr.String(), "runall: geometric mean needs positive compute times, ackermann has 0us") {
+		t.Errorf("stderr does not explain the geomean failure:\n%s", stderr.String())
+	}
+	if strings.Contains(stdout.String(), "GEOMEAN") {
+		t.Errorf("table has a GEOMEAN row despite the error:\n%s", stdout.String())
+	}
+}
+
+func TestRunAllVerifyDeterminism(t *testing.T) {
+	benches := []benchmark{stub(t, "primes", "fast"), stub(t, "channels", "racy")}
+
+	var stdout, stderr bytes.Buffer
+	outcomes, ok, err := runAll(benches, config{Format: "text", Runs: 3}, &stdout, &stderr)
+	if err != nil || ok {
+		t.Fatalf("runAll = ok %v, err %v; want the racy benchmark to fail", ok, err)
+	}
+	if outcomes[0].Err != nil || outcomes[0].Result.Result != 9592 {
+		t.Errorf("stable benchmark outcome = %+v, want success", outcomes[0])
+	}
+	if err := outcomes[1].Err; err == nil || !strings.Contains(err.Error(), "nondeterministic: RESULT") || !strings.Contains(err.Error(), "on run 2") {
+		t.Errorf("racy benchmark error = %v, want nondeterministic on run 2", err)
+	}
+	if !strings.Contains(stderr.String(), "runall: channels: nondeterministic") {
+		t.Errorf("stderr does not name the nondeterministic benchmark:\n%s", stderr.String())
+	}
+
+	// Run once, the racy benchmark cannot be caught.
+	stdout.Reset()
+	if _, ok, err := runAll(benches, config{Format: "text", Runs: 1}, &stdout, &stderr); err != nil || !ok {
+		t.Errorf("runAll with one run = ok %v, err %v", ok, err)
+	}
+}
+
+func TestRunRejectsZeroRuns(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if got := run([]string{"--verify-determinism=0"}, &stdout, &stderr); got != 2 {
+		t.Errorf("run --verify-determinism=0 = %d, want 2", got)
+	}
+}
+
+func TestRunUnknownFormat(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if got := run([]string{"--format=xml"}, &stdout, &stderr); got != 2 {
+		t.Errorf("run --format=xml = %d, want 2", got)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	info, err := describe(stub(t, "primes", "fast"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (benchlib.Info{Name: "primes", Category: benchlib.CategoryNumeric, Expected: 1}); info != want {
+		t.Errorf("describe = %+v, want %+v", info, want)
+	}
+
+	for _, b := range []benchmark{
+		stub(t, "primes", "misnamed"),
+		stub(t, "old", "garbled"), // no --describe support
+		{Name: "broken", Err: fmt.Errorf("go build: exit status 1")},
+	} {
+		if _, err := describe(b); err == nil {
+			t.Errorf("describe(%s) succeeded", b.Name)
+		}
+	}
+}
+
+func TestSelectCategory(t *testing.T) {
+	benches := []benchmark{
+		stub(t, "primes", "fast"),
+		stub(t, "fibonacci", "slow"),
+		stub(t, "nqueens", "panic"),
+		stub(t, "old", "garbled"),
+	}
+	describeAll(benches)
+
+	var names []string
+	for _, b := range selectCategory(benches, benchlib.CategoryRecursion) {
+		names = append(names, b.Name)
+	}
+	// old could not be described, so it is kept to surface the error.
+	if want := []string{"fibonacci", "nqueens", "old"}; !slices.Equal(names, want) {
+		t.Errorf("recursion benchmarks = %v, want %v", names, want)
+	}
+	if got := selectCategory(benches[:3], benchlib.CategoryText); len(got) != 0 {
+		t.Errorf("text benchmarks = %v, want none", got)
+	}
+}
+
+func TestPrintList(t *testing.T) {
+	benches := []benchmark{
+		{Name: "primes", Info: benchlib.Info{Name: "primes", Category: benchlib.CategoryNumeric, Expected: 9592}},
+		{Name: "fibonacci", Info: benchlib.Info{Name: "fibonacci", Category: benchlib.CategoryRecursion, Expected: 9227465}},
+		{Name: "broken", Err: fmt.Errorf("go build: exit status 1")},
+		{Name: "mandelbrot", Info: benchlib.Info{Name: "mandelbrot", Category: benchlib.CategoryNumeric, Expected: 380263}},
+	}
+	var stdout, stderr bytes.Buffer
+	ok, err := printList(&stdout, &stderr, benches)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Error("printList reported success despite a broken benchmark")
+	}
+	if !strings.Contains(stderr.String(), "broken: go build") {
+		t.Errorf("stderr = %q, want the broken benchmark reported", stderr.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	want := [][]string{
+		{"CATEGORY", "BENCHMARK", "EXPECTED"},
+		{"numeric", "mandelbrot", "380263"},
+		{"primes", "9592"},
+		{"recursion", "fibonacci", "9227465"},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(want), stdout.String())
+	}
+	for i := range want {
+		if got := strings.Fields(lines[i]); !slices.Equal(got, want[i]) {
+			t.Errorf("line %d = %q, want %q", i, got, want[i])
+		}
+	}
+}
+
+func TestRunUnknownCategory(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if got := run([]string{"--category=fast"}, &stdout, &stderr); got != 2 {
+		t.Errorf("run --category=fast = %d, want 2", got)
+	}
+	if !strings.Contains(stderr.String(), "unknown category") {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+}
@@ -0,0 +1,195 @@
+// Command speedup compares the compute times of the same benchmarks in two
+// languages, Go and Ruchy.
+//
+// Both inputs are combined results JSON as written by `runall
+// --format=json`. For each benchmark speedup prints the Ruchy speedup over
+// Go, go_us / ruchy_us, so values above 1 mean Ruchy is faster, and names
+// the faster language. The last line is the geometric mean of the speedups,
+// which weights a 2x win and a 2x loss equally.
+//
+// Usage:
+//
+//	speedup --go=go.json --ruchy=ruchy.json
+//
+// Both files must hold the same set of benchmarks, all successful. speedup
+// exits 2 on usage errors, unreadable input, or mismatched benchmark sets.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/paiml/ruchy-docker/result"
+)
+
+// row is the comparison of one benchmark.
+type row struct {
+	Name    string
+	GoUS    int64
+	RuchyUS int64
+	Speedup float64 // GoUS / RuchyUS
+}
+
+// tieTolerance absorbs floating-point noise, such as a geometric mean of
+// exact reciprocals coming out as 1.0000000000000002.
+const tieTolerance = 1e-9
+
+// faster names the faster language for r.
+func (r row) faster() string {
+	switch {
+	case r.Speedup > 1+tieTolerance:
+		return "ruchy"
+	case r.Speedup < 1-tieTolerance:
+		return "go"
+	default:
+		return "tie"
+	}
+}
+
+// computeTimes returns benchmark name → compute_us for c. Failed or
+// duplicate benchmarks and non-positive times are errors.
+func computeTimes(c result.Combined) (map[string]int64, error) {
+	times := make(map[string]int64, len(c.Benchmarks))
+	for _, e := range c.Benchmarks {
+		if e.Error != "" {
+			return nil, fmt.Errorf("benchmark %q failed: %s", e.Benchmark, e.Error)
+		}
+		if _, dup := times[e.Benchmark]; dup {
+			return nil, fmt.Errorf("duplicate benchmark %q", e.Benchmark)
+		}
+		if e.ComputeUS <= 0 {
+			return nil, fmt.Errorf("benchmark %q: compute time must be positive, got %d", e.Benchmark, e.ComputeUS)
+		}
+		times[e.Benchmark] = e.ComputeUS
+	}
+	return times, nil
+}
+
+// onlyIn returns the sorted names in a that are not in b.
+func onlyIn(a, b map[string]int64) []string {
+	var names []string
+	for name := range a {
+		if _, ok := b[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// compare pairs the Go and Ruchy times by benchmark name, sorted by name.
+// The two sets must name exactly the same benchmarks.
+func compare(goTimes, ruchyTimes map[string]int64) ([]row, error) {
+	var problems []string
+	if missing := onlyIn(goTimes, ruchyTimes); len(missing) > 0 {
+		problems = append(problems, "missing from Ruchy results: "+strings.Join(missing, ", "))
+	}
+	if missing := onlyIn(ruchyTimes, goTimes); len(missing) > 0 {
+		problems = append(problems, "missing from Go results: "+strings.Join(missing, ", "))
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("benchmark sets differ: %s", strings.Join(problems, "; "))
+	}
+	if len(goTimes) == 0 {
+		return nil, fmt.Errorf("no benchmarks to compare")
+	}
+
+	rows := make([]row, 0, len(goTimes))
+	for name, g := range goTimes {
+		r := ruchyTimes[name]
+		rows = append(rows, row{Name: name, GoUS: g, RuchyUS: r, Speedup: float64(g) / float64(r)})
+	}
+	slices.SortFunc(rows, func(a, b row) int { return strings.Compare(a.Name, b.Name) })
+	return rows, nil
+}
+
+// geomean returns the geometric mean of the rows' speedups, computed in
+// log space so long lists cannot overflow.
+func geomean(rows []row) float64 {
+	var sum float64
+	for _, r := range rows {
+		sum += math.Log(r.Speedup)
+	}
+	return math.Exp(sum / float64(len(rows)))
+}
+
+// printTable writes rows and the geometric mean as an aligned table.
+func printTable(w io.Writer, rows []row) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "BENCHMARK\tGO_US\tRUCHY_US\tSPEEDUP\tFASTER")
+	for _, r := range rows {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.2fx\t%s\n", r.Name, r.GoUS, r.RuchyUS, r.Speedup, r.faster())
+	}
+	overall := row{Name: "GEOMEAN", Speedup: geomean(rows)}
+	fmt.Fprintf(tw, "%s\t\t\t%.2fx\t%s\n", overall.Name, overall.Speedup, overall.faster())
+	return tw.Flush()
+}
+
+// loadTimes reads a combined results file and returns its compute times.
+func loadTimes(path string) (map[string]int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	c, err := result.DecodeCombined(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	times, err := computeTimes(c)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return times, nil
+}
+
+// run is main with injectable arguments and output; it returns the exit
+// status.
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("speedup", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	goPath := fs.String("go", "", "combined results JSON for the Go benchmarks")
+	ruchyPath := fs.String("ruchy", "", "combined results JSON for the Ruchy benchmarks")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: speedup --go=go.json --ruchy=ruchy.json")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *goPath == "" || *ruchyPath == "" || fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+
+	goTimes, err := loadTimes(*goPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "speedup: %v\n", err)
+		return 2
+	}
+	ruchyTimes, err := loadTimes(*ruchyPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "speedup: %v\n", err)
+		return 2
+	}
+	rows, err := compare(goTimes, ruchyTimes)
+	if err != nil {
+		fmt.Fprintf(stderr, "speedup: %v\n", err)
+		return 2
+	}
+	if err := printTable(stdout, rows); err != nil {
+		fmt.Fprintf(stderr, "speedup: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompareMixedWins(t *testing.T) {
+	goTimes := map[string]int64{"fibonacci": 1000, "primes": 200, "nbody": 3000}
+	ruchyTimes := map[string]int64{"fibonacci": 500, "primes": 400, "nbody": 3000}
+
+	rows, err := compare(goTimes, ruchyTimes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		name    string
+		speedup float64
+		faster  string
+	}{
+		{"fibonacci", 2, "ruchy"},
+		{"nbody", 1, "tie"},
+		{"primes", 0.5, "go"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(want))
+	}
+	for i, w := range want {
+		r := rows[i]
+		if r.Name != w.name || r.Speedup != w.speedup || r.faster() != w.faster {
+			t.Errorf("row %d = %s %.2fx %s, want %s %.2fx %s", i, r.Name, r.Speedup, r.faster(), w.name, w.speedup, w.faster)
+		}
+	}
+	// A 2x win and a 2x loss cancel out.
+	if g := geomean(rows); math.Abs(g-1) > 1e-12 {
+		t.Errorf("geomean = %v, want 1", g)
+	}
+}
+
+func TestGeomean(t *testing.T) {
+	tests := []struct {
+		speedups []float64
+		want     float64
+	}{
+		{[]float64{4}, 4},
+		{[]float64{1, 4}, 2},
+		{[]float64{2, 4, 8}, 4},
+		{[]float64{0.25, 0.5, 0.5}, math.Cbrt(0.0625)},
+	}
+	for _, tt := range tests {
+		var rows []row
+		for _, s := range tt.speedups {
+			rows = append(rows, row{Speedup: s})
+		}
+		if got := geomean(rows); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("geomean(%v) = %v, want %v", tt.speedups, got, tt.want)
+		}
+	}
+}
+
+func TestCompareMismatch(t *testing.T) {
+	_, err := compare(map[string]int64{"a": 1, "b": 1}, map[string]int64{"a": 1, "c": 1})
+	if err == nil {
+		t.Fatal("no error for differing benchmark sets")
+	}
+	for _, want := range []string{"missing from Ruchy results: b", "missing from Go results: c"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+	if _, err := compare(map[string]int64{}, map[string]int64{}); err == nil {
+		t.Error("no error for empty benchmark sets")
+	}
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	goPath := writeFile(t, dir, "go.json", `{"benchmarks":[
+		{"benchmark":"fibonacci","startup_us":5,"compute_us":1000,"result":9227465},
+		{"benchmark":"primes","startup_us":8,"compute_us":200,"result":9592}]}`)
+	ruchyPath := writeFile(t, dir, "ruchy.json", `{"benchmarks":[
+		{"benchmark":"primes","startup_us":9,"compute_us":100,"result":9592},
+		{"benchmark":"fibonacci","startup_us":6,"compute_us":2000,"result":9227465}]}`)
+	shortPath := writeFile(t, dir, "short.json", `{"benchmarks":[
+		{"benchmark":"primes","startup_us":9,"compute_us":100,"result":9592}]}`)
+	failedPath := writeFile(t, dir, "failed.json", `{"benchmarks":[
+		{"benchmark":"primes","startup_us":0,"compute_us":0,"result":0,"error":"exit status 1"},
+		{"benchmark":"fibonacci","startup_us":6,"compute_us":2000,"result":9227465}]}`)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"--go=" + goPath, "--ruchy=" + ruchyPath}, &stdout, &stderr); code != 0 {
+		t.Fatalf("run = %d, stderr: %s", code, stderr.String())
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	want := []string{
+		"BENCHMARK GO_US RUCHY_US SPEEDUP FASTER",
+		"fibonacci 1000 2000 0.50x go",
+		"primes 200 100 2.00x ruchy",
+		"GEOMEAN 1.00x tie",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("output has %d lines, want %d:\n%s", len(lines), len(want), stdout.String())
+	}
+	for i, w := range want {
+		if got := strings.Join(strings.Fields(lines[i]), " "); got != w {
+			t.Errorf("line %d = %q, want %q", i, got, w)
+		}
+	}
+
+	for _, args := range [][]string{
+		{"--go=" + goPath, "--ruchy=" + shortPath},
+		{"--go=" + goPath, "--ruchy=" + failedPath},
+		{"--go=" + goPath},
+		{"--go=" + goPath, "--ruchy=" + filepath.Join(dir, "absent.json")},
+	} {
+		stderr.Reset()
+		if code := run(args, &stdout, &stderr); code != 2 {
+			t.Errorf("run(%v) = %d, want 2", args, code)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+// Command trend plots one benchmark's compute time across stored
+// baselines as an SVG line chart.
+//
+// The input is a directory of baseline files written by `baseline save`,
+// for example one saved per day. Every *.json file in it must be a
+// baseline; those that lack the benchmark are skipped. Points are placed
+// by baseline timestamp, oldest on the left, on a time axis scaled to the
+// span of the data, and the compute axis runs from zero to the slowest
+// point.
+//
+// Usage:
+//
+//	trend [--width=800] [--height=400] baselines/ fibonacci > trend.svg
+//
+// trend exits 2 on usage errors or unreadable baselines, and 1 if no
+// baseline has the benchmark.
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"flag"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/paiml/ruchy-docker/result"
+)
+
+// Chart margins in SVG user units, leaving room for the title and axis
+// labels.
+const (
+	marginLeft   = 80
+	marginRight  = 20
+	marginTop    = 40
+	marginBottom = 40
+)
+
+// point is one baseline's measurement of the benchmark.
+type point struct {
+	Time      time.Time
+	ComputeUS int64
+	File      string
+}
+
+// loadPoints reads every *.json baseline in dir and returns the compute
+// times recorded for benchmark, sorted by timestamp and then file name.
+func loadPoints(dir, benchmark string) ([]point, error) {
+	if _, err := os.ReadDir(dir); err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var points []point
+	for _, path := range paths {
+		b, err := readBaseline(path)
+		if err != nil {
+			return nil, err
+		}
+		e, ok := b.Benchmarks[benchmark]
+		if !ok {
+			continue
+		}
+		points = append(points, point{Time: b.Timestamp, ComputeUS: e.ComputeUS, File: filepath.Base(path)})
+	}
+	slices.SortFunc(points, func(a, b point) int {
+		return cmp.Or(a.Time.Compare(b.Time), cmp.Compare(a.File, b.File))
+	})
+	return points, nil
+}
+
+// readBaseline opens and decodes one baseline file.
+func readBaseline(path string) (result.Baseline, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return result.Baseline{}, err
+	}
+	defer f.Close()
+	b, err := result.ReadBaseline(f)
+	if err != nil {
+		return result.Baseline{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return b, nil
+}
+
+// chart maps points to SVG coordinates inside a width×height viewBox.
+type chart struct {
+	width, height int
+	first, last   time.Time
+	maxUS         int64
+}
+
+func newChart(points []point, width, height int) chart {
+	c := chart{width: width, height: height, first: points[0].Time, last: points[len(points)-1].Time}
+	for _, p := range points {
+		c.maxUS = max(c.maxUS, p.ComputeUS)
+	}
+	return c
+}
+
+// x returns the horizontal position of t. With a single timestamp every
+// point is centered.
+func (c chart) x(t time.Time) float64 {
+	left, right := float64(marginLeft), float64(c.width-marginRight)
+	span := c.last.Sub(c.first)
+	if span <= 0 {
+		return (left + right) / 2
+	}
+	return left + float64(t.Sub(c.first))/float64(span)*(right-left)
+}
+
+// y returns the vertical position of a compute time; zero is the bottom
+// axis.
+func (c chart) y(us int64) float64 {
+	top, bottom := float64(marginTop), float64(c.height-marginBottom)
+	if c.maxUS <= 0 {
+		return bottom
+	}
+	return bottom - float64(us)/float64(c.maxUS)*(bottom-top)
+}
+
+// writeSVG renders the chart of benchmark's points, which must be
+// non-empty and sorted by time.
+func writeSVG(w io.Writer, benchmark string, points []point, width, height int) error {
+	c := newChart(points, width, height)
+	name := html.EscapeString(benchmark)
+	left, right := marginLeft, width-marginRight
+	top, bottom := marginTop, height-marginBottom
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d" font-family="sans-serif" font-size="12">`+"\n", width, height, width, height)
+	fmt.Fprintf(bw, "<title>%s compute time</title>\n", name)
+	fmt.Fprintf(bw, `<text x="%d" y="%d" text-anchor="middle" font-size="16">%s: compute_us over time</text>`+"\n", width/2, top/2+6, name)
+
+	// Axes with their end labels.
+	fmt.Fprintf(bw, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#444"/>`+"\n", left, bottom, right, bottom)
+	fmt.Fprintf(bw, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#444"/>`+"\n", left, top, left, bottom)
+	fmt.Fprintf(bw, `<text x="%d" y="%d" text-anchor="end">0</text>`+"\n", left-6, bottom)
+	fmt.Fprintf(bw, `<text x="%d" y="%d" text-anchor="end">%d µs</text>`+"\n", left-6, top+4, c.maxUS)
+	fmt.Fprintf(bw, `<text x="%d" y="%d" text-anchor="start">%s</text>`+"\n", left, bottom+20, c.first.UTC().Format(time.DateOnly))
+	fmt.Fprintf(bw, `<text x="%d" y="%d" text-anchor="end">%s</text>`+"\n", right, bottom+20, c.last.UTC().Format(time.DateOnly))
+
+	bw.WriteString(`<polyline fill="none" stroke="#6a8cc7" stroke-width="2" points="`)
+	for i, p := range points {
+		if i > 0 {
+			bw.WriteString(" ")
+		}
+		fmt.Fprintf(bw, "%.1f,%.1f", c.x(p.Time), c.y(p.ComputeUS))
+	}
+	bw.WriteString(`"/>` + "\n")
+	for _, p := range points {
+		fmt.Fprintf(bw, `<circle class="point" cx="%.1f" cy="%.1f" r="3" fill="#6a8cc7"><title>%s: %d µs (%s)</title></circle>`+"\n",
+			c.x(p.Time), c.y(p.ComputeUS), p.Time.UTC().Format(time.RFC3339), p.ComputeUS, html.EscapeString(p.File))
+	}
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
+
+// run is main with injectable arguments and output; it returns the exit
+// status.
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("trend", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	width := fs.Int("width", 800, "chart width in SVG units")
+	height := fs.Int("height", 400, "chart height in SVG units")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: trend [--width=W] [--height=H] baseline-dir benchmark")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+	if *width <= marginLeft+marginRight || *height <= marginTop+marginBottom {
+		fmt.Fprintf(stderr, "trend: chart must be larger than %dx%d, got %dx%d\n", marginLeft+marginRight, marginTop+marginBottom, *width, *height)
+		return 2
+	}
+	dir, benchmark := fs.Arg(0), fs.Arg(1)
+
+	points, err := loadPoints(dir, benchmark)
+	if err != nil {
+		fmt.Fprintf(stderr, "trend: %v\n", err)
+		return 2
+	}
+	if len(points) == 0 {
+		fmt.Fprintf(stderr, "trend: no baseline in %s has benchmark %q\n", dir, benchmark)
+		return 1
+	}
+	if err := writeSVG(stdout, benchmark, points, *width, *height); err != nil {
+		fmt.Fprintf(stderr, "trend: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+	"github.com/paiml/ruchy-docker/result"
+)
+
+// writeBaseline stores a baseline taken at day days after 2026-01-01 with
+// the given compute times.
+func writeBaseline(t *testing.T, dir, file string, day int, computeUS map[string]int64) {
+	t.Helper()
+	var c result.Combined
+	for name, us := range computeUS {
+		c.Benchmarks = append(c.Benchmarks, result.Entry{Benchmark: name, ComputeUS: us})
+	}
+	at := time.Date(2026, 1, 1+day, 0, 0, 0, 0, time.UTC)
+	b, err := result.NewBaseline(c, benchlib.Host{}, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := result.WriteBaseline(&buf, b); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, file), buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// baselineDir returns a directory of four baselines, named out of
+// chronological order, one of which lacks primes.
+func baselineDir(t *testing.T) string {
+	dir := t.TempDir()
+	writeBaseline(t, dir, "a.json", 10, map[string]int64{"primes": 300, "fibonacci": 50000})
+	writeBaseline(t, dir, "b.json", 0, map[string]int64{"primes": 400})
+	writeBaseline(t, dir, "c.json", 5, map[string]int64{"fibonacci": 52000})
+	writeBaseline(t, dir, "d.json", 20, map[string]int64{"primes": 200})
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a baseline"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestLoadPoints(t *testing.T) {
+	points, err := loadPoints(baselineDir(t), "primes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, p := range points {
+		got = append(got, p.File+":"+strconv.FormatInt(p.ComputeUS, 10))
+	}
+	if want := "b.json:400 a.json:300 d.json:200"; strings.Join(got, " ") != want {
+		t.Errorf("points = %s, want %s", strings.Join(got, " "), want)
+	}
+}
+
+var circleRE = regexp.MustCompile(`<circle class="point" cx="([0-9.]+)" cy="([0-9.]+)"`)
+
+func TestRunSVG(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{baselineDir(t), "primes"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("run = %d, stderr: %s", code, stderr.String())
+	}
+	svg := stdout.String()
+
+	// The output must be well-formed XML.
+	dec := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		if _, err := dec.Token(); err != nil {
+			if err != io.EOF {
+				t.Fatalf("SVG is not well-formed: %v\n%s", err, svg)
+			}
+			break
+		}
+	}
+	if !strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 800 400" `) {
+		t.Errorf("unexpected root element:\n%s", svg)
+	}
+
+	circles := circleRE.FindAllStringSubmatch(svg, -1)
+	if len(circles) != 3 {
+		t.Fatalf("got %d data points, want 3:\n%s", len(circles), svg)
+	}
+	// Days 0, 10 and 20 span the plot area evenly; compute times fall.
+	var prevX, prevY float64
+	for i, m := range circles {
+		x, _ := strconv.ParseFloat(m[1], 64)
+		y, _ := strconv.ParseFloat(m[2], 64)
+		if x < marginLeft || x > 800-marginRight || y < marginTop || y > 400-marginBottom {
+			t.Errorf("point %d at (%g, %g) is outside the plot area", i, x, y)
+		}
+		if i > 0 && (x <= prevX || y <= prevY) {
+			t.Errorf("point %d at (%g, %g) does not follow (%g, %g)", i, x, y, prevX, prevY)
+		}
+		prevX, prevY = x, y
+	}
+	if got := circles[1][1]; got != "430.0" {
+		t.Errorf("middle point x = %s, want 430.0", got)
+	}
+	// The slowest point is at the top of the compute axis.
+	if got := circles[0][2]; got != strconv.Itoa(marginTop)+".0" {
+		t.Errorf("slowest point y = %s, want %d.0", got, marginTop)
+	}
+}
+
+func TestRunSinglePoint(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"--width=400", "--height=300", baselineDir(t), "fibonacci"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("run = %d, stderr: %s", code, stderr.String())
+	}
+	if got := len(circleRE.FindAllString(stdout.String(), -1)); got != 2 {
+		t.Errorf("got %d data points, want 2", got)
+	}
+	if !strings.Contains(stdout.String(), `viewBox="0 0 400 300"`) {
+		t.Errorf("viewBox does not follow --width/--height:\n%s", stdout.String())
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	dir := baselineDir(t)
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{[]string{dir}, 2},
+		{[]string{"--width=50", dir, "primes"}, 2},
+		{[]string{filepath.Join(dir, "missing"), "primes"}, 2},
+		{[]string{dir, "nbody"}, 1},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if got := run(tt.args, &stdout, &stderr); got != tt.want {
+			t.Errorf("run(%q) = %d, want %d", tt.args, got, tt.want)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	if got := run([]string{dir, "primes"}, &stdout, &stderr); got != 2 {
+		t.Errorf("run with an unreadable baseline = %d, want 2", got)
+	}
+}
@@ -0,0 +1,177 @@
+// Command validate checks that every Go benchmark still computes its
+// registered expected result, without caring how long it takes.
+//
+// A benchmark is any directory under --dir (default "benchmarks") that
+// contains a main.go. Each one is built with `go build`, asked for its
+// registered metadata with --describe, and run once with default flags:
+// a single compute run with no warmup and no repeated-run statistics. The
+// BENCH_ITERATIONS, BENCH_WARMUP and BENCH_FORMAT variables are removed
+// from its environment so they cannot change that. Its
+// RESULT, parsed with the result package, must equal the registered
+// expected value.
+//
+// Usage:
+//
+//	validate [--dir=benchmarks] [--category=NAME]
+//
+// validate prints one PASS or FAIL line per benchmark and exits 1 if any
+// benchmark fails to build, describe itself, run or match, and 2 on usage
+// errors. It is meant for CI, where correctness matters and timings on
+// shared runners do not.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+	"github.com/paiml/ruchy-docker/internal/benchexec"
+	"github.com/paiml/ruchy-docker/result"
+)
+
+// benchmark is one runnable benchmark program.
+type benchmark struct {
+	Name string
+	Cmd  []string // program and arguments
+	Err  error    // set if the benchmark could not be built
+}
+
+// check is the outcome of validating one benchmark. Err is nil when it
+// passed.
+type check struct {
+	Name string
+	Got  int64
+	Err  error
+}
+
+// lastLine returns the last non-empty line of s, which for a failed
+// benchmark is its FAILURE message or panic.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return lines[len(lines)-1]
+}
+
+// validate runs b once and compares its RESULT with info.Expected. A
+// mismatch is reported as such even when the benchmark also exits non-zero
+// for it, which benchmarks that validate themselves do.
+func validate(b benchmark, info benchlib.Info) check {
+	c := check{Name: b.Name}
+	var stdout, stderr bytes.Buffer
+	// Without the BENCH_* variables, the defaults are one run, no warmup
+	// and the text format result.Parse reads.
+	cmd := benchexec.Command(b.Cmd, benchlib.EnvIterations, benchlib.EnvWarmup, benchlib.EnvFormat)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	runErr := cmd.Run()
+	res, parseErr := result.Parse(&stdout)
+	switch {
+	case parseErr == nil && res.Result != info.Expected:
+		c.Err = fmt.Errorf("expected %d got %d", info.Expected, res.Result)
+	case runErr != nil && stderr.Len() > 0:
+		c.Err = fmt.Errorf("%v: %s", runErr, lastLine(stderr.String()))
+	case runErr != nil:
+		c.Err = runErr
+	case parseErr != nil:
+		c.Err = parseErr
+	}
+	c.Got = res.Result
+	return c
+}
+
+// validateAll checks each benchmark in turn and writes a PASS/FAIL table
+// to w. With a non-empty category, benchmarks registered in another one
+// are skipped without being run; those that fail before describing
+// themselves are still reported. It returns whether every checked
+// benchmark passed.
+func validateAll(benches []benchmark, category string, w io.Writer) (bool, error) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "BENCHMARK\tSTATUS\tDETAIL")
+	ok := true
+	for _, b := range benches {
+		c := check{Name: b.Name, Err: b.Err}
+		if c.Err == nil {
+			info, err := benchexec.Describe(b.Name, b.Cmd)
+			switch {
+			case err != nil:
+				c.Err = err
+			case category != "" && info.Category != category:
+				continue
+			default:
+				c = validate(b, info)
+			}
+		}
+		if c.Err != nil {
+			ok = false
+			// Build errors span several lines; keep the table to one.
+			detail, _, _ := strings.Cut(c.Err.Error(), "\n")
+			fmt.Fprintf(tw, "%s\tFAIL\t%s\n", c.Name, detail)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\tPASS\t%d\n", c.Name, c.Got)
+	}
+	return ok, tw.Flush()
+}
+
+// run is main with injectable arguments and output; it returns the exit
+// status.
+func run(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dir := fs.String("dir", "benchmarks", "directory containing one subdirectory per benchmark")
+	category := fs.String("category", "", "validate only the benchmarks in this category: "+strings.Join(benchlib.Categories, ", "))
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fmt.Fprintf(stderr, "validate: unexpected arguments %q\n", fs.Args())
+		return 2
+	}
+	if *category != "" && !slices.Contains(benchlib.Categories, *category) {
+		fmt.Fprintf(stderr, "validate: unknown category %q (want one of %s)\n", *category, strings.Join(benchlib.Categories, ", "))
+		return 2
+	}
+
+	names, err := benchexec.Discover(*dir)
+	if err != nil {
+		fmt.Fprintf(stderr, "validate: %v\n", err)
+		return 2
+	}
+	if len(names) == 0 {
+		fmt.Fprintf(stderr, "validate: no benchmarks found under %s\n", *dir)
+		return 2
+	}
+
+	binDir, err := os.MkdirTemp("", "validate-")
+	if err != nil {
+		fmt.Fprintf(stderr, "validate: %v\n", err)
+		return 2
+	}
+	defer os.RemoveAll(binDir)
+
+	benches := make([]benchmark, 0, len(names))
+	for _, name := range names {
+		bin := filepath.Join(binDir, name)
+		err := benchexec.Build(filepath.Join(*dir, name), bin)
+		benches = append(benches, benchmark{Name: name, Cmd: []string{bin}, Err: err})
+	}
+
+	ok, err := validateAll(benches, *category, stdout)
+	if err != nil {
+		fmt.Fprintf(stderr, "validate: %v\n", err)
+		return 1
+	}
+	if !ok {
+		return 1
+	}
+	return 0
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
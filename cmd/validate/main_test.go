@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+// stubExpected is the RESULT every stub registers.
+const stubExpected = 9592
+
+// TestMain lets the test binary double as a stub benchmark: when
+// VALIDATE_STUB is set it behaves as that mode and exits. With --describe
+// it prints the metadata of a numeric benchmark named VALIDATE_STUB_NAME
+// expecting stubExpected.
+func TestMain(m *testing.M) {
+	mode := os.Getenv("VALIDATE_STUB")
+	if mode != "" && slices.Contains(os.Args[1:], "--describe") {
+		if mode == "nodescribe" {
+			fmt.Fprintln(os.Stderr, "flag provided but not defined: -describe")
+			os.Exit(2)
+		}
+		json.NewEncoder(os.Stdout).Encode(benchlib.Info{Name: os.Getenv("VALIDATE_STUB_NAME"), Category: benchlib.CategoryNumeric, Expected: stubExpected})
+		os.Exit(0)
+	}
+	switch mode {
+	case "":
+		os.Exit(m.Run())
+	case "pass":
+		fmt.Println("STARTUP_TIME_US: 12")
+		fmt.Println("COMPUTE_TIME_US: 340")
+		fmt.Printf("RESULT: %d\n", stubExpected)
+	case "wrong":
+		// A benchmark whose own Validate catches the bad result.
+		fmt.Println("STARTUP_TIME_US: 12")
+		fmt.Println("COMPUTE_TIME_US: 340")
+		fmt.Println("RESULT: 9591")
+		fmt.Fprintf(os.Stderr, "FAILURE: expected %d got 9591\n", stubExpected)
+		os.Exit(1)
+	case "wrongsilent":
+		// A wrong result the benchmark itself does not notice.
+		fmt.Println("STARTUP_TIME_US: 12")
+		fmt.Println("COMPUTE_TIME_US: 340")
+		fmt.Println("RESULT: 1")
+	case "env":
+		// Fails if a BENCH_* variable could have changed how it ran.
+		for _, v := range []string{benchlib.EnvIterations, benchlib.EnvWarmup, benchlib.EnvFormat} {
+			if os.Getenv(v) != "" {
+				fmt.Fprintf(os.Stderr, "FAILURE: run with %s set\n", v)
+				os.Exit(1)
+			}
+		}
+		fmt.Println("STARTUP_TIME_US: 12")
+		fmt.Println("COMPUTE_TIME_US: 340")
+		fmt.Printf("RESULT: %d\n", stubExpected)
+	case "crash":
+		fmt.Println("STARTUP_TIME_US: 12")
+		fmt.Fprintln(os.Stderr, "goroutine 1 [running]:")
+		fmt.Fprintln(os.Stderr, "panic: index out of range")
+		os.Exit(2)
+	}
+	os.Exit(0)
+}
+
+// stub returns a benchmark that re-executes the test binary in mode.
+func stub(t *testing.T, name, mode string) benchmark {
+	t.Helper()
+	script := filepath.Join(t.TempDir(), name)
+	body := fmt.Sprintf("#!/bin/sh\nVALIDATE_STUB=%s VALIDATE_STUB_NAME=%s exec %q \"$@\"\n", mode, name, os.Args[0])
+	if err := os.WriteFile(script, []byte(body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return benchmark{Name: name, Cmd: []string{script}}
+}
+
+// tableRows returns the fields of each line of a validateAll table after
+// the header, keyed by benchmark name.
+func tableRows(t *testing.T, out string) map[string][]string {
+	t.Helper()
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if got := strings.Fields(lines[0]); !slices.Equal(got, []string{"BENCHMARK", "STATUS", "DETAIL"}) {
+		t.Fatalf("header = %q", got)
+	}
+	rows := make(map[string][]string)
+	for _, line := range lines[1:] {
+		f := strings.Fields(line)
+		rows[f[0]] = f[1:]
+	}
+	return rows
+}
+
+func TestValidateAllPassAndFail(t *testing.T) {
+	benches := []benchmark{stub(t, "primes", "pass"), stub(t, "fibonacci", "wrong")}
+
+	var stdout bytes.Buffer
+	ok, err := validateAll(benches, "", &stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Errorf("validateAll = ok with a wrong benchmark:\n%s", stdout.String())
+	}
+	rows := tableRows(t, stdout.String())
+	if got := rows["primes"]; !slices.Equal(got, []string{"PASS", "9592"}) {
+		t.Errorf("primes row = %q, want PASS 9592", got)
+	}
+	if got := strings.Join(rows["fibonacci"], " "); got != "FAIL expected 9592 got 9591" {
+		t.Errorf("fibonacci row = %q, want FAIL with the mismatch", got)
+	}
+}
+
+func TestValidateAllPasses(t *testing.T) {
+	benches := []benchmark{stub(t, "primes", "pass"), stub(t, "sieve", "pass")}
+	var stdout bytes.Buffer
+	if ok, err := validateAll(benches, "", &stdout); !ok || err != nil {
+		t.Errorf("validateAll = %v, %v; want ok:\n%s", ok, err, stdout.String())
+	}
+}
+
+func TestValidateIgnoresBenchEnv(t *testing.T) {
+	t.Setenv(benchlib.EnvIterations, "30")
+	t.Setenv(benchlib.EnvWarmup, "auto")
+	t.Setenv(benchlib.EnvFormat, benchlib.FormatJSON)
+	var stdout bytes.Buffer
+	if ok, err := validateAll([]benchmark{stub(t, "primes", "env")}, "", &stdout); !ok || err != nil {
+		t.Errorf("validateAll = %v, %v; want ok:\n%s", ok, err, stdout.String())
+	}
+}
+
+func TestValidateFailures(t *testing.T) {
+	tests := []struct {
+		bench      benchmark
+		wantDetail string
+	}{
+		{stub(t, "silent", "wrongsilent"), "expected 9592 got 1"},
+		{stub(t, "crash", "crash"), "exit status 2: panic: index out of range"},
+		{stub(t, "nodescribe", "nodescribe"), "--describe: exit status 2"},
+		{benchmark{Name: "broken", Err: errors.New("go build: exit status 1\n./main.go:3: syntax error")}, "go build: exit status 1"},
+	}
+	for _, tt := range tests {
+		var stdout bytes.Buffer
+		ok, err := validateAll([]benchmark{tt.bench}, "", &stdout)
+		if err != nil || ok {
+			t.Errorf("%s: validateAll = %v, %v; want a failure", tt.bench.Name, ok, err)
+		}
+		row := tableRows(t, stdout.String())[tt.bench.Name]
+		if got := strings.Join(row, " "); got != "FAIL "+tt.wantDetail {
+			t.Errorf("%s: row = %q, want FAIL %s", tt.bench.Name, got, tt.wantDetail)
+		}
+	}
+}
+
+func TestValidateAllCategory(t *testing.T) {
+	// The stubs are numeric, so --category=text skips the wrong one
+	// without running it, while a build failure is still reported.
+	benches := []benchmark{stub(t, "fibonacci", "wrong"), {Name: "broken", Err: errors.New("go build: failed")}}
+	var stdout bytes.Buffer
+	ok, err := validateAll(benches, benchlib.CategoryText, &stdout)
+	if err != nil || ok {
+		t.Fatalf("validateAll = %v, %v; want the build failure", ok, err)
+	}
+	rows := tableRows(t, stdout.String())
+	if _, ran := rows["fibonacci"]; ran || len(rows) != 1 {
+		t.Errorf("rows = %q, want only broken", rows)
+	}
+
+	stdout.Reset()
+	if ok, _ := validateAll(benches[:1], benchlib.CategoryNumeric, &stdout); ok {
+		t.Errorf("numeric category skipped the wrong numeric benchmark:\n%s", stdout.String())
+	}
+}
+
+func TestRunUsageErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{"--category=fast"},
+		{"extra"},
+		{"--dir=" + filepath.Join(t.TempDir(), "missing")},
+		{"--dir=" + t.TempDir()},
+	} {
+		var stdout, stderr bytes.Buffer
+		if got := run(args, &stdout, &stderr); got != 2 {
+			t.Errorf("run(%q) = %d, want 2; stderr: %s", args, got, stderr.String())
+		}
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/paiml/ruchy-docker
+
+go 1.23
@@ -0,0 +1,87 @@
+// Package benchexec finds, builds and describes the benchmark programs
+// under a directory, for the commands that run them: runall and validate.
+//
+// A benchmark is any directory under the root that contains a main.go. It
+// registers its metadata with benchlib.Register and prints it as JSON when
+// run with --describe.
+package benchexec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+// Discover returns the names of the directories under root that contain a
+// main.go, in lexical order.
+func Discover(root string) ([]string, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(root, e.Name(), "main.go")); err == nil {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
+// Build compiles the benchmark package in dir into the binary out.
+func Build(dir, out string) error {
+	cmd := exec.Command("go", "build", "-o", out, "./"+filepath.ToSlash(dir))
+	if msg, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("go build: %v\n%s", err, msg)
+	}
+	return nil
+}
+
+// Describe runs the program and arguments in cmd with --describe and
+// returns its registered metadata, which must be a single benchmark
+// registered under name.
+func Describe(name string, cmd []string) (benchlib.Info, error) {
+	out, err := exec.Command(cmd[0], append(cmd[1:], "--describe")...).Output()
+	if err != nil {
+		return benchlib.Info{}, fmt.Errorf("--describe: %v", err)
+	}
+	var infos []benchlib.Info
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for dec.More() {
+		var info benchlib.Info
+		if err := dec.Decode(&info); err != nil {
+			return benchlib.Info{}, fmt.Errorf("--describe: %v", err)
+		}
+		infos = append(infos, info)
+	}
+	switch {
+	case len(infos) != 1:
+		return benchlib.Info{}, fmt.Errorf("--describe listed %d benchmarks, want 1", len(infos))
+	case infos[0].Name != name:
+		return benchlib.Info{}, fmt.Errorf("registered as %q, want its directory name", infos[0].Name)
+	}
+	return infos[0], nil
+}
+
+// Command returns a command running the program and arguments in cmd with
+// the current environment minus the variables named in unset. Callers
+// that parse a benchmark's text output unset benchlib.EnvFormat, since a
+// BENCH_FORMAT meant for the benchmarks themselves would change it.
+func Command(cmd []string, unset ...string) *exec.Cmd {
+	c := exec.Command(cmd[0], cmd[1:]...)
+	c.Env = slices.DeleteFunc(os.Environ(), func(kv string) bool {
+		name, _, _ := strings.Cut(kv, "=")
+		return slices.Contains(unset, name)
+	})
+	return c
+}
@@ -0,0 +1,67 @@
+package benchexec
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+func TestDiscover(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"fibonacci", "primes", "c-only"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, file := range []string{"fibonacci/main.go", "primes/main.go", "c-only/main.c", "README.md"} {
+		if err := os.WriteFile(filepath.Join(root, file), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := Discover(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"fibonacci", "primes"}; !slices.Equal(got, want) {
+		t.Errorf("Discover = %v, want %v", got, want)
+	}
+	if _, err := Discover(filepath.Join(root, "missing")); err == nil {
+		t.Error("Discover of a missing directory succeeded")
+	}
+}
+
+// script returns a shell script running body, for use as a benchmark.
+func script(t *testing.T, body string) []string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "bench")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return []string{path}
+}
+
+func TestDescribe(t *testing.T) {
+	primes := `{"name":"primes","category":"numeric","expected":9592}`
+	info, err := Describe("primes", script(t, `echo '`+primes+`'`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (benchlib.Info{Name: "primes", Category: benchlib.CategoryNumeric, Expected: 9592}); info != want {
+		t.Errorf("Describe = %+v, want %+v", info, want)
+	}
+
+	for name, body := range map[string]string{
+		"misnamed":    `echo '` + primes + `'`,
+		"two":         `echo '` + primes + `'; echo '` + primes + `'`,
+		"garbled":     `echo 'RESULT: 1'`,
+		"no describe": `echo 'flag provided but not defined: -describe' >&2; exit 2`,
+	} {
+		if _, err := Describe(name, script(t, body)); err == nil {
+			t.Errorf("Describe(%s) succeeded", name)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package result
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+// BaselineSchemaVersion is the baseline file format written by this
+// version of the tools. ReadBaseline rejects any other version.
+const BaselineSchemaVersion = 1
+
+// BaselineEntry is one benchmark's stored measurement.
+type BaselineEntry struct {
+	StartupUS int64 `json:"startup_us"`
+	ComputeUS int64 `json:"compute_us"`
+	Result    int64 `json:"result"`
+}
+
+// Baseline is a persisted set of results, written by `baseline save` and
+// read by `baseline show` and `compare`.
+type Baseline struct {
+	SchemaVersion int                      `json:"schema_version"`
+	Timestamp     time.Time                `json:"timestamp"`
+	Host          benchlib.Host            `json:"host"`
+	Benchmarks    map[string]BaselineEntry `json:"benchmarks"`
+}
+
+// NewBaseline builds a baseline from combined runall results. Every
+// benchmark must have succeeded, since a baseline with holes cannot be
+// compared against.
+func NewBaseline(c Combined, host benchlib.Host, at time.Time) (Baseline, error) {
+	b := Baseline{
+		SchemaVersion: BaselineSchemaVersion,
+		Timestamp:     at.UTC(),
+		Host:          host,
+		Benchmarks:    make(map[string]BaselineEntry, len(c.Benchmarks)),
+	}
+	for _, e := range c.Benchmarks {
+		if e.Error != "" {
+			return Baseline{}, fmt.Errorf("benchmark %s failed: %s", e.Benchmark, e.Error)
+		}
+		if _, dup := b.Benchmarks[e.Benchmark]; dup {
+			return Baseline{}, fmt.Errorf("duplicate benchmark %s", e.Benchmark)
+		}
+		b.Benchmarks[e.Benchmark] = BaselineEntry{StartupUS: e.StartupUS, ComputeUS: e.ComputeUS, Result: e.Result}
+	}
+	return b, nil
+}
+
+// WriteBaseline writes b as indented JSON, so stored baselines diff well.
+func WriteBaseline(w io.Writer, b Baseline) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(b)
+}
+
+// ReadBaseline decodes a baseline file and checks its schema version.
+func ReadBaseline(r io.Reader) (Baseline, error) {
+	var b Baseline
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return Baseline{}, fmt.Errorf("decoding baseline: %w", err)
+	}
+	if b.SchemaVersion != BaselineSchemaVersion {
+		return Baseline{}, fmt.Errorf("unsupported baseline schema_version %d (want %d)", b.SchemaVersion, BaselineSchemaVersion)
+	}
+	return b, nil
+}
+
+// ComputeUS returns the benchmark-name to compute_us map that compare
+// works on.
+func (b Baseline) ComputeUS() map[string]int64 {
+	m := make(map[string]int64, len(b.Benchmarks))
+	for name, e := range b.Benchmarks {
+		m[name] = e.ComputeUS
+	}
+	return m
+}
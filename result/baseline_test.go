@@ -0,0 +1,70 @@
+package result
+
+import (
+	"bytes"
+	"maps"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/paiml/ruchy-docker/benchlib"
+)
+
+var testHost = benchlib.Host{CPUModel: "test cpu", NumCPU: 8, GOARCH: "amd64", GOOS: "linux", GoVersion: "go1.23.0"}
+
+func TestBaselineRoundTrip(t *testing.T) {
+	c := Combined{Benchmarks: []Entry{
+		{Benchmark: "primes", StartupUS: 12, ComputeUS: 340, Result: 9592},
+		{Benchmark: "fibonacci", StartupUS: 5, ComputeUS: 51861, Result: 9227465},
+	}}
+	at := time.Date(2026, 10, 14, 9, 30, 0, 0, time.FixedZone("CEST", 2*3600))
+	b, err := NewBaseline(c, testHost, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteBaseline(&buf, b); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"schema_version": 1`, `"timestamp": "2026-10-14T07:30:00Z"`, `"cpu_model": "test cpu"`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("baseline file missing %s:\n%s", want, buf.String())
+		}
+	}
+
+	got, err := ReadBaseline(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.SchemaVersion != b.SchemaVersion || !got.Timestamp.Equal(at) || got.Host != testHost ||
+		!maps.Equal(got.Benchmarks, b.Benchmarks) {
+		t.Errorf("round trip = %+v, want %+v", got, b)
+	}
+	if want := map[string]int64{"primes": 340, "fibonacci": 51861}; !maps.Equal(got.ComputeUS(), want) {
+		t.Errorf("ComputeUS = %v, want %v", got.ComputeUS(), want)
+	}
+}
+
+func TestReadBaselineRejectsUnknownSchema(t *testing.T) {
+	for _, input := range []string{
+		`{"schema_version": 2, "benchmarks": {}}`,
+		`{"benchmarks": {}}`, // no version at all
+	} {
+		_, err := ReadBaseline(strings.NewReader(input))
+		if err == nil || !strings.Contains(err.Error(), "schema_version") {
+			t.Errorf("ReadBaseline(%s) error = %v, want schema_version error", input, err)
+		}
+	}
+}
+
+func TestNewBaselineRejectsFailures(t *testing.T) {
+	c := Combined{Benchmarks: []Entry{{Benchmark: "mutex", Error: "exit status 1"}}}
+	if _, err := NewBaseline(c, testHost, time.Now()); err == nil {
+		t.Error("NewBaseline accepted a failed benchmark")
+	}
+	c = Combined{Benchmarks: []Entry{{Benchmark: "primes"}, {Benchmark: "primes"}}}
+	if _, err := NewBaseline(c, testHost, time.Now()); err == nil {
+		t.Error("NewBaseline accepted a duplicate benchmark")
+	}
+}
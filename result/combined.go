@@ -0,0 +1,39 @@
+package result
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Entry is one benchmark in a combined results document. The field names
+// match the per-benchmark --format=json output. Error is set, and the
+// numeric fields are zero, when the benchmark failed.
+type Entry struct {
+	Benchmark string `json:"benchmark"`
+	StartupUS int64  `json:"startup_us"`
+	ComputeUS int64  `json:"compute_us"`
+	Result    int64  `json:"result"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Combined is the document written by `runall --format=json` and read by
+// the reporting tools. GeomeanComputeUS is the geometric mean of the
+// successful benchmarks' compute times, or zero when none succeeded.
+type Combined struct {
+	Benchmarks       []Entry `json:"benchmarks"`
+	GeomeanComputeUS float64 `json:"geomean_compute_us,omitempty"`
+}
+
+// DecodeCombined reads a Combined document from r. Unknown fields are
+// rejected so that a file of a different shape, such as a bare
+// name-to-time map, is not silently read as empty.
+func DecodeCombined(r io.Reader) (Combined, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var c Combined
+	if err := dec.Decode(&c); err != nil {
+		return Combined{}, fmt.Errorf("decoding combined results: %w", err)
+	}
+	return c, nil
+}
@@ -0,0 +1,37 @@
+package result
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeCombined(t *testing.T) {
+	input := `{"benchmarks":[` +
+		`{"benchmark":"primes","startup_us":12,"compute_us":340,"result":9592},` +
+		`{"benchmark":"fibonacci","startup_us":0,"compute_us":0,"result":0,"error":"exit status 1"}]}`
+
+	c, err := DecodeCombined(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Entry{
+		{Benchmark: "primes", StartupUS: 12, ComputeUS: 340, Result: 9592},
+		{Benchmark: "fibonacci", Error: "exit status 1"},
+	}
+	if len(c.Benchmarks) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(c.Benchmarks), len(want))
+	}
+	for i := range want {
+		if c.Benchmarks[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, c.Benchmarks[i], want[i])
+		}
+	}
+}
+
+func TestDecodeCombinedRejectsOtherShapes(t *testing.T) {
+	for _, input := range []string{`{"primes": 340}`, `[1, 2]`, `not json`} {
+		if _, err := DecodeCombined(strings.NewReader(input)); err == nil {
+			t.Errorf("DecodeCombined(%q) succeeded, want error", input)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Package result parses the standardized output emitted by the benchmark
+// programs:
+//
+//	STARTUP_TIME_US: <microseconds>
+//	COMPUTE_TIME_US: <microseconds>
+//	RESULT: <integer>
+//
+// A benchmark run with --result-base=hex prints the result as
+//
+//	RESULT_HEX: 0x<16 hex digits>
+//
+// instead, its int64 bit pattern as an unsigned number. Lines that are not
+// one of the recognized keys are ignored, so the output may be interleaved
+// with log noise.
+package result
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Output keys recognized by Parse.
+const (
+	KeyStartup = "STARTUP_TIME_US"
+	KeyCompute = "COMPUTE_TIME_US"
+	KeyResult  = "RESULT"
+	// KeyResultHex is the hexadecimal form of KeyResult.
+	KeyResultHex = "RESULT_HEX"
+)
+
+// Result holds the values parsed from one benchmark run.
+type Result struct {
+	StartupUS int64
+	ComputeUS int64
+	Result    int64
+}
+
+// Parse reads benchmark output from r and extracts the standardized fields.
+//
+// It returns an error if any key is missing, appears more than once, or has
+// a value that is not a base-10 integer. The result may be given as RESULT
+// or as RESULT_HEX, a hexadecimal uint64 with an optional 0x prefix, but not
+// both.
+func Parse(r io.Reader) (Result, error) {
+	var res Result
+	fields := map[string]*int64{
+		KeyStartup: &res.StartupUS,
+		KeyCompute: &res.ComputeUS,
+		KeyResult:  &res.Result,
+	}
+	seen := make(map[string]bool, len(fields))
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		field := key
+		if key == KeyResultHex {
+			field = KeyResult
+		}
+		dst, known := fields[field]
+		if !known {
+			continue
+		}
+		if seen[field] {
+			return Result{}, fmt.Errorf("line %d: duplicate %s", lineNo, field)
+		}
+		n, err := parseValue(key, strings.TrimSpace(value))
+		if err != nil {
+			return Result{}, fmt.Errorf("line %d: invalid %s value %q: %w", lineNo, key, strings.TrimSpace(value), err)
+		}
+		*dst = n
+		seen[field] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return Result{}, fmt.Errorf("reading benchmark output: %w", err)
+	}
+
+	var missing []string
+	for _, key := range []string{KeyStartup, KeyCompute, KeyResult} {
+		if !seen[key] {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return Result{}, fmt.Errorf("benchmark output missing %s", strings.Join(missing, ", "))
+	}
+	return res, nil
+}
+
+// parseValue parses the value of key: hexadecimal for KeyResultHex,
+// decimal otherwise.
+func parseValue(key, value string) (int64, error) {
+	if key != KeyResultHex {
+		return strconv.ParseInt(value, 10, 64)
+	}
+	digits := strings.TrimPrefix(strings.TrimPrefix(value, "0x"), "0X")
+	u, err := strconv.ParseUint(digits, 16, 64)
+	return int64(u), err
+}
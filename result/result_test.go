@@ -0,0 +1,116 @@
+package result
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Result
+		wantErr string
+	}{
+		{
+			name:  "normal run",
+			input: "STARTUP_TIME_US: 8234\nCOMPUTE_TIME_US: 23891\nRESULT: 9227465\n",
+			want:  Result{StartupUS: 8234, ComputeUS: 23891, Result: 9227465},
+		},
+		{
+			name: "interleaved stderr noise",
+			input: "warming up caches...\n" +
+				"STARTUP_TIME_US: 12\n" +
+				"runtime: note: GC forced\n" +
+				"COMPUTE_TIME_US: 345\n" +
+				"\n" +
+				"RESULT: 9592\n" +
+				"done\n",
+			want: Result{StartupUS: 12, ComputeUS: 345, Result: 9592},
+		},
+		{
+			name: "three-phase output",
+			input: "STARTUP_TIME_US: 12\nSTARTUP_TIME_NS: 12000\n" +
+				"PARSE_TIME_US: 40\nPARSE_TIME_NS: 40000\n" +
+				"COMPUTE_TIME_US: 345\nCOMPUTE_TIME_NS: 345000\nRESULT: 9592\n",
+			want: Result{StartupUS: 12, ComputeUS: 345, Result: 9592},
+		},
+		{
+			name:  "negative result without trailing newline",
+			input: "STARTUP_TIME_US: 0\nCOMPUTE_TIME_US: 1\nRESULT: -7",
+			want:  Result{StartupUS: 0, ComputeUS: 1, Result: -7},
+		},
+		{
+			name:  "hex result",
+			input: "STARTUP_TIME_US: 1\nCOMPUTE_TIME_US: 2\nRESULT_HEX: 0x00000000000025f8\n",
+			want:  Result{StartupUS: 1, ComputeUS: 2, Result: 9720},
+		},
+		{
+			// The sha256 benchmark's RESULT, -6232655581607151700.
+			name:  "hex result above int64 max",
+			input: "STARTUP_TIME_US: 1\nCOMPUTE_TIME_US: 2\nRESULT_HEX: 0xa98128c542f337ac\n",
+			want:  Result{StartupUS: 1, ComputeUS: 2, Result: -6232655581607151700},
+		},
+		{
+			name:  "hex result without prefix",
+			input: "STARTUP_TIME_US: 1\nCOMPUTE_TIME_US: 2\nRESULT_HEX: FF\n",
+			want:  Result{StartupUS: 1, ComputeUS: 2, Result: 255},
+		},
+		{
+			name:    "decimal and hex result",
+			input:   "STARTUP_TIME_US: 1\nCOMPUTE_TIME_US: 2\nRESULT: 255\nRESULT_HEX: 0xff\n",
+			wantErr: "line 4: duplicate RESULT",
+		},
+		{
+			name:    "invalid hex result",
+			input:   "STARTUP_TIME_US: 1\nCOMPUTE_TIME_US: 2\nRESULT_HEX: 0x1g\n",
+			wantErr: `line 3: invalid RESULT_HEX value "0x1g"`,
+		},
+		{
+			name:    "hex result wider than 64 bits",
+			input:   "STARTUP_TIME_US: 1\nCOMPUTE_TIME_US: 2\nRESULT_HEX: 0x10000000000000000\n",
+			wantErr: "invalid RESULT_HEX value",
+		},
+		{
+			name:    "truncated output",
+			input:   "STARTUP_TIME_US: 12\nCOMPUTE_TI",
+			wantErr: "missing COMPUTE_TIME_US, RESULT",
+		},
+		{
+			name:    "empty output",
+			input:   "",
+			wantErr: "missing STARTUP_TIME_US, COMPUTE_TIME_US, RESULT",
+		},
+		{
+			name:    "duplicate key",
+			input:   "STARTUP_TIME_US: 1\nCOMPUTE_TIME_US: 2\nCOMPUTE_TIME_US: 3\nRESULT: 4\n",
+			wantErr: "line 3: duplicate COMPUTE_TIME_US",
+		},
+		{
+			name:    "invalid value",
+			input:   "STARTUP_TIME_US: 1\nCOMPUTE_TIME_US: fast\nRESULT: 4\n",
+			wantErr: `line 2: invalid COMPUTE_TIME_US value "fast"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil {
+					t.Fatalf("Parse() = %+v, want error containing %q", got, tt.wantErr)
+				}
+				if !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() error = %q, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}